@@ -1,11 +1,20 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime/debug"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/emontenegr/ClaudeCodeArchitect/internal/compiler"
 	"github.com/emontenegr/ClaudeCodeArchitect/internal/completion"
@@ -15,10 +24,20 @@ import (
 	"github.com/emontenegr/ClaudeCodeArchitect/internal/skill"
 	"github.com/emontenegr/ClaudeCodeArchitect/internal/validator"
 	versionpkg "github.com/emontenegr/ClaudeCodeArchitect/internal/version"
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/workspace"
+	"golang.org/x/term"
 )
 
 var version = "dev" // set via ldflags: -X main.version=
 
+// Exit codes for cca validate
+const (
+	exitFindings   = 1 // Structural checks failed or findings at or above --fail-on
+	exitFailure    = 2 // cca or the provider failed
+	exitCancelled  = 3 // Declined at the size prompt or interrupted
+	exitOverBudget = 4 // Refused: the estimated run exceeds budget.tokens or budget.cost
+)
+
 func getVersion() string {
 	// ldflags takes priority (goreleaser sets this)
 	if version != "dev" && version != "" {
@@ -63,6 +82,24 @@ func main() {
 		err = runList()
 	case "skill":
 		err = runSkill()
+	case "deps":
+		err = runDeps()
+	case "attrs":
+		err = runAttrs()
+	case "stats":
+		err = runStats()
+	case "rules":
+		err = runRules()
+	case "fix":
+		err = runFix()
+	case "ask":
+		err = runAsk()
+	case "cache":
+		err = runCache()
+	case "prompts":
+		err = runPrompts()
+	case "schema":
+		err = runSchema()
 	case "completion":
 		runCompletion()
 		return
@@ -79,6 +116,9 @@ func main() {
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if command == "validate" {
+			os.Exit(exitFailure)
+		}
 		os.Exit(1)
 	}
 }
@@ -91,11 +131,37 @@ Usage:
   cca compile --section <name>     Compile specific section only
   cca validate                     Full validation (structural + Claude semantic)
   cca validate --quick             Structural checks only (no Claude)
-  cca validate --ultra             Enhanced validation (3x + synthesis)
+  cca validate --ultra             Enhanced validation (3 runs + consensus scoring)
+  cca validate --runs <n>          Ultra validation with n parallel runs
   cca validate --yes               Skip confirmation for large specs
+  cca validate --since <ref>       Validate only sections changed since a commit
+  cca validate --section <name>    Validate one section and its subsections
+  cca validate --file <path>       Validate one spec file and the files it includes
+  cca validate ./... [--jobs <n>]  Validate every spec under a directory tree
+  cca validate --record            Also append the result to .cca/history.jsonl
+  cca validate --history           Show per-rule trends and regressions from recorded runs
+  cca validate --at <ref>          Validate the spec as of a commit and record it (repeatable)
+  cca validate --dry-run           Print the prompts and token estimate without calling the provider
   cca diff [commit]                Diff compiled output vs commit (default: HEAD~1)
   cca impact <attribute>           Show sections using attribute
   cca list                         List all sections in spec
+  cca deps [--json]                List dependencies and their version status
+  cca attrs [--json]               List attributes with values and reference counts
+  cca attrs --check [--fail-on <s>] Report undefined, unused, redefined and hard-coded attributes
+  cca stats [--json]               Spec size, tokens and native findings per section and file
+  cca rules [--json]               List every rule with its kind, severity and status
+  cca rules explain <id>           Show a rule's rationale, examples and how to suppress it
+  cca rules --markdown             Print the rule table used in docs/validation.md
+  cca fix                          Propose and apply edits for recorded findings
+  cca fix --yes                    Apply every proposed edit without prompting
+  cca fix --rule <id>              Only fix findings for one rule
+  cca ask "<question>"             Answer from the spec, citing sections and file:line
+  cca ask "<question>" --top <n>   Search the n most relevant sections (default 5)
+  cca cache                        Show semantic validation cache size
+  cca cache prune                  Remove cache entries older than 7 days
+  cca cache prune --all            Remove all cache entries
+  cca prompts export [dir]         Write default prompt templates to dir (default: prompts)
+  cca schema export [file]         Write the default spec schema (default: spec-schema.yaml)
   cca skill                        Install/update Claude Code skill
   cca skill --global               Install to ~/.claude/skills (all projects)
   cca completion [bash|zsh|fish]   Generate shell completion script
@@ -104,13 +170,83 @@ Usage:
 
 Flags:
   --quick, -q     Structural checks only, skip Claude semantic validation
-  --ultra, -u     Enhanced validation (3x parallel + synthesis)
+  --ultra, -u     Enhanced validation (parallel runs + Go-side consensus)
+  --runs <n>      Number of ultra runs (default 3, implies --ultra)
+  --threshold <f> Minimum fraction of runs agreeing on an issue (default 0.5)
+  --synthesize    Also ask the provider to synthesize all ultra runs
   --yes, -y       Skip interactive confirmation
   --json          Output JSON (for CI, use with --quick)
+  --since <ref>   Incremental: send changed sections only, reuse cached findings
+  --section <s>   Validate one section (matched like compile --section), with Context for reference
+  --file <path>   Validate one spec file, with Context for reference
+  --provider <p>  LLM provider: claude-cli (default), anthropic, openai
+  --model <m>     Model passed to the provider
+  --timeout <d>   Per-call provider timeout (default 10m)
+  --retries <n>   Retries for transient provider failures (default 2, 0 disables)
+  --quorum <n>    Minimum successful ultra runs (default: majority)
+  --no-cache      Ignore cached semantic results in .cca/cache
+  --no-chunk      Send specs over 50KB in one call instead of chunking
+  --fail-on <s>   Exit 1 on findings at or above: error (default), warning, info, none
+  --jobs <n>      Specs validated at once with ./... (default 4)
+  --record        Append the run to .cca/history.jsonl (always on with history: true)
+  --dry-run       Render every prompt the run would send (ultra: per run and synthesis skeleton)
+  --out <dir>     With --dry-run, save each prompt to <dir>/<name>.md instead of printing it
+
+Exit codes (validate):
+  0  Passed
+  1  Structural failures or findings at or above --fail-on
+  2  Tool or provider failure
+  3  Cancelled
+  4  Refused: the estimated run exceeds budget.tokens or budget.cost
+  With ./..., the highest code across all specs
+
+Environment:
+  CCA_RECORD=<dir>  Save every provider reply to <dir>, keyed by prompt hash
+  CCA_REPLAY=<dir>  Answer from replies saved with CCA_RECORD; unknown prompts fail
+
+Multiple specs:
+  compile, validate, list and diff accept <dir>/... to run on every spec below <dir>
+  (directories with .spec.yaml or a convention manifest; hidden dirs, node_modules
+  and vendor are skipped). Semantic validation of several specs needs --yes.
 
 Configuration:
   Create .spec.yaml in your project root:
     spec: ./MANIFEST.adoc
+    provider:                   # optional, defaults to the claude CLI
+      name: anthropic           # claude-cli | anthropic | openai
+      model: <model-id>
+      timeout: 5m
+      retries: 2                # transient failures, exponential backoff
+      retry_delay: 2s
+    ultra:                      # optional --ultra tuning
+      runs: 5
+      threshold: 0.6
+      quorum: 3                 # runs that must succeed
+      synthesize: false
+    chunks:                     # optional, specs over 50KB are validated in chunks
+      max_size: 30000           # bytes per chunk
+      concurrency: 4
+    fail_on: warning            # optional, default error
+    pricing:                    # optional, USD per million tokens (by model or provider)
+      <model-id>: {input: 3, output: 15}
+    budget:                     # optional, refuse (not prompt) runs estimated above this
+      tokens: 400000
+      cost: 2.50
+    rules:                      # optional project-specific checklist rules
+      custom:
+        - id: queue-dlq
+          description: Every queue has a DLQ with retention
+          examples: ["orders queue -> orders-dlq, 14 day retention"]
+          severity: error       # default warning
+      severity:                 # override built-in rule severities
+        no-weak-language: error
+      disabled: [file-tree]     # skip rules, native or semantic (see cca rules)
+    prompts:                    # optional template overrides (see cca prompts export)
+      validate: prompts/validate.tmpl
+    schema:                     # optional required sections and fields
+      kind: service             # service | cli | library
+      file: spec-schema.yaml    # replaces the embedded schema (see cca schema export)
+    history: true               # optional, record every validate run (see --history)
 
   Or use convention - cca looks for:
     - MANIFEST.adoc
@@ -123,37 +259,50 @@ Examples:
   cca validate                          # Full validation with Claude
   cca validate --quick                  # Fast structural checks only
   cca validate --yes                    # Skip size confirmation (CI/scripts)
+  cca validate --since main             # Re-check only what changed since main
+  cca validate --file api/routes.adoc   # Check the concern file you are editing
+  cca validate --quick ./...            # Structural checks for every spec in a monorepo
+  cca validate --quick --at v1.0        # Back-fill history for a release
+  cca validate --ultra --dry-run        # Inspect ultra prompts and token cost first
+  cca ask "Login rate limit?"           # Cited answer; exits 1 if the spec does not say
   cca diff HEAD~1                       # Compare with previous commit
   cca impact api-p99-latency            # Find attribute usages
+  cca rules explain no-weak-language    # Why a rule exists and how to silence it
 `)
 }
 
 func runCompile() error {
-	specPath, err := config.FindSpec()
-	if err != nil {
-		return err
-	}
-
-	// Check for --section flag
+	// Check for --section flag and a ./... pattern
 	sectionQuery := ""
-	for i, arg := range os.Args {
-		if arg == "--section" && i+1 < len(os.Args) {
-			sectionQuery = os.Args[i+1]
-			break
+	pattern := ""
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		if value, ok := flagValue(args, &i, "--section"); ok {
+			sectionQuery = value
+			continue
 		}
-		if strings.HasPrefix(arg, "--section=") {
-			sectionQuery = strings.TrimPrefix(arg, "--section=")
-			break
+		if root, ok := config.SpecPattern(args[i]); ok {
+			pattern = root
 		}
 	}
 
-	var output string
-	if sectionQuery != "" {
-		output, err = compiler.CompileSection(specPath, sectionQuery)
-	} else {
-		output, err = compiler.Compile(specPath)
+	compile := func(specPath string) (string, error) {
+		if sectionQuery != "" {
+			return compiler.CompileSection(specPath, sectionQuery)
+		}
+		return compiler.Compile(specPath)
+	}
+
+	if pattern != "" {
+		return runEach(pattern, compile)
+	}
+
+	specPath, err := config.FindSpec()
+	if err != nil {
+		return err
 	}
 
+	output, err := compile(specPath)
 	if err != nil {
 		return err
 	}
@@ -166,9 +315,104 @@ func runValidate() error {
 	// Parse flags and optional path argument
 	quick := false
 	opts := validator.ValidationOptions{}
+	providerFlags := config.ProviderConfig{}
 	dir := "."
+	pattern := "" // Search root for ./... style arguments
+	jobs := workspace.DefaultConcurrency
+	record := false
+	showHistory := false
+	dryRun := false
+	outDir := ""        // --out: where --dry-run saves prompts
+	var atRefs []string // Commits to back-fill history for
+
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if value, ok := flagValue(args, &i, "--jobs"); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid --jobs %q: must be a number >= 1", value)
+			}
+			jobs = n
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--since"); ok {
+			opts.Since = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--at"); ok {
+			atRefs = append(atRefs, value)
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--section"); ok {
+			opts.Section = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--file"); ok {
+			opts.File = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--out"); ok {
+			outDir = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--fail-on"); ok {
+			if _, err := validator.ParseFailOn(value); err != nil {
+				return err
+			}
+			opts.FailOn = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--provider"); ok {
+			providerFlags.Name = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--model"); ok {
+			providerFlags.Model = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--runs"); ok {
+			runs, err := strconv.Atoi(value)
+			if err != nil || runs < 2 {
+				return fmt.Errorf("invalid --runs %q: must be a number >= 2", value)
+			}
+			opts.Runs = runs
+			opts.Ultra = true
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--threshold"); ok {
+			threshold, err := strconv.ParseFloat(value, 64)
+			if err != nil || threshold <= 0 || threshold > 1 {
+				return fmt.Errorf("invalid --threshold %q: must be in (0, 1]", value)
+			}
+			opts.Threshold = threshold
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--timeout"); ok {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid --timeout %q: %w", value, err)
+			}
+			providerFlags.Timeout = timeout
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--retries"); ok {
+			retries, err := strconv.Atoi(value)
+			if err != nil || retries < 0 {
+				return fmt.Errorf("invalid --retries %q: must be a number >= 0", value)
+			}
+			providerFlags.Retries = &retries
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--quorum"); ok {
+			quorum, err := strconv.Atoi(value)
+			if err != nil || quorum < 1 {
+				return fmt.Errorf("invalid --quorum %q: must be a number >= 1", value)
+			}
+			opts.Quorum = quorum
+			continue
+		}
 
-	for _, arg := range os.Args[2:] {
 		switch arg {
 		case "--quick", "-q":
 			quick = true
@@ -178,65 +422,533 @@ func runValidate() error {
 			opts.Ultra = true
 		case "--json":
 			opts.JSON = true
+		case "--no-cache":
+			opts.NoCache = true
+		case "--no-chunk":
+			opts.NoChunk = true
+		case "--synthesize":
+			opts.Synthesize = true
+		case "--record":
+			record = true
+		case "--history":
+			showHistory = true
+		case "--dry-run":
+			dryRun = true
 		default:
-			if !strings.HasPrefix(arg, "-") {
+			if root, ok := config.SpecPattern(arg); ok {
+				pattern = root
+			} else if !strings.HasPrefix(arg, "-") {
 				dir = arg
 			}
 		}
 	}
 
+	if quick && opts.Since != "" {
+		return fmt.Errorf("--since applies to semantic validation and cannot be combined with --quick")
+	}
+	scoped := opts.Section != "" || opts.File != ""
+	switch {
+	case opts.Section != "" && opts.File != "":
+		return fmt.Errorf("--section and --file cannot be combined")
+	case scoped && opts.Since != "":
+		return fmt.Errorf("--since already limits validation to changed sections and cannot be combined with --section or --file")
+	case scoped && (pattern != "" || showHistory || len(atRefs) > 0 || record):
+		return fmt.Errorf("--section and --file validate part of one spec and cannot be combined with ./..., --history, --at or --record")
+	case dryRun && (quick || pattern != "" || showHistory || len(atRefs) > 0 || record):
+		return fmt.Errorf("--dry-run shows the semantic prompts for one spec and cannot be combined with --quick, ./..., --history, --at or --record")
+	case outDir != "" && !dryRun:
+		return fmt.Errorf("--out saves the prompts of a --dry-run")
+	}
+
+	// Ctrl-C cancels in-flight provider calls instead of killing the process mid-write
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if pattern != "" {
+		if showHistory || len(atRefs) > 0 {
+			return fmt.Errorf("--history and --at apply to one spec and cannot be combined with %s/...", pattern)
+		}
+		if !quick && !opts.SkipConfirm {
+			return fmt.Errorf("validating several specs runs them in parallel and cannot prompt - pass --yes, or --quick for structural checks only")
+		}
+		return validateAll(ctx, pattern, jobs, quick, record, opts, providerFlags)
+	}
+
 	specPath, err := config.FindSpecInDir(dir)
 	if err != nil {
 		return err
 	}
 
-	if quick {
-		result, err := validator.ValidateQuick(specPath)
+	// Replies stream to a terminal; --json and redirected output stay buffered
+	opts.Stream = !opts.JSON && term.IsTerminal(int(os.Stdout.Fd()))
+
+	if showHistory {
+		return showValidationHistory(specPath)
+	}
+	if len(atRefs) > 0 {
+		return validateAtCommits(ctx, dir, specPath, atRefs, quick, opts, providerFlags)
+	}
+	if dryRun {
+		return dryRunValidation(dir, specPath, outDir, opts, providerFlags)
+	}
+
+	outcome, err := validateSpec(ctx, dir, specPath, quick, opts, providerFlags, os.Stdout)
+	if err != nil {
+		return err
+	}
+	if !scoped && historyEnabled(dir, record) {
+		commit, _ := differ.GetCurrentCommit(filepath.Dir(specPath))
+		recordHistory(specPath, specPath, commit, outcome, os.Stdout)
+	}
+	if outcome.ExitCode != 0 {
+		os.Exit(outcome.ExitCode)
+	}
+	return nil
+}
+
+// dryRunValidation prints, or saves to outDir, the prompts a validation would send
+// without calling the provider
+func dryRunValidation(dir, specPath, outDir string, opts validator.ValidationOptions, providerFlags config.ProviderConfig) error {
+	if err := configureValidation(dir, &opts, providerFlags); err != nil {
+		return err
+	}
+	plan, err := validator.PlanDryRun(specPath, opts)
+	if err != nil {
+		return err
+	}
+
+	if outDir != "" {
+		written, err := validator.SaveDryRun(plan, outDir)
+		if err != nil {
+			return err
+		}
+		if !opts.JSON {
+			fmt.Print(validator.FormatDryRunSummary(plan))
+			fmt.Println()
+			for _, path := range written {
+				fmt.Printf("Wrote %s\n", path)
+			}
+			return nil
+		}
+	}
+	if opts.JSON {
+		fmt.Println(validator.FormatDryRunJSON(plan))
+		return nil
+	}
+	fmt.Print(validator.FormatDryRun(plan))
+	return nil
+}
+
+// historyEnabled reports whether runs for the spec in dir are recorded: --record, or history: true in .spec.yaml
+func historyEnabled(dir string, record bool) bool {
+	if record {
+		return true
+	}
+	cfg, err := config.LoadSpecConfigInDir(dir)
+	return err == nil && cfg.History
+}
+
+// recordHistory appends a finished run to historyManifest's history
+// sourceManifest is the spec that was validated (a worktree copy when back-filling)
+func recordHistory(historyManifest, sourceManifest, commit string, outcome workspace.Result, w io.Writer) {
+	result, ok := outcome.Data.(*validator.ValidationResult)
+	if !ok || result.Cancelled {
+		return
+	}
+
+	entry, err := validator.NewHistoryEntry(sourceManifest, result)
+	if err == nil {
+		entry.Commit = commit
+		if commit != "" {
+			entry.CommitTime, _ = differ.GetCommitTime(filepath.Dir(sourceManifest), commit)
+		}
+		err = validator.AppendHistory(historyManifest, entry)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "Warning: failed to record history: %v\n", err)
+	}
+}
+
+// showValidationHistory prints the per-rule trend and exits 1 if a rule regressed since the previous commit
+func showValidationHistory(specPath string) error {
+	entries, err := validator.LoadHistory(specPath)
+	if err != nil {
+		return err
+	}
+
+	trend := validator.BuildTrend(entries, "", 10)
+	fmt.Print(validator.FormatTrend(trend))
+	if len(trend.Regressions) > 0 {
+		os.Exit(exitFindings)
+	}
+	return nil
+}
+
+// validateAtCommits validates the spec as it was at each ref, in a temporary worktree,
+// and records the results in the current spec's history
+func validateAtCommits(ctx context.Context, dir, specPath string, refs []string, quick bool, opts validator.ValidationOptions, providerFlags config.ProviderConfig) error {
+	repo := filepath.Dir(specPath)
+	code := 0
+	for _, ref := range refs {
+		commit, err := differ.ResolveCommit(repo, ref)
 		if err != nil {
 			return err
 		}
+		short, _ := differ.GetCommitShort(repo, commit)
+		fmt.Printf("=== Validating %s (%s) ===\n\n", short, ref)
+
+		outcome, err := validateInWorktree(ctx, dir, specPath, commit, quick, opts, providerFlags)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ref, err)
+		}
+		fmt.Println()
+		code = max(code, outcome.ExitCode)
+		if outcome.Status == workspace.StatusCancelled {
+			break
+		}
+	}
+
+	if code != 0 {
+		os.Exit(code)
+	}
+	return nil
+}
+
+// validateInWorktree checks out commit in a temporary worktree, validates the spec there
+// and records the run in the current spec's history
+func validateInWorktree(ctx context.Context, dir, specPath, commit string, quick bool, opts validator.ValidationOptions, providerFlags config.ProviderConfig) (workspace.Result, error) {
+	repo := filepath.Dir(specPath)
+	worktree, err := differ.CreateWorktree(repo, commit)
+	if err != nil {
+		return workspace.Result{}, err
+	}
+	defer differ.RemoveWorktree(repo, worktree)
+
+	manifest := differ.WorktreeManifestPath(worktree, specPath)
+	if _, err := os.Stat(manifest); err != nil {
+		return workspace.Result{}, fmt.Errorf("spec not found at %s in this commit", filepath.Base(manifest))
+	}
+
+	outcome, err := validateSpec(ctx, dir, manifest, quick, opts, providerFlags, os.Stdout)
+	if err != nil {
+		return workspace.Result{}, err
+	}
+	recordHistory(specPath, manifest, commit, outcome, os.Stdout)
+	return outcome, nil
+}
+
+// validateSpec validates one spec, writing the report to w
+// The returned result carries the exit code and a one-line summary; errors are returned, not recorded
+func validateSpec(ctx context.Context, dir, specPath string, quick bool, opts validator.ValidationOptions, providerFlags config.ProviderConfig, w io.Writer) (workspace.Result, error) {
+	if quick {
+		cfg, err := config.LoadSpecConfigInDir(dir)
+		if err != nil {
+			return workspace.Result{}, err
+		}
+		if err := configureRules(cfg, dir, &opts); err != nil {
+			return workspace.Result{}, err
+		}
+		result, err := validator.ValidateQuick(specPath, opts.Schema)
+		if err != nil {
+			return workspace.Result{}, err
+		}
+		validator.ApplyRuleConfig(result, opts.Severities, opts.Disabled)
+		if opts.Section != "" || opts.File != "" {
+			slice, err := validator.ResolveSlice(specPath, opts.Section, opts.File)
+			if err != nil {
+				return workspace.Result{}, err
+			}
+			slice.Scope(result)
+			if !opts.JSON {
+				fmt.Fprintf(w, "Scoped to %s (%d section(s))\n\n", slice.Target, len(slice.Sections))
+			}
+		}
+		outcome := structuralOutcome(result, opts.FailOn)
 		if opts.JSON {
-			fmt.Println(validator.FormatStructuralChecksJSON(result.StructuralChecks))
+			fmt.Fprintln(w, validator.FormatStructuralChecksJSON(result.StructuralChecks))
 		} else {
-			fmt.Print(validator.FormatStructuralChecks(result.StructuralChecks))
+			fmt.Fprint(w, validator.FormatStructuralChecks(result.StructuralChecks, opts.FailOn))
+			if result.StructuralPassed && outcome.ExitCode != 0 {
+				fmt.Fprintf(w, "\033[31m✗\033[0m %s (--fail-on %s)\n", outcome.Summary, opts.FailOn)
+			}
 		}
-		if !result.StructuralPassed {
-			os.Exit(1)
+		return outcome, nil
+	}
+
+	// Full validation: structural + semantic
+	if err := configureValidation(dir, &opts, providerFlags); err != nil {
+		return workspace.Result{}, err
+	}
+
+	result, err := validator.Validate(ctx, specPath, w, opts)
+	if isOverBudget(err) {
+		fmt.Fprintf(w, "\033[31m✗\033[0m Refused %v\n", err)
+		return workspace.Result{Status: workspace.StatusRefused, ExitCode: exitOverBudget, Summary: "over budget"}, nil
+	}
+	if err != nil {
+		return workspace.Result{}, err
+	}
+
+	if result.Cancelled {
+		return workspace.Result{Status: workspace.StatusCancelled, ExitCode: exitCancelled, Summary: "cancelled", Data: result}, nil
+	}
+	if !result.StructuralPassed {
+		return structuralOutcome(result, opts.FailOn), nil
+	}
+
+	// Analyzer issues count against --fail-on alongside the semantic findings
+	findings := append(validator.AnalyzerFindings(result.StructuralChecks), result.Findings...)
+	outcome := workspace.Result{Status: workspace.StatusPassed, Summary: "no findings", Data: result}
+	if result.SemanticRun || len(findings) > 0 {
+		fmt.Fprintf(w, "Findings: %s\n", validator.FormatSeverityCounts(findings))
+		outcome.Summary = "findings: " + validator.FormatSeverityCounts(findings)
+	}
+	if n := validator.CountAtOrAbove(findings, opts.FailOn); n > 0 {
+		fmt.Fprintf(w, "\033[31m✗\033[0m %d finding(s) at or above %s severity (--fail-on %s)\n", n, opts.FailOn, opts.FailOn)
+		outcome.Status, outcome.ExitCode = workspace.StatusFindings, exitFindings
+	}
+	return outcome, nil
+}
+
+// isOverBudget reports whether err refuses a run estimated over the budget
+func isOverBudget(err error) bool {
+	var overBudget *validator.BudgetError
+	return errors.As(err, &overBudget)
+}
+
+// exitIfOverBudget prints a refused over-budget call and exits 4
+func exitIfOverBudget(err error) {
+	if isOverBudget(err) {
+		fmt.Printf("\033[31m✗\033[0m Refused %v\n", err)
+		os.Exit(exitOverBudget)
+	}
+}
+
+// structuralOutcome summarizes the structural phase of a result
+// Analyzer issues fail it only at or above failOn
+func structuralOutcome(result *validator.ValidationResult, failOn string) workspace.Result {
+	failed := 0
+	for _, check := range result.StructuralChecks {
+		if !check.Passed {
+			failed++
 		}
-		return nil
 	}
+	if failed > 0 {
+		return workspace.Result{Status: workspace.StatusFindings, ExitCode: exitFindings, Summary: fmt.Sprintf("%d structural check(s) failed", failed), Data: result}
+	}
+	if n := validator.CountAtOrAbove(validator.AnalyzerFindings(result.StructuralChecks), failOn); n > 0 {
+		return workspace.Result{Status: workspace.StatusFindings, ExitCode: exitFindings, Summary: fmt.Sprintf("%d analyzer issue(s) at or above %s severity", n, failOn), Data: result}
+	}
+	return workspace.Result{Status: workspace.StatusPassed, Summary: fmt.Sprintf("%d structural checks passed", len(result.StructuralChecks)), Data: result}
+}
 
-	// Full validation: structural + Claude
-	result, err := validator.Validate(specPath, os.Stdout, opts)
+// validateAll validates every spec under root in parallel and prints a combined report
+// The exit code is the most severe of the per-spec exit codes
+func validateAll(ctx context.Context, root string, jobs int, quick, record bool, opts validator.ValidationOptions, providerFlags config.ProviderConfig) error {
+	specs, err := findSpecs(root)
 	if err != nil {
 		return err
 	}
 
-	if !result.StructuralPassed || result.Cancelled {
-		os.Exit(1)
+	results := workspace.Run(ctx, root, specs, jobs, func(ctx context.Context, spec config.SpecLocation, w io.Writer) workspace.Result {
+		outcome, err := validateSpec(ctx, spec.Dir, spec.Manifest, quick, opts, providerFlags, w)
+		if err != nil {
+			return workspace.Failed(err, exitFailure)
+		}
+		if historyEnabled(spec.Dir, record) {
+			commit, _ := differ.GetCurrentCommit(spec.Dir)
+			recordHistory(spec.Manifest, spec.Manifest, commit, outcome, w)
+		}
+		return outcome
+	})
+
+	if opts.JSON {
+		fmt.Println(workspace.FormatReportJSON(results))
+	} else {
+		fmt.Print(workspace.FormatOutputs(results))
+		fmt.Printf("\n=== Summary ===\n\n")
+		fmt.Print(workspace.FormatReport(results))
 	}
 
+	if code := workspace.ExitCode(results); code != 0 {
+		os.Exit(code)
+	}
 	return nil
 }
 
-func runDiff() error {
-	specPath, err := config.FindSpec()
+// findSpecs discovers the specs under root, failing when there are none
+func findSpecs(root string) ([]config.SpecLocation, error) {
+	specs, err := config.FindSpecs(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no specs found under %s (looked for .spec.yaml and MANIFEST.adoc)", root)
+	}
+	return specs, nil
+}
+
+// runEach runs a command that prints text over every spec under root, printing each
+// spec's output under a header. Specs that fail are listed after the output
+func runEach(root string, task func(specPath string) (string, error)) error {
+	specs, err := findSpecs(root)
+	if err != nil {
+		return err
+	}
+
+	results := workspace.Run(context.Background(), root, specs, workspace.DefaultConcurrency, func(_ context.Context, spec config.SpecLocation, w io.Writer) workspace.Result {
+		output, err := task(spec.Manifest)
+		if err != nil {
+			return workspace.Failed(err, 1)
+		}
+		fmt.Fprint(w, output)
+		return workspace.Result{Status: workspace.StatusPassed}
+	})
+
+	fmt.Print(workspace.FormatOutputs(results))
+	if failed := workspace.Count(results, workspace.StatusFailed); failed > 0 {
+		for _, r := range results {
+			if r.Status == workspace.StatusFailed {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", r.Spec, r.Error)
+			}
+		}
+		return fmt.Errorf("%d of %d spec(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+// configureValidation applies .spec.yaml in dir to opts: provider, prompt overrides
+// and custom rules. Non-empty fields in providerFlags take precedence over the file
+func configureValidation(dir string, opts *validator.ValidationOptions, providerFlags config.ProviderConfig) error {
+	cfg, err := config.LoadSpecConfigInDir(dir)
+	if err != nil {
+		return err
+	}
+
+	providerCfg := cfg.Provider
+	if providerFlags.Name != "" && providerFlags.Name != providerCfg.Name {
+		// Switching provider on the command line discards file settings for the other provider
+		providerCfg = config.ProviderConfig{Name: providerFlags.Name}
+	}
+	if providerFlags.Model != "" {
+		providerCfg.Model = providerFlags.Model
+	}
+	if providerFlags.Timeout != 0 {
+		providerCfg.Timeout = providerFlags.Timeout
+	}
+	if providerFlags.Retries != nil {
+		providerCfg.Retries = providerFlags.Retries
+	}
+
+	opts.Provider, err = validator.NewProvider(providerCfg)
 	if err != nil {
 		return err
 	}
 
-	// Get target commit (default: HEAD~1)
+	// Ultra settings from the file apply unless given on the command line
+	if opts.Runs == 0 {
+		opts.Runs = cfg.Ultra.Runs
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = cfg.Ultra.Threshold
+	}
+	if opts.Quorum == 0 {
+		opts.Quorum = cfg.Ultra.Quorum
+	}
+
+	opts.ChunkSize = cfg.Chunks.MaxSize
+	opts.ChunkConcurrency = cfg.Chunks.Concurrency
+	opts.NoChunk = opts.NoChunk || cfg.Chunks.Disabled
+	opts.Synthesize = opts.Synthesize || cfg.Ultra.Synthesize
+
+	opts.Pricing = cfg.Pricing
+	opts.Budget = cfg.Budget
+	if err := configureRules(cfg, dir, opts); err != nil {
+		return err
+	}
+
+	opts.Prompts, err = validator.NewPromptConfig(cfg, dir)
+	return err
+}
+
+// configureRules applies the schema, rule severities, disabled rules and fail-on threshold
+// from .spec.yaml, which the quick path needs as well
+func configureRules(cfg *config.SpecConfig, dir string, opts *validator.ValidationOptions) error {
+	var err error
+	if opts.FailOn == "" {
+		opts.FailOn = cfg.FailOn
+	}
+	if opts.FailOn, err = validator.ParseFailOn(opts.FailOn); err != nil {
+		return err
+	}
+	if opts.Severities, err = validator.RuleSeverities(cfg); err != nil {
+		return err
+	}
+	if opts.Disabled, err = validator.DisabledRules(cfg); err != nil {
+		return err
+	}
+	opts.Schema, err = validator.LoadSpecSchema(cfg, dir)
+	return err
+}
+
+// loadSchema loads the spec schema configured in dir's .spec.yaml
+func loadSchema(dir string) (*validator.SpecSchema, error) {
+	cfg, err := config.LoadSpecConfigInDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return validator.LoadSpecSchema(cfg, dir)
+}
+
+// flagValue reads a flag given as "--name value" or "--name=value" at args[*i]
+// For the two-argument form it advances *i past the value
+func flagValue(args []string, i *int, name string) (string, bool) {
+	arg := args[*i]
+	if strings.HasPrefix(arg, name+"=") {
+		return strings.TrimPrefix(arg, name+"="), true
+	}
+	if arg == name && *i+1 < len(args) {
+		*i++
+		return args[*i], true
+	}
+	return "", false
+}
+
+func runDiff() error {
+	// Get target commit (default: HEAD~1) and an optional ./... pattern
 	targetCommit := "HEAD~1"
-	if len(os.Args) > 2 {
-		targetCommit = os.Args[2]
+	pattern := ""
+	for _, arg := range os.Args[2:] {
+		if root, ok := config.SpecPattern(arg); ok {
+			pattern = root
+		} else {
+			targetCommit = arg
+		}
+	}
+
+	diff := func(specPath string) (string, error) {
+		result, err := differ.DiffCompiled(specPath, targetCommit)
+		if err != nil {
+			return "", err
+		}
+		return differ.FormatDiffResult(result) + "\n", nil
+	}
+
+	if pattern != "" {
+		return runEach(pattern, diff)
+	}
+
+	specPath, err := config.FindSpec()
+	if err != nil {
+		return err
 	}
 
-	result, err := differ.DiffCompiled(specPath, targetCommit)
+	output, err := diff(specPath)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(differ.FormatDiffResult(result))
+	fmt.Print(output)
 	return nil
 }
 
@@ -263,18 +975,634 @@ func runImpact() error {
 }
 
 func runList() error {
+	list := func(specPath string) (string, error) {
+		sections, err := compiler.ListSections(specPath)
+		if err != nil {
+			return "", err
+		}
+		return compiler.FormatSectionList(sections), nil
+	}
+
+	if len(os.Args) > 2 {
+		if root, ok := config.SpecPattern(os.Args[2]); ok {
+			return runEach(root, list)
+		}
+	}
+
 	specPath, err := config.FindSpec()
 	if err != nil {
 		return err
 	}
 
-	sections, err := compiler.ListSections(specPath)
+	output, err := list(specPath)
 	if err != nil {
 		return err
 	}
 
 	fmt.Print("Sections in specification:\n\n")
-	fmt.Print(compiler.FormatSectionList(sections))
+	fmt.Print(output)
+	return nil
+}
+
+func runDeps() error {
+	dir := "."
+	jsonOutput := false
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		default:
+			if !strings.HasPrefix(arg, "-") {
+				dir = arg
+			}
+		}
+	}
+
+	specPath, err := config.FindSpecInDir(dir)
+	if err != nil {
+		return err
+	}
+
+	src, err := validator.LoadSpecSources(specPath)
+	if err != nil {
+		return err
+	}
+	inventory := validator.BuildDependencyInventory(validator.ExtractDependencies(src))
+
+	if jsonOutput {
+		fmt.Println(validator.FormatDependencyJSON(inventory))
+		return nil
+	}
+
+	fmt.Print(validator.FormatDependencyTable(inventory))
+	return nil
+}
+
+func runAttrs() error {
+	dir := "."
+	check := false
+	jsonOutput := false
+	failOn := ""
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		if value, ok := flagValue(args, &i, "--fail-on"); ok {
+			if _, err := validator.ParseFailOn(value); err != nil {
+				return err
+			}
+			failOn = value
+			continue
+		}
+		switch args[i] {
+		case "--check":
+			check = true
+		case "--json":
+			jsonOutput = true
+		default:
+			if !strings.HasPrefix(args[i], "-") {
+				dir = args[i]
+			}
+		}
+	}
+
+	specPath, err := config.FindSpecInDir(dir)
+	if err != nil {
+		return err
+	}
+
+	src, err := validator.LoadSpecSources(specPath)
+	if err != nil {
+		return err
+	}
+
+	if !check {
+		summaries := validator.SummarizeAttributes(src)
+		if jsonOutput {
+			fmt.Println(validator.FormatAttributeSummaryJSON(summaries))
+		} else {
+			fmt.Print(validator.FormatAttributeSummary(summaries))
+		}
+		return nil
+	}
+
+	// Rule severities, disabled rules and the fail-on threshold match cca validate
+	cfg, err := config.LoadSpecConfigInDir(dir)
+	if err != nil {
+		return err
+	}
+	opts := validator.ValidationOptions{FailOn: failOn}
+	if err := configureRules(cfg, dir, &opts); err != nil {
+		return err
+	}
+
+	issues := validator.ApplyIssueConfig(validator.AttributeIssues(src), opts.Severities, opts.Disabled)
+	if jsonOutput {
+		fmt.Println(validator.FormatIssuesJSON(issues))
+	} else if len(issues) == 0 {
+		fmt.Println("No attribute issues found.")
+	} else {
+		fmt.Print(validator.FormatIssues(issues))
+	}
+	findings := validator.AnalyzerFindings([]validator.StructuralCheck{{Issues: issues}})
+	if n := validator.CountAtOrAbove(findings, opts.FailOn); n > 0 {
+		return fmt.Errorf("%d attribute issue(s) at or above %s severity (--fail-on %s)", n, opts.FailOn, opts.FailOn)
+	}
+	return nil
+}
+
+func runStats() error {
+	dir := "."
+	jsonOutput := false
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		default:
+			if !strings.HasPrefix(arg, "-") {
+				dir = arg
+			}
+		}
+	}
+
+	specPath, err := config.FindSpecInDir(dir)
+	if err != nil {
+		return err
+	}
+
+	src, err := validator.LoadSpecSources(specPath)
+	if err != nil {
+		return err
+	}
+	if src.Schema, err = loadSchema(dir); err != nil {
+		return err
+	}
+
+	// Token counts use the compiled spec when asciidoctor is available
+	compiled := ""
+	if compiler.IsAsciidoctorAvailable() {
+		compiled, _ = compiler.Compile(specPath)
+	}
+
+	stats, err := validator.BuildStats(src, compiled)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		fmt.Println(validator.FormatStatsJSON(stats))
+		return nil
+	}
+	fmt.Print(validator.FormatStats(stats))
+	return nil
+}
+
+func runRules() error {
+	args := os.Args[2:]
+	explain := len(args) > 0 && args[0] == "explain"
+	if explain {
+		if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+			return fmt.Errorf("usage: cca rules explain <rule-id>")
+		}
+		args = args[1:]
+	}
+
+	dir := "."
+	id := ""
+	jsonOutput, markdown := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		case "--markdown":
+			markdown = true
+		default:
+			switch {
+			case strings.HasPrefix(arg, "-"):
+			case explain && id == "":
+				id = arg
+			default:
+				dir = arg
+			}
+		}
+	}
+
+	// The docs table covers the built-in rules only, independent of any project config
+	if markdown {
+		fmt.Print(validator.RulesMarkdown())
+		return nil
+	}
+
+	cfg, err := config.LoadSpecConfigInDir(dir)
+	if err != nil {
+		return err
+	}
+	rules, err := validator.ProjectRules(cfg)
+	if err != nil {
+		return err
+	}
+
+	if explain {
+		rule, err := validator.FindRuleStatus(rules, id)
+		if err != nil {
+			return err
+		}
+		fmt.Print(validator.FormatRuleExplanation(rule))
+		return nil
+	}
+	if jsonOutput {
+		fmt.Println(validator.FormatRuleListJSON(rules))
+		return nil
+	}
+	fmt.Print(validator.FormatRuleList(rules))
+	return nil
+}
+
+func runFix() error {
+	dir := "."
+	rule := ""
+	autoAccept := false
+	providerFlags := config.ProviderConfig{}
+
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		if value, ok := flagValue(args, &i, "--rule"); ok {
+			rule = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--provider"); ok {
+			providerFlags.Name = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--model"); ok {
+			providerFlags.Model = value
+			continue
+		}
+		switch args[i] {
+		case "--yes", "-y":
+			autoAccept = true
+		default:
+			if !strings.HasPrefix(args[i], "-") {
+				dir = args[i]
+			}
+		}
+	}
+
+	specPath, err := config.FindSpecInDir(dir)
+	if err != nil {
+		return err
+	}
+
+	opts := validator.ValidationOptions{}
+	if err := configureValidation(dir, &opts, providerFlags); err != nil {
+		return err
+	}
+	if err := opts.Provider.Available(); err != nil {
+		return err
+	}
+
+	record, err := validator.LoadFindings(specPath)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("no recorded findings - run 'cca validate' first")
+	}
+
+	var findings []validator.Finding
+	for _, f := range record.Findings {
+		if rule == "" || strings.EqualFold(f.Rule, rule) {
+			findings = append(findings, f)
+		}
+	}
+	if len(findings) == 0 {
+		fmt.Println("No findings to fix.")
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Requesting fixes for %d finding(s) from %s...\n\n", len(findings), validator.ProviderLabel(opts.Provider))
+	proposal, err := validator.ProposeFixes(ctx, specPath, findings, opts)
+	exitIfOverBudget(err)
+	if err != nil {
+		return err
+	}
+	fmt.Println(validator.FormatUsage(proposal.Usage))
+
+	for _, reason := range proposal.Rejected {
+		fmt.Printf("Dropped edit: %s\n", reason)
+	}
+	if len(proposal.Skipped) > 0 {
+		fmt.Println("Needs author input:")
+		for _, s := range proposal.Skipped {
+			fmt.Printf("  - %s: %s\n", s.Rule, s.Reason)
+		}
+	}
+	if len(proposal.Rejected) > 0 || len(proposal.Skipped) > 0 {
+		fmt.Println()
+	}
+	if len(proposal.Edits) == 0 {
+		fmt.Println("No applicable edits proposed.")
+		return nil
+	}
+
+	baseDir := filepath.Dir(specPath)
+	reader := bufio.NewReader(os.Stdin)
+	var accepted []validator.FixEdit
+
+review:
+	for i, edit := range proposal.Edits {
+		fmt.Printf("[%d/%d] %s:%d (%s) %s\n", i+1, len(proposal.Edits), edit.File, edit.Line, edit.Rule, edit.Reason)
+		diff, err := validator.FormatFixDiff(baseDir, edit)
+		if err != nil {
+			return err
+		}
+		fmt.Println(diff)
+
+		if autoAccept {
+			accepted = append(accepted, edit)
+			continue
+		}
+
+		choice, err := promptFixChoice(reader)
+		if err != nil {
+			return err
+		}
+		switch choice {
+		case "a":
+			accepted = append(accepted, edit)
+		case "e":
+			replacement, err := editInEditor(edit.Replacement)
+			if err != nil {
+				return err
+			}
+			edit.Replacement = replacement
+			accepted = append(accepted, edit)
+		case "q":
+			fmt.Println("Stopped; remaining edits skipped.")
+			break review
+		}
+		fmt.Println()
+	}
+
+	if len(accepted) == 0 {
+		fmt.Println("No edits applied.")
+		return nil
+	}
+
+	files, err := validator.ApplyFixEdits(baseDir, accepted)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Applied %d edit(s) to %s\n\n", len(accepted), strings.Join(files, ", "))
+
+	// Re-check the edited spec
+	result, err := validator.ValidateQuick(specPath, opts.Schema)
+	if err != nil {
+		return err
+	}
+	validator.ApplyRuleConfig(result, opts.Severities, opts.Disabled)
+	fmt.Print(validator.FormatStructuralChecks(result.StructuralChecks, opts.FailOn))
+	fmt.Println("\nRun 'cca validate' to re-check the fixed findings.")
+	if !result.StructuralPassed {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// promptFixChoice asks whether to accept, skip or edit a proposed fix
+func promptFixChoice(reader *bufio.Reader) (string, error) {
+	for {
+		fmt.Print("Apply this edit? [a]ccept / [s]kip / [e]dit / [q]uit: ")
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		switch strings.TrimSpace(strings.ToLower(response)) {
+		case "a", "accept", "y", "yes":
+			return "a", nil
+		case "s", "skip", "n", "no", "":
+			return "s", nil
+		case "e", "edit":
+			return "e", nil
+		case "q", "quit":
+			return "q", nil
+		}
+	}
+}
+
+// editInEditor opens text in $EDITOR (default vi) and returns the saved result
+func editInEditor(text string) (string, error) {
+	f, err := os.CreateTemp("", "cca-fix-*.adoc")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	f.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command(editor, f.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// runAsk answers a question from the spec's most relevant sections, citing file:line
+// An unanswered question exits 1: the spec has a gap an implementer would hit
+func runAsk() error {
+	dir := "."
+	question := ""
+	limit := validator.DefaultAskSections
+	jsonOutput := false
+	providerFlags := config.ProviderConfig{}
+
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		if value, ok := flagValue(args, &i, "--top"); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid --top %q: must be a number >= 1", value)
+			}
+			limit = n
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--provider"); ok {
+			providerFlags.Name = value
+			continue
+		}
+		if value, ok := flagValue(args, &i, "--model"); ok {
+			providerFlags.Model = value
+			continue
+		}
+		switch args[i] {
+		case "--json":
+			jsonOutput = true
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				continue
+			}
+			if question == "" {
+				question = args[i]
+			} else {
+				dir = args[i]
+			}
+		}
+	}
+	if question == "" {
+		return fmt.Errorf("usage: cca ask \"<question>\" [--top <n>] [--json] [dir]")
+	}
+
+	specPath, err := config.FindSpecInDir(dir)
+	if err != nil {
+		return err
+	}
+
+	opts := validator.ValidationOptions{}
+	if err := configureValidation(dir, &opts, providerFlags); err != nil {
+		return err
+	}
+	if err := opts.Provider.Available(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	answer, err := validator.AskSpec(ctx, specPath, question, limit, opts)
+	exitIfOverBudget(err)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		fmt.Println(validator.FormatAnswerJSON(answer))
+	} else {
+		fmt.Print(validator.FormatAnswer(answer))
+		if answer.Usage != nil {
+			fmt.Print("\n" + validator.FormatUsage(answer.Usage))
+		}
+	}
+	if !answer.Answered {
+		os.Exit(exitFindings)
+	}
+	return nil
+}
+
+func runCache() error {
+	specPath, err := config.FindSpec()
+	if err != nil {
+		return err
+	}
+	cache := validator.NewResponseCache(specPath)
+
+	if len(os.Args) < 3 {
+		stats, err := cache.Stats()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Cache: %s\n", cache.Dir)
+		fmt.Printf("  %d entries, %dKB\n", stats.Entries, stats.Bytes/1024)
+		return nil
+	}
+
+	if os.Args[2] != "prune" {
+		return fmt.Errorf("unknown cache command: %s (usage: cca cache [prune [--all] [--older-than <duration>]])", os.Args[2])
+	}
+
+	maxAge := validator.DefaultCacheMaxAge
+	args := os.Args[3:]
+	for i := 0; i < len(args); i++ {
+		if value, ok := flagValue(args, &i, "--older-than"); ok {
+			maxAge, err = time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid --older-than %q: %w", value, err)
+			}
+			continue
+		}
+		if args[i] == "--all" {
+			maxAge = 0
+		}
+	}
+
+	removed, err := cache.Prune(maxAge)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d cache entries\n", removed)
+	return nil
+}
+
+func runPrompts() error {
+	if len(os.Args) < 3 || os.Args[2] != "export" {
+		return fmt.Errorf("usage: cca prompts export [dir] [--force]")
+	}
+
+	dir := "prompts"
+	force := false
+	for _, arg := range os.Args[3:] {
+		switch arg {
+		case "--force", "-f":
+			force = true
+		default:
+			if !strings.HasPrefix(arg, "-") {
+				dir = arg
+			}
+		}
+	}
+
+	written, err := validator.ExportPromptTemplates(dir, force)
+	for _, path := range written {
+		fmt.Printf("Wrote %s\n", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("\nTo use them, add to .spec.yaml:\n  prompts:\n")
+	for _, name := range validator.PromptTemplateNames() {
+		fmt.Printf("    %s: %s\n", name, filepath.ToSlash(filepath.Join(dir, name+".tmpl")))
+	}
+	return nil
+}
+
+func runSchema() error {
+	if len(os.Args) < 3 || os.Args[2] != "export" {
+		return fmt.Errorf("usage: cca schema export [file] [--force]")
+	}
+
+	path := "spec-schema.yaml"
+	force := false
+	for _, arg := range os.Args[3:] {
+		switch arg {
+		case "--force", "-f":
+			force = true
+		default:
+			if !strings.HasPrefix(arg, "-") {
+				path = arg
+			}
+		}
+	}
+
+	if err := validator.ExportSchema(path, force); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s\n\nTo use it, add to .spec.yaml:\n  schema:\n    file: %s\n", path, filepath.ToSlash(path))
 	return nil
 }
 
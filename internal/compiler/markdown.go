@@ -0,0 +1,92 @@
+package compiler
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MarkdownSection is a heading-delimited block of compiled Markdown
+type MarkdownSection struct {
+	Title   string // Heading text without leading #
+	Level   int    // Number of # characters
+	Content string // Heading line plus body, up to the next heading
+}
+
+// Matches characters asciidoctor drops when generating section IDs
+var invalidIDChars = regexp.MustCompile(`[^a-z0-9_ .-]`)
+
+// Matches runs of separators in a generated section ID
+var idSeparators = regexp.MustCompile(`[ .-]+`)
+
+// Matches compiled xref links: [Title](#_section_id)
+var xrefLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\(#([^)\s]+)\)`)
+
+// SplitMarkdownSections splits compiled Markdown into sections in document order
+// Lines inside fenced code blocks are never treated as headings
+func SplitMarkdownSections(content string) []MarkdownSection {
+	var sections []MarkdownSection
+	var current *MarkdownSection
+	var body strings.Builder
+	inFence := false
+
+	flush := func() {
+		if current != nil {
+			current.Content = body.String()
+			sections = append(sections, *current)
+		}
+		body.Reset()
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+
+		if !inFence && strings.HasPrefix(line, "#") {
+			flush()
+			level := len(line) - len(strings.TrimLeft(line, "#"))
+			current = &MarkdownSection{
+				Title: strings.TrimLeft(line, "# "),
+				Level: level,
+			}
+		}
+
+		if current != nil {
+			body.WriteString(line)
+			body.WriteString("\n")
+		}
+	}
+	flush()
+
+	return sections
+}
+
+// SectionID returns the ID asciidoctor generates for a section title by default
+// (idprefix "_", idseparator "_"), which is what compiled xref links point at
+func SectionID(title string) string {
+	id := invalidIDChars.ReplaceAllString(strings.ToLower(title), "")
+	id = idSeparators.ReplaceAllString(strings.TrimSpace(id), "_")
+	return "_" + id
+}
+
+// LinkedSections returns the indexes of the sections that xref links in content point at
+// Generated IDs are matched against each title; sections with an explicit [[id]] anchor
+// lose their ID in the Markdown, so a link is also matched by its text, which asciidoctor
+// fills with the target's title
+func LinkedSections(sections []MarkdownSection, content string) []int {
+	ids := make(map[string]bool)
+	texts := make(map[string]bool)
+	for _, match := range xrefLinkPattern.FindAllStringSubmatch(content, -1) {
+		texts[strings.TrimSpace(match[1])] = true
+		ids[match[2]] = true
+	}
+
+	var linked []int
+	for i, section := range sections {
+		if ids[SectionID(section.Title)] || texts[section.Title] {
+			linked = append(linked, i)
+		}
+	}
+	return linked
+}
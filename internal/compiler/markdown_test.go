@@ -0,0 +1,121 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSplitMarkdownSections(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string // title/level of each section
+	}{
+		{
+			name:    "nested headings",
+			content: "# Spec\n\nIntro.\n\n## API\n\n### POST /users\n\nBody.\n\n## Deployment\n",
+			want:    []string{"Spec/1", "API/2", "POST /users/3", "Deployment/2"},
+		},
+		{
+			name:    "hash lines in fenced code",
+			content: "## Setup\n\n```bash\n# install deps\n## not a heading\n```\n\n~~~\n# still code\n~~~\n\n## Next\n",
+			want:    []string{"Setup/2", "Next/2"},
+		},
+		{
+			name:    "text before the first heading is dropped",
+			content: "Preamble.\n\n## Only\n",
+			want:    []string{"Only/2"},
+		},
+		{
+			name:    "no headings",
+			content: "Just text.\n",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range SplitMarkdownSections(tt.content) {
+				got = append(got, fmt.Sprintf("%s/%d", s.Title, s.Level))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("sections = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitMarkdownSections_Content(t *testing.T) {
+	sections := SplitMarkdownSections("## Setup\n\n```bash\n# install deps\n```\n\n## Next\n\nDone.\n")
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d", len(sections))
+	}
+	if want := "## Setup\n\n```bash\n# install deps\n```\n\n"; sections[0].Content != want {
+		t.Errorf("first section content = %q, want %q", sections[0].Content, want)
+	}
+	if want := "## Next\n\nDone.\n\n"; sections[1].Content != want {
+		t.Errorf("second section content = %q, want %q", sections[1].Content, want)
+	}
+}
+
+func TestSectionID(t *testing.T) {
+	tests := map[string]string{
+		"User":                "_user",
+		"Core Types":          "_core_types",
+		"POST /users":         "_post_users",
+		"Rate-limit v1.2":     "_rate_limit_v1_2",
+		"Errors (HTTP 4xx)":   "_errors_http_4xx",
+		"  Padded Title  ":    "_padded_title",
+		"snake_case_sections": "_snake_case_sections",
+	}
+	for title, want := range tests {
+		if got := SectionID(title); got != want {
+			t.Errorf("SectionID(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestLinkedSections(t *testing.T) {
+	sections := SplitMarkdownSections("## Context\n\n## User\n\n## Error Codes\n\n## Deployment\n")
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "generated ID",
+			content: "Returns a [User](#_user).",
+			want:    []string{"User"},
+		},
+		{
+			name:    "explicit anchor matched by link text",
+			content: "Fails with one of the [Error Codes](#api-errors).",
+			want:    []string{"Error Codes"},
+		},
+		{
+			name:    "custom text on an explicit anchor cannot be resolved",
+			content: "See [the error table](#api-errors).",
+			want:    nil,
+		},
+		{
+			name:    "external links are ignored",
+			content: "See [Deployment](https://example.com/deploy).",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, i := range LinkedSections(sections, tt.content) {
+				got = append(got, sections[i].Title)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("linked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,13 +17,14 @@ func CompileSection(manifestPath, sectionQuery string) (string, error) {
 	}
 
 	// Find the matching section
-	section := parser.FindSection(structure, sectionQuery)
-	if section == nil {
+	found := parser.FindSection(structure, sectionQuery)
+	if found == nil {
 		return "", fmt.Errorf("section not found: %s", sectionQuery)
 	}
+	section := WithSubsections(structure, *found)
 
-	// Get the section content
-	content, err := parser.GetSectionContent(section)
+	// Get the section content, subsections included
+	content, err := parser.GetSectionContent(&section)
 	if err != nil {
 		return "", fmt.Errorf("failed to read section content: %v", err)
 	}
@@ -37,6 +38,18 @@ func CompileSection(manifestPath, sectionQuery string) (string, error) {
 	return CompileContent(fullContent, baseDir)
 }
 
+// WithSubsections extends a section to the end of its last subsection in the same file
+func WithSubsections(structure *parser.SpecStructure, section parser.SectionInfo) parser.SectionInfo {
+	section.EndLine = -1
+	for _, s := range structure.Sections {
+		if s.FilePath == section.FilePath && s.StartLine > section.StartLine && s.Level <= section.Level &&
+			(section.EndLine < 0 || s.StartLine-1 < section.EndLine) {
+			section.EndLine = s.StartLine - 1
+		}
+	}
+	return section
+}
+
 // CompileFile compiles a specific included file with attributes from manifest
 func CompileFile(manifestPath, filePath string) (string, error) {
 	// Build the spec structure to get attributes
@@ -0,0 +1,54 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+func TestWithSubsections(t *testing.T) {
+	structure := &parser.SpecStructure{
+		Sections: []parser.SectionInfo{
+			{Title: "Spec", Level: 0, FilePath: "MANIFEST.adoc", StartLine: 1},
+			{Title: "Context", Level: 1, FilePath: "MANIFEST.adoc", StartLine: 3},
+			{Title: "Stack", Level: 2, FilePath: "MANIFEST.adoc", StartLine: 7},
+			{Title: "API", Level: 1, FilePath: "MANIFEST.adoc", StartLine: 12},
+			{Title: "Errors", Level: 2, FilePath: "MANIFEST.adoc", StartLine: 15},
+			{Title: "Codes", Level: 3, FilePath: "MANIFEST.adoc", StartLine: 18},
+			{Title: "Limits", Level: 2, FilePath: "MANIFEST.adoc", StartLine: 22},
+			{Title: "Storage", Level: 1, FilePath: "storage.adoc", StartLine: 1},
+			{Title: "Tables", Level: 2, FilePath: "storage.adoc", StartLine: 4},
+		},
+	}
+
+	tests := []struct {
+		title   string
+		wantEnd int
+	}{
+		{"Context", 11}, // Ends before the next level-1 section, after its subsection
+		{"Stack", 11},   // A subsection ends with its parent
+		{"API", -1},     // Last section in its file runs to the end
+		{"Errors", 21},  // Includes the nested Codes subsection
+		{"Codes", 21},   // Ends at the next sibling of its parent
+		{"Storage", -1}, // Sections in other files do not end it
+		{"Tables", -1},  // Last subsection in its file
+		{"Spec", -1},    // The document title spans its whole file
+		{"Limits", -1},  // Followed only by sections in another file
+	}
+
+	for _, tt := range tests {
+		var section parser.SectionInfo
+		for _, s := range structure.Sections {
+			if s.Title == tt.title {
+				section = s
+			}
+		}
+		got := WithSubsections(structure, section)
+		if got.EndLine != tt.wantEnd {
+			t.Errorf("WithSubsections(%s).EndLine = %d, want %d", tt.title, got.EndLine, tt.wantEnd)
+		}
+		if got.StartLine != section.StartLine || got.Title != section.Title {
+			t.Errorf("WithSubsections(%s) changed the section start: %+v", tt.title, got)
+		}
+	}
+}
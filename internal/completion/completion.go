@@ -7,7 +7,7 @@ func Bash() string {
     COMPREPLY=()
     cur="${COMP_WORDS[COMP_CWORD]}"
     prev="${COMP_WORDS[COMP_CWORD-1]}"
-    commands="compile validate diff impact list skill version help completion"
+    commands="compile validate fix ask deps attrs stats rules diff impact list cache prompts schema skill version help completion"
 
     case "${prev}" in
         cca)
@@ -15,7 +15,31 @@ func Bash() string {
             return 0
             ;;
         validate)
-            COMPREPLY=( $(compgen -W "--quick --ultra --yes -q -u -y" -- ${cur}) )
+            COMPREPLY=( $(compgen -W "--quick --ultra --runs --threshold --synthesize --yes --since --provider --model --timeout --retries --quorum --no-cache --no-chunk --fail-on --jobs --record --history --at --section --file --dry-run --out -q -u -y" -- ${cur}) )
+            return 0
+            ;;
+        fix)
+            COMPREPLY=( $(compgen -W "--yes --rule --provider --model -y" -- ${cur}) )
+            return 0
+            ;;
+        ask)
+            COMPREPLY=( $(compgen -W "--top --json --provider --model" -- ${cur}) )
+            return 0
+            ;;
+        deps)
+            COMPREPLY=( $(compgen -W "--json" -- ${cur}) )
+            return 0
+            ;;
+        attrs)
+            COMPREPLY=( $(compgen -W "--check --json --fail-on" -- ${cur}) )
+            return 0
+            ;;
+        stats)
+            COMPREPLY=( $(compgen -W "--json" -- ${cur}) )
+            return 0
+            ;;
+        rules)
+            COMPREPLY=( $(compgen -W "explain --json --markdown" -- ${cur}) )
             return 0
             ;;
         compile)
@@ -26,6 +50,22 @@ func Bash() string {
             COMPREPLY=( $(compgen -W "--global -g" -- ${cur}) )
             return 0
             ;;
+        cache)
+            COMPREPLY=( $(compgen -W "prune" -- ${cur}) )
+            return 0
+            ;;
+        prune)
+            COMPREPLY=( $(compgen -W "--all --older-than" -- ${cur}) )
+            return 0
+            ;;
+        prompts)
+            COMPREPLY=( $(compgen -W "export" -- ${cur}) )
+            return 0
+            ;;
+        schema)
+            COMPREPLY=( $(compgen -W "export" -- ${cur}) )
+            return 0
+            ;;
         completion)
             COMPREPLY=( $(compgen -W "bash zsh fish" -- ${cur}) )
             return 0
@@ -47,9 +87,18 @@ _cca() {
     commands=(
         'compile:Compile spec to Markdown'
         'validate:Run validation'
+        'fix:Apply fixes for recorded findings'
+        'ask:Answer a question from the spec with citations'
+        'deps:List dependencies and version status'
+        'attrs:List or check attributes'
+        'stats:Show spec size, tokens and findings density'
+        'rules:List or explain validation rules'
         'diff:Diff compiled output'
         'impact:Show attribute impact'
         'list:List sections'
+        'cache:Inspect or prune validation cache'
+        'prompts:Export prompt templates'
+        'schema:Export the spec schema'
         'skill:Install Claude Code skill'
         'version:Show version'
         'help:Show help'
@@ -70,17 +119,74 @@ _cca() {
                     _arguments \
                         '--quick[Structural checks only]' \
                         '--ultra[Enhanced validation]' \
+                        '--runs[Number of ultra runs]:runs:' \
+                        '--threshold[Consensus threshold]:fraction:' \
+                        '--synthesize[LLM synthesis of ultra runs]' \
                         '--yes[Skip confirmation]' \
+                        '--since[Validate sections changed since ref]:ref:' \
+                        '--provider[LLM provider]:provider:(claude-cli anthropic openai)' \
+                        '--model[Provider model]:model:' \
+                        '--timeout[Per-call timeout]:duration:' \
+                        '--retries[Retries for transient failures]:retries:' \
+                        '--quorum[Minimum successful ultra runs]:runs:' \
+                        '--no-cache[Ignore cached results]' \
+                        '--no-chunk[Send large specs in one call]' \
+                        '--fail-on[Lowest severity that fails]:severity:(error warning info none)' \
+                        '--jobs[Specs validated in parallel with ./...]:jobs:' \
+                        '--record[Record the run in .cca/history.jsonl]' \
+                        '--history[Show per-rule trends from recorded runs]' \
+                        '--at[Validate the spec at a commit and record it]:ref:' \
+                        '--section[Validate one section]:section:' \
+                        '--file[Validate one spec file]:file:_files' \
+                        '--dry-run[Show prompts without calling the provider]' \
+                        '--out[Save dry-run prompts to a directory]:directory:_files -/' \
                         '-q[Structural checks only]' \
                         '-u[Enhanced validation]' \
                         '-y[Skip confirmation]'
                     ;;
+                fix)
+                    _arguments \
+                        '--yes[Apply all edits]' \
+                        '--rule[Only fix one rule]:rule:' \
+                        '--provider[LLM provider]:provider:(claude-cli anthropic openai)' \
+                        '--model[Provider model]:model:' \
+                        '-y[Apply all edits]'
+                    ;;
+                ask)
+                    _arguments \
+                        '1:question:' \
+                        '--top[Sections to search]:count:' \
+                        '--json[Output JSON]' \
+                        '--provider[LLM provider]:provider:(claude-cli anthropic openai)' \
+                        '--model[Provider model]:model:'
+                    ;;
+                deps)
+                    _arguments '--json[Output JSON]'
+                    ;;
+                attrs)
+                    _arguments '--check[Report attribute issues]' '--json[Output JSON]' '--fail-on[Lowest severity that fails]:severity:(error warning info none)'
+                    ;;
+                stats)
+                    _arguments '--json[Output JSON]'
+                    ;;
+                rules)
+                    _arguments '1:command:(explain)' '2:rule:' '--json[Output JSON]' '--markdown[Print the docs table]'
+                    ;;
                 compile)
                     _arguments '--section[Compile specific section]:section:'
                     ;;
                 skill)
                     _arguments '--global[Install globally]' '-g[Install globally]'
                     ;;
+                cache)
+                    _arguments '1:command:(prune)' '--all[Remove all entries]' '--older-than[Remove entries older than]:duration:'
+                    ;;
+                prompts)
+                    _arguments '1:command:(export)' '2:directory:_files -/' '--force[Overwrite existing files]'
+                    ;;
+                schema)
+                    _arguments '1:command:(export)' '2:file:_files' '--force[Overwrite existing file]'
+                    ;;
                 completion)
                     _arguments '1:shell:(bash zsh fish)'
                     ;;
@@ -99,9 +205,18 @@ func Fish() string {
 
 complete -c cca -n '__fish_use_subcommand' -a compile -d 'Compile spec to Markdown'
 complete -c cca -n '__fish_use_subcommand' -a validate -d 'Run validation'
+complete -c cca -n '__fish_use_subcommand' -a fix -d 'Apply fixes for recorded findings'
+complete -c cca -n '__fish_use_subcommand' -a ask -d 'Answer a question from the spec with citations'
+complete -c cca -n '__fish_use_subcommand' -a deps -d 'List dependencies and version status'
+complete -c cca -n '__fish_use_subcommand' -a attrs -d 'List or check attributes'
+complete -c cca -n '__fish_use_subcommand' -a stats -d 'Show spec size, tokens and findings density'
+complete -c cca -n '__fish_use_subcommand' -a rules -d 'List or explain validation rules'
 complete -c cca -n '__fish_use_subcommand' -a diff -d 'Diff compiled output'
 complete -c cca -n '__fish_use_subcommand' -a impact -d 'Show attribute impact'
 complete -c cca -n '__fish_use_subcommand' -a list -d 'List sections'
+complete -c cca -n '__fish_use_subcommand' -a cache -d 'Inspect or prune validation cache'
+complete -c cca -n '__fish_use_subcommand' -a prompts -d 'Export prompt templates'
+complete -c cca -n '__fish_use_subcommand' -a schema -d 'Export the spec schema'
 complete -c cca -n '__fish_use_subcommand' -a skill -d 'Install Claude Code skill'
 complete -c cca -n '__fish_use_subcommand' -a version -d 'Show version'
 complete -c cca -n '__fish_use_subcommand' -a help -d 'Show help'
@@ -109,12 +224,61 @@ complete -c cca -n '__fish_use_subcommand' -a completion -d 'Generate shell comp
 
 complete -c cca -n '__fish_seen_subcommand_from validate' -l quick -s q -d 'Structural checks only'
 complete -c cca -n '__fish_seen_subcommand_from validate' -l ultra -s u -d 'Enhanced validation'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l runs -r -d 'Number of ultra runs'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l threshold -r -d 'Consensus threshold'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l synthesize -d 'LLM synthesis of ultra runs'
 complete -c cca -n '__fish_seen_subcommand_from validate' -l yes -s y -d 'Skip confirmation'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l since -r -d 'Validate sections changed since ref'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l provider -r -a 'claude-cli anthropic openai' -d 'LLM provider'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l model -r -d 'Provider model'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l timeout -r -d 'Per-call timeout'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l retries -r -d 'Retries for transient failures'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l quorum -r -d 'Minimum successful ultra runs'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l no-cache -d 'Ignore cached results'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l no-chunk -d 'Send large specs in one call'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l fail-on -r -a 'error warning info none' -d 'Lowest severity that fails'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l jobs -r -d 'Specs validated in parallel with ./...'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l record -d 'Record the run in .cca/history.jsonl'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l history -d 'Show per-rule trends from recorded runs'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l at -r -d 'Validate the spec at a commit and record it'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l section -r -d 'Validate one section'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l file -r -F -d 'Validate one spec file'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l dry-run -d 'Show prompts without calling the provider'
+complete -c cca -n '__fish_seen_subcommand_from validate' -l out -r -a '(__fish_complete_directories)' -d 'Save dry-run prompts to a directory'
+
+complete -c cca -n '__fish_seen_subcommand_from fix' -l yes -s y -d 'Apply all edits'
+complete -c cca -n '__fish_seen_subcommand_from fix' -l rule -r -d 'Only fix one rule'
+complete -c cca -n '__fish_seen_subcommand_from fix' -l provider -r -a 'claude-cli anthropic openai' -d 'LLM provider'
+complete -c cca -n '__fish_seen_subcommand_from fix' -l model -r -d 'Provider model'
+
+complete -c cca -n '__fish_seen_subcommand_from ask' -l top -r -d 'Sections to search'
+complete -c cca -n '__fish_seen_subcommand_from ask' -l json -d 'Output JSON'
+complete -c cca -n '__fish_seen_subcommand_from ask' -l provider -r -a 'claude-cli anthropic openai' -d 'LLM provider'
+complete -c cca -n '__fish_seen_subcommand_from ask' -l model -r -d 'Provider model'
+
+complete -c cca -n '__fish_seen_subcommand_from deps' -l json -d 'Output JSON'
+
+complete -c cca -n '__fish_seen_subcommand_from attrs' -l check -d 'Report attribute issues'
+complete -c cca -n '__fish_seen_subcommand_from attrs' -l json -d 'Output JSON'
+complete -c cca -n '__fish_seen_subcommand_from attrs' -l fail-on -r -a 'error warning info none' -d 'Lowest severity that fails'
+complete -c cca -n '__fish_seen_subcommand_from stats' -l json -d 'Output JSON'
+
+complete -c cca -n '__fish_seen_subcommand_from rules' -a explain -d 'Explain one rule'
+complete -c cca -n '__fish_seen_subcommand_from rules' -l json -d 'Output JSON'
+complete -c cca -n '__fish_seen_subcommand_from rules' -l markdown -d 'Print the docs table'
 
 complete -c cca -n '__fish_seen_subcommand_from compile' -l section -d 'Compile specific section'
 
 complete -c cca -n '__fish_seen_subcommand_from skill' -l global -s g -d 'Install globally'
 
+complete -c cca -n '__fish_seen_subcommand_from cache' -a prune -d 'Remove old cache entries'
+complete -c cca -n '__fish_seen_subcommand_from prune' -l all -d 'Remove all entries'
+complete -c cca -n '__fish_seen_subcommand_from prune' -l older-than -r -d 'Remove entries older than'
+
+complete -c cca -n '__fish_seen_subcommand_from prompts' -a export -d 'Write default templates'
+complete -c cca -n '__fish_seen_subcommand_from schema' -a export -d 'Write the default schema'
+complete -c cca -n '__fish_seen_subcommand_from export' -l force -s f -d 'Overwrite existing files'
+
 complete -c cca -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'
 `
 }
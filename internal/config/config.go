@@ -2,15 +2,90 @@ package config
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // SpecConfig represents the .spec.yaml configuration file
 type SpecConfig struct {
-	Spec string `yaml:"spec"`
+	Spec     string            `yaml:"spec"`
+	Provider ProviderConfig    `yaml:"provider"`
+	Rules    RulesConfig       `yaml:"rules"`
+	Ultra    UltraConfig       `yaml:"ultra"`
+	Chunks   ChunkConfig       `yaml:"chunks"`
+	FailOn   string            `yaml:"fail_on"` // Lowest finding severity that fails validate (default error)
+	Pricing  map[string]Price  `yaml:"pricing"` // Model (or provider) name -> price
+	Budget   BudgetConfig      `yaml:"budget"`
+	Prompts  map[string]string `yaml:"prompts"` // Template name -> replacement file (relative to .spec.yaml)
+	Schema   SchemaConfig      `yaml:"schema"`
+	History  bool              `yaml:"history"` // Record every validate run in .cca/history.jsonl
+}
+
+// SchemaConfig selects the structural schema for required sections and fields
+type SchemaConfig struct {
+	Kind string `yaml:"kind"` // Project kind adding its own sections: service, cli, library
+	File string `yaml:"file"` // Schema replacing the embedded default (relative to .spec.yaml)
+}
+
+// UltraConfig tunes multi-run (--ultra) validation
+type UltraConfig struct {
+	Runs       int     `yaml:"runs"`       // Parallel validation runs (default 3)
+	Threshold  float64 `yaml:"threshold"`  // Minimum fraction of runs that must agree (default 0.5)
+	Synthesize bool    `yaml:"synthesize"` // Also ask the provider to synthesize all runs
+	Quorum     int     `yaml:"quorum"`     // Minimum successful runs (default: majority of runs)
+}
+
+// Price is the cost of a model in USD per million tokens
+type Price struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+// BudgetConfig caps the estimated size of a semantic run
+// Runs over budget are refused instead of prompting; 0 means no limit
+type BudgetConfig struct {
+	Tokens int     `yaml:"tokens"` // Estimated input + output tokens
+	Cost   float64 `yaml:"cost"`   // Estimated USD, requires pricing for the model
+}
+
+// ChunkConfig tunes chunked validation of specs above the large-spec threshold
+type ChunkConfig struct {
+	MaxSize     int  `yaml:"max_size"`    // Target chunk size in bytes (default 30000)
+	Concurrency int  `yaml:"concurrency"` // Chunks validated in parallel (default 4)
+	Disabled    bool `yaml:"disabled"`    // Always send the whole spec in one call
+}
+
+// RulesConfig extends the built-in validation checklist
+type RulesConfig struct {
+	Custom   []CustomRule      `yaml:"custom"`
+	Severity map[string]string `yaml:"severity"` // Rule ID -> error | warning | info, overrides defaults
+	Disabled []string          `yaml:"disabled"` // Built-in or custom rule IDs to skip
+}
+
+// CustomRule is a project-specific rule injected into the validation prompt
+type CustomRule struct {
+	ID          string   `yaml:"id"`
+	Description string   `yaml:"description"`
+	Examples    []string `yaml:"examples"`
+	Severity    string   `yaml:"severity"` // error | warning | info (default warning)
+}
+
+// ProviderConfig selects and configures the LLM used for semantic validation
+type ProviderConfig struct {
+	Name       string        `yaml:"name"`        // claude-cli (default), anthropic, openai
+	Model      string        `yaml:"model"`       // Model identifier passed to the provider
+	Timeout    time.Duration `yaml:"timeout"`     // Per-call timeout (e.g. 5m), default 10m
+	Retries    *int          `yaml:"retries"`     // Retries for transient failures (default 2, 0 disables)
+	RetryDelay time.Duration `yaml:"retry_delay"` // Initial backoff, doubled per retry (default 2s)
+	Args       []string      `yaml:"args"`        // Extra CLI arguments (claude-cli only)
+	BaseURL    string        `yaml:"base_url"`    // API endpoint (HTTP providers)
+	APIKeyEnv  string        `yaml:"api_key_env"` // Environment variable holding the API key
+	MaxTokens  int           `yaml:"max_tokens"`  // Response token limit (HTTP providers)
 }
 
 // FindSpec discovers the specification file location in the current directory
@@ -56,6 +131,67 @@ func FindSpecInDir(dir string) (string, error) {
 	return "", fmt.Errorf("spec not found in %s - checked: %v\nCreate .spec.yaml or use MANIFEST.adoc", dir, conventions)
 }
 
+// SpecLocation is a spec found by FindSpecs
+type SpecLocation struct {
+	Dir      string // Directory the spec was found from (holds .spec.yaml or the convention layout)
+	Manifest string // Absolute manifest path
+}
+
+// skippedDirs are never searched for specs, along with hidden directories
+var skippedDirs = map[string]bool{"node_modules": true, "vendor": true, "testdata": true}
+
+// SpecPattern reports whether arg is a recursive spec pattern such as ./... or services/...
+// and returns the directory to search
+func SpecPattern(arg string) (string, bool) {
+	if arg == "..." {
+		return ".", true
+	}
+	if root, ok := strings.CutSuffix(arg, "/..."); ok {
+		if root == "" {
+			root = "/"
+		}
+		return root, true
+	}
+	return "", false
+}
+
+// FindSpecs discovers every spec under root, in directory order
+// A directory holds a spec if FindSpecInDir succeeds there; a manifest reachable from
+// several directories (svc and svc/spec) is reported once, for the outermost one
+func FindSpecs(root string) ([]SpecLocation, error) {
+	var specs []SpecLocation
+	seen := make(map[string]bool)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || skippedDirs[d.Name()]) {
+			return filepath.SkipDir
+		}
+
+		manifest, err := FindSpecInDir(path)
+		if err != nil {
+			if _, statErr := os.Stat(filepath.Join(path, ".spec.yaml")); statErr == nil {
+				return err // A .spec.yaml pointing at a missing manifest is a mistake, not an absence
+			}
+			return nil
+		}
+		if !seen[manifest] {
+			seen[manifest] = true
+			specs = append(specs, SpecLocation{Dir: path, Manifest: manifest})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
 // LoadSpecConfig loads .spec.yaml configuration from the current directory
 func LoadSpecConfig() (*SpecConfig, error) {
 	data, err := os.ReadFile(".spec.yaml")
@@ -71,6 +207,25 @@ func LoadSpecConfig() (*SpecConfig, error) {
 	return &config, nil
 }
 
+// LoadSpecConfigInDir loads .spec.yaml from the given directory
+// A missing file yields an empty config so callers can rely on defaults
+func LoadSpecConfigInDir(dir string) (*SpecConfig, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".spec.yaml"))
+	if os.IsNotExist(err) {
+		return &SpecConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config SpecConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid .spec.yaml: %w", err)
+	}
+
+	return &config, nil
+}
+
 // GetSpecRoot returns the directory containing the MANIFEST.adoc file
 func GetSpecRoot(manifestPath string) string {
 	return filepath.Dir(manifestPath)
@@ -117,3 +117,108 @@ func TestFindSpec_UsesCurrentDir(t *testing.T) {
 		t.Errorf("expected MANIFEST.adoc, got %s", result)
 	}
 }
+
+func TestLoadSpecConfigInDir_Provider(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".spec.yaml"), []byte(`spec: ./MANIFEST.adoc
+provider:
+  name: openai
+  model: qwen2.5-coder
+  timeout: 90s
+  base_url: http://localhost:8000/v1
+  args: ["--verbose"]
+`), 0644)
+
+	config, err := LoadSpecConfigInDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := config.Provider
+	if p.Name != "openai" || p.Model != "qwen2.5-coder" || p.BaseURL != "http://localhost:8000/v1" {
+		t.Errorf("unexpected provider config: %+v", p)
+	}
+	if p.Timeout.Seconds() != 90 {
+		t.Errorf("expected 90s timeout, got %v", p.Timeout)
+	}
+	if len(p.Args) != 1 || p.Args[0] != "--verbose" {
+		t.Errorf("expected args [--verbose], got %v", p.Args)
+	}
+}
+
+func TestLoadSpecConfigInDir_Missing(t *testing.T) {
+	config, err := LoadSpecConfigInDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Spec != "" || config.Provider.Name != "" {
+		t.Errorf("expected empty config, got %+v", config)
+	}
+}
+
+func TestFindSpecs(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		full := filepath.Join(root, path)
+		os.MkdirAll(filepath.Dir(full), 0755)
+		os.WriteFile(full, []byte(content), 0644)
+	}
+	write("services/api/MANIFEST.adoc", "= API")
+	write("services/billing/.spec.yaml", "spec: ./docs/billing.adoc")
+	write("services/billing/docs/billing.adoc", "= Billing")
+	write("services/worker/spec/MANIFEST.adoc", "= Worker")
+	write("services/web/node_modules/pkg/MANIFEST.adoc", "= Vendored")
+	write(".git/MANIFEST.adoc", "= Hidden")
+	write("services/web/README.md", "no spec here")
+
+	specs, err := FindSpecs(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, spec := range specs {
+		rel, _ := filepath.Rel(root, spec.Dir)
+		got = append(got, rel+" "+filepath.Base(spec.Manifest))
+	}
+	want := []string{
+		"services/api MANIFEST.adoc",
+		"services/billing billing.adoc",
+		"services/worker MANIFEST.adoc",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("spec %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestFindSpecs_BadSpecYaml(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "svc"), 0755)
+	os.WriteFile(filepath.Join(root, "svc", ".spec.yaml"), []byte("spec: ./missing.adoc"), 0644)
+
+	if _, err := FindSpecs(root); err == nil {
+		t.Error("expected error for .spec.yaml pointing at a missing spec")
+	}
+}
+
+func TestSpecPattern(t *testing.T) {
+	tests := map[string]string{
+		"./...":        ".",
+		"...":          ".",
+		"services/...": "services",
+	}
+	for arg, want := range tests {
+		root, ok := SpecPattern(arg)
+		if !ok || root != want {
+			t.Errorf("SpecPattern(%q) = %q, %v; want %q", arg, root, ok, want)
+		}
+	}
+	if _, ok := SpecPattern("services"); ok {
+		t.Error("plain directory should not be a pattern")
+	}
+}
@@ -31,17 +31,18 @@ type SectionChange struct {
 
 // DiffCompiled compares compiled output between current and a previous commit
 func DiffCompiled(manifestPath, targetCommit string) (*DiffResult, error) {
-	if !IsGitRepository() {
+	dir := filepath.Dir(manifestPath)
+	if !IsGitRepository(dir) {
 		return nil, fmt.Errorf("not in a git repository")
 	}
 
 	// Resolve commits
-	currentCommit, err := GetCurrentCommit()
+	currentCommit, err := GetCurrentCommit(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	oldCommit, err := ResolveCommit(targetCommit)
+	oldCommit, err := ResolveCommit(dir, targetCommit)
 	if err != nil {
 		return nil, err
 	}
@@ -51,11 +52,11 @@ func DiffCompiled(manifestPath, targetCommit string) (*DiffResult, error) {
 		NewCommit: currentCommit,
 	}
 
-	result.OldCommitShort, _ = GetCommitShort(oldCommit)
-	result.NewCommitShort, _ = GetCommitShort(currentCommit)
+	result.OldCommitShort, _ = GetCommitShort(dir, oldCommit)
+	result.NewCommitShort, _ = GetCommitShort(dir, currentCommit)
 
 	// Get changed source files
-	changedFiles, err := GetChangedFiles(oldCommit, currentCommit)
+	changedFiles, err := GetChangedFiles(dir, oldCommit, currentCommit)
 	if err != nil {
 		return nil, err
 	}
@@ -68,14 +69,14 @@ func DiffCompiled(manifestPath, targetCommit string) (*DiffResult, error) {
 	}
 
 	// Create worktree for old version and compile
-	worktreePath, err := CreateWorktree(oldCommit)
+	worktreePath, err := CreateWorktree(dir, oldCommit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create worktree: %v", err)
 	}
-	defer RemoveWorktree(worktreePath)
+	defer RemoveWorktree(dir, worktreePath)
 
 	// Find manifest in worktree
-	oldManifestPath := filepath.Join(worktreePath, getRelativeManifestPath(manifestPath))
+	oldManifestPath := WorktreeManifestPath(worktreePath, manifestPath)
 	oldOutput, err := compiler.Compile(oldManifestPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile old spec: %v", err)
@@ -170,33 +171,13 @@ func analyzeSectionChanges(old, new string) []SectionChange {
 }
 
 // extractSectionBlocks extracts sections from markdown content
+// Each section's content is its body without the heading line
 func extractSectionBlocks(content string) map[string]string {
 	sections := make(map[string]string)
-	lines := strings.Split(content, "\n")
-
-	var currentTitle string
-	var currentContent strings.Builder
-
-	for _, line := range lines {
-		if strings.HasPrefix(line, "#") {
-			// Save previous section
-			if currentTitle != "" {
-				sections[currentTitle] = currentContent.String()
-			}
-			// Start new section
-			currentTitle = strings.TrimLeft(line, "# ")
-			currentContent.Reset()
-		} else if currentTitle != "" {
-			currentContent.WriteString(line)
-			currentContent.WriteString("\n")
-		}
-	}
-
-	// Save last section
-	if currentTitle != "" {
-		sections[currentTitle] = currentContent.String()
+	for _, section := range compiler.SplitMarkdownSections(content) {
+		_, body, _ := strings.Cut(section.Content, "\n")
+		sections[section.Title] = body
 	}
-
 	return sections
 }
 
@@ -233,13 +214,26 @@ func filterAdocFiles(files []string) []string {
 	return result
 }
 
+// WorktreeManifestPath returns where manifestPath lives inside a worktree of the same repository
+func WorktreeManifestPath(worktreePath, manifestPath string) string {
+	return filepath.Join(worktreePath, getRelativeManifestPath(manifestPath))
+}
+
 // getRelativeManifestPath gets the relative path of manifest from git root
 func getRelativeManifestPath(manifestPath string) string {
-	gitRoot, err := GetGitRoot()
+	gitRoot, err := GetGitRoot(filepath.Dir(manifestPath))
 	if err != nil {
 		return filepath.Base(manifestPath)
 	}
 
+	// git reports the root with symlinks resolved; match it so Rel does not climb out
+	if abs, err := filepath.Abs(manifestPath); err == nil {
+		manifestPath = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(manifestPath); err == nil {
+		manifestPath = resolved
+	}
+
 	relPath, err := filepath.Rel(gitRoot, manifestPath)
 	if err != nil {
 		return filepath.Base(manifestPath)
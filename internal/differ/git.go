@@ -6,11 +6,17 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
-// GetCurrentCommit returns the current HEAD commit hash
-func GetCurrentCommit() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "HEAD")
+// gitCommand runs git in the repository containing dir, whatever the working directory
+func gitCommand(dir string, args ...string) *exec.Cmd {
+	return exec.Command("git", append([]string{"-C", dir}, args...)...)
+}
+
+// GetCurrentCommit returns the HEAD commit hash of the repository containing dir
+func GetCurrentCommit(dir string) (string, error) {
+	cmd := gitCommand(dir, "rev-parse", "HEAD")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current commit: %v", err)
@@ -19,8 +25,8 @@ func GetCurrentCommit() (string, error) {
 }
 
 // GetCommitShort returns the short hash for a commit
-func GetCommitShort(commit string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--short", commit)
+func GetCommitShort(dir, commit string) (string, error) {
+	cmd := gitCommand(dir, "rev-parse", "--short", commit)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get short commit: %v", err)
@@ -29,8 +35,8 @@ func GetCommitShort(commit string) (string, error) {
 }
 
 // ResolveCommit resolves a commit reference (HEAD~1, branch name, etc.) to a hash
-func ResolveCommit(ref string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", ref)
+func ResolveCommit(dir, ref string) (string, error) {
+	cmd := gitCommand(dir, "rev-parse", ref)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to resolve commit '%s': %v", ref, err)
@@ -38,6 +44,16 @@ func ResolveCommit(ref string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// GetCommitTime returns the committer date of a commit
+func GetCommitTime(dir, commit string) (time.Time, error) {
+	cmd := gitCommand(dir, "log", "-1", "--format=%cI", commit)
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get commit time: %v", err)
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(output)))
+}
+
 // GetFileAtCommit retrieves file content at a specific commit
 func GetFileAtCommit(commit, filePath string) (string, error) {
 	// Make path relative to git root
@@ -46,7 +62,7 @@ func GetFileAtCommit(commit, filePath string) (string, error) {
 		relPath = filePath
 	}
 
-	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", commit, relPath))
+	cmd := gitCommand(filepath.Dir(filePath), "show", fmt.Sprintf("%s:%s", commit, relPath))
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get file at commit: %v", err)
@@ -54,8 +70,8 @@ func GetFileAtCommit(commit, filePath string) (string, error) {
 	return string(output), nil
 }
 
-// CreateWorktree creates a temporary git worktree for a specific commit
-func CreateWorktree(commit string) (string, error) {
+// CreateWorktree creates a temporary worktree of the repository containing dir for a specific commit
+func CreateWorktree(dir, commit string) (string, error) {
 	// Create temp directory
 	tempDir, err := os.MkdirTemp("", "spec-diff-*")
 	if err != nil {
@@ -63,7 +79,7 @@ func CreateWorktree(commit string) (string, error) {
 	}
 
 	// Create worktree
-	cmd := exec.Command("git", "worktree", "add", "--detach", tempDir, commit)
+	cmd := gitCommand(dir, "worktree", "add", "--detach", tempDir, commit)
 	if err := cmd.Run(); err != nil {
 		os.RemoveAll(tempDir)
 		return "", fmt.Errorf("failed to create worktree: %v", err)
@@ -72,19 +88,19 @@ func CreateWorktree(commit string) (string, error) {
 	return tempDir, nil
 }
 
-// RemoveWorktree removes a git worktree
-func RemoveWorktree(path string) error {
+// RemoveWorktree removes a worktree created by CreateWorktree for the repository containing dir
+func RemoveWorktree(dir, path string) error {
 	// Remove from git worktree list
-	cmd := exec.Command("git", "worktree", "remove", "--force", path)
+	cmd := gitCommand(dir, "worktree", "remove", "--force", path)
 	cmd.Run() // Ignore errors, cleanup anyway
 
 	// Remove the directory
 	return os.RemoveAll(path)
 }
 
-// GetGitRoot returns the root directory of the git repository
-func GetGitRoot() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+// GetGitRoot returns the root directory of the git repository containing dir
+func GetGitRoot(dir string) (string, error) {
+	cmd := gitCommand(dir, "rev-parse", "--show-toplevel")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("not in a git repository: %v", err)
@@ -92,15 +108,15 @@ func GetGitRoot() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-// IsGitRepository checks if we're in a git repository
-func IsGitRepository() bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
+// IsGitRepository checks if dir is inside a git repository
+func IsGitRepository(dir string) bool {
+	cmd := gitCommand(dir, "rev-parse", "--git-dir")
 	return cmd.Run() == nil
 }
 
 // getRelativeToGitRoot converts an absolute path to relative to git root
 func getRelativeToGitRoot(absPath string) (string, error) {
-	gitRoot, err := GetGitRoot()
+	gitRoot, err := GetGitRoot(filepath.Dir(absPath))
 	if err != nil {
 		return "", err
 	}
@@ -115,8 +131,8 @@ func getRelativeToGitRoot(absPath string) (string, error) {
 }
 
 // GetChangedFiles returns files changed between two commits
-func GetChangedFiles(oldCommit, newCommit string) ([]string, error) {
-	cmd := exec.Command("git", "diff", "--name-only", oldCommit, newCommit)
+func GetChangedFiles(dir, oldCommit, newCommit string) ([]string, error) {
+	cmd := gitCommand(dir, "diff", "--name-only", oldCommit, newCommit)
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get changed files: %v", err)
@@ -134,8 +150,8 @@ func GetChangedFiles(oldCommit, newCommit string) ([]string, error) {
 }
 
 // GetCommitMessage returns the commit message for a commit
-func GetCommitMessage(commit string) (string, error) {
-	cmd := exec.Command("git", "log", "-1", "--format=%s", commit)
+func GetCommitMessage(dir, commit string) (string, error) {
+	cmd := gitCommand(dir, "log", "-1", "--format=%s", commit)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get commit message: %v", err)
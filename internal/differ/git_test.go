@@ -0,0 +1,78 @@
+package differ
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a git repository with one commit per spec version and returns its path
+func initRepo(t *testing.T, versions ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := gitCommand(dir, args...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	git("init", "-q")
+	if err := os.MkdirAll(filepath.Join(dir, "spec"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range versions {
+		if err := os.WriteFile(filepath.Join(dir, "spec", "MANIFEST.adoc"), []byte(version), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", version)
+	}
+	return dir
+}
+
+func TestGitCommandsUseTheSpecRepository(t *testing.T) {
+	repo := initRepo(t, "= Spec v1\n", "= Spec v2\n")
+	manifest := filepath.Join(repo, "spec", "MANIFEST.adoc")
+	specDir := filepath.Dir(manifest)
+
+	// Run from a directory outside the spec's repository
+	t.Chdir(t.TempDir())
+
+	head, err := GetCurrentCommit(specDir)
+	if err != nil {
+		t.Fatalf("GetCurrentCommit: %v", err)
+	}
+	previous, err := ResolveCommit(specDir, "HEAD~1")
+	if err != nil {
+		t.Fatalf("ResolveCommit: %v", err)
+	}
+	if previous == head {
+		t.Fatalf("HEAD~1 resolved to HEAD (%s)", head)
+	}
+	if msg, _ := GetCommitMessage(specDir, previous); msg != "= Spec v1" {
+		t.Errorf("HEAD~1 message = %q, want the first commit", msg)
+	}
+
+	worktree, err := CreateWorktree(specDir, previous)
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	defer RemoveWorktree(specDir, worktree)
+
+	content, err := os.ReadFile(WorktreeManifestPath(worktree, manifest))
+	if err != nil {
+		t.Fatalf("manifest not found in worktree: %v", err)
+	}
+	if string(content) != "= Spec v1\n" {
+		t.Errorf("worktree manifest = %q, want the first version", content)
+	}
+}
@@ -52,30 +52,27 @@ func ExtractAttributes(content string) map[string]string {
 
 // ExtractAttributesFromFile extracts attributes from a file with line numbers
 func ExtractAttributesFromFile(filePath string) ([]AttributeDefinition, error) {
-	file, err := os.Open(filePath)
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	return ExtractAttributeDefinitions(string(data), filePath), nil
+}
 
+// ExtractAttributeDefinitions extracts attribute definitions from content with line numbers
+func ExtractAttributeDefinitions(content, filePath string) []AttributeDefinition {
 	var attrs []AttributeDefinition
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
-
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-		if matches := attrDefPattern.FindStringSubmatch(line); matches != nil {
+	for i, line := range strings.Split(content, "\n") {
+		if matches := attrDefPattern.FindStringSubmatch(strings.TrimRight(line, "\r")); matches != nil {
 			attrs = append(attrs, AttributeDefinition{
 				Name:     matches[1],
 				Value:    strings.TrimSpace(matches[2]),
 				FilePath: filePath,
-				Line:     lineNum,
+				Line:     i + 1,
 			})
 		}
 	}
-
-	return attrs, scanner.Err()
+	return attrs
 }
 
 // FindAttributeUsages finds all references to a specific attribute in content
@@ -0,0 +1,246 @@
+package parser
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Document is an AsciiDoc source file split into prose, source blocks and tables
+type Document struct {
+	FilePath string
+	Prose    []ProseLine   // Text outside blocks, tables, headings and attribute definitions
+	Blocks   []SourceBlock // Listing and source blocks
+	Tables   []Table
+}
+
+// ProseLine is a line of regular text
+type ProseLine struct {
+	Line    int
+	Text    string
+	Section string // Enclosing section title
+}
+
+// SourceBlock is a delimited listing, literal or source block
+type SourceBlock struct {
+	Language  string // From [source,lang] or ```lang, "" if none
+	FilePath  string
+	StartLine int // Line of the first content line
+	Lines     []string
+	Section   string
+}
+
+// Table is an AsciiDoc |=== table
+type Table struct {
+	FilePath  string
+	StartLine int // Line of the opening |===
+	Section   string
+	Header    []string // nil when the table has no header row
+	Rows      []TableRow
+}
+
+// TableRow is one row of table cells
+type TableRow struct {
+	Line  int // Line of the row's first cell
+	Cells []string
+}
+
+// Block and table syntax
+var (
+	sourceAttrPattern  = regexp.MustCompile(`^\[source(?:%[\w%]+)?\s*(?:,\s*([\w+#.-]+))?.*\]$`)
+	listingDelimiter   = regexp.MustCompile(`^(-{4,}|\.{4,})$`)
+	fenceDelimiter     = regexp.MustCompile("^```\\s*([\\w+#.-]*)")
+	commentDelimiter   = regexp.MustCompile(`^/{4,}$`)
+	tableDelimiter     = regexp.MustCompile(`^\|={3,}$`)
+	blockAttrPattern   = regexp.MustCompile(`^\[.*\]$`)
+	colsAttrPattern    = regexp.MustCompile(`cols="?([^",\]]*(?:,[^",\]]*)*)"?`)
+	cellSpecPattern    = regexp.MustCompile(`^[\d.+*<>^aehlmdsv]*$`)
+	colsMultiplierSpec = regexp.MustCompile(`^(\d+)\*`)
+)
+
+// ParseDocument reads and parses an AsciiDoc file
+func ParseDocument(filePath string) (*Document, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocumentContent(string(data), filePath), nil
+}
+
+// ParseDocumentContent parses AsciiDoc content into prose, source blocks and tables
+func ParseDocumentContent(content, filePath string) *Document {
+	doc := &Document{FilePath: filePath}
+	lines := strings.Split(content, "\n")
+
+	section := ""
+	pendingAttr := "" // Block attribute line ([source,go], [cols=...]) preceding a delimiter
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t\r")
+		lineNum := i + 1
+
+		switch {
+		case commentDelimiter.MatchString(line):
+			i = skipUntil(lines, i, line)
+			pendingAttr = ""
+
+		case strings.HasPrefix(line, "//"):
+			// Line comment
+
+		case tableDelimiter.MatchString(line):
+			end := skipUntil(lines, i, line)
+			doc.Tables = append(doc.Tables, parseTable(lines[i+1:end], lineNum, filePath, section, pendingAttr))
+			i = end
+			pendingAttr = ""
+
+		case listingDelimiter.MatchString(line):
+			end := skipUntil(lines, i, line)
+			block := SourceBlock{
+				FilePath:  filePath,
+				StartLine: lineNum + 1,
+				Lines:     lines[i+1 : end],
+				Section:   section,
+			}
+			if matches := sourceAttrPattern.FindStringSubmatch(pendingAttr); matches != nil {
+				block.Language = strings.ToLower(matches[1])
+			}
+			doc.Blocks = append(doc.Blocks, block)
+			i = end
+			pendingAttr = ""
+
+		case fenceDelimiter.MatchString(line):
+			end := skipUntilPrefix(lines, i, "```")
+			doc.Blocks = append(doc.Blocks, SourceBlock{
+				Language:  strings.ToLower(fenceDelimiter.FindStringSubmatch(line)[1]),
+				FilePath:  filePath,
+				StartLine: lineNum + 1,
+				Lines:     lines[i+1 : end],
+				Section:   section,
+			})
+			i = end
+			pendingAttr = ""
+
+		case sectionPattern.MatchString(line):
+			section = strings.TrimSpace(sectionPattern.FindStringSubmatch(line)[2])
+			pendingAttr = ""
+
+		case blockAttrPattern.MatchString(line):
+			pendingAttr = line
+
+		case attrDefPattern.MatchString(line):
+			// Attribute definition
+
+		case strings.TrimSpace(line) == "":
+			pendingAttr = ""
+
+		default:
+			doc.Prose = append(doc.Prose, ProseLine{Line: lineNum, Text: line, Section: section})
+			pendingAttr = ""
+		}
+	}
+
+	return doc
+}
+
+// skipUntil returns the index of the line closing the block opened at lines[start]
+// (or the last line when the block is unterminated)
+func skipUntil(lines []string, start int, delimiter string) int {
+	for j := start + 1; j < len(lines); j++ {
+		if strings.TrimRight(lines[j], " \t\r") == delimiter {
+			return j
+		}
+	}
+	return len(lines)
+}
+
+func skipUntilPrefix(lines []string, start int, prefix string) int {
+	for j := start + 1; j < len(lines); j++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[j]), prefix) {
+			return j
+		}
+	}
+	return len(lines)
+}
+
+// parseTable groups cells into rows; the column count comes from cols= or the first row
+func parseTable(lines []string, startLine int, filePath, section, attr string) Table {
+	table := Table{FilePath: filePath, StartLine: startLine, Section: section}
+
+	type cell struct {
+		line int
+		text string
+	}
+	var cells []cell
+	firstRowCells := 0
+	firstRowLine := -1
+	headerByBlank := false
+
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		lineNum := startLine + 1 + i
+		if line == "" {
+			if firstRowLine >= 0 && lineNum == firstRowLine+1 && len(cells) == firstRowCells {
+				headerByBlank = true
+			}
+			continue
+		}
+
+		if !strings.Contains(line, "|") || (!strings.HasPrefix(line, "|") && !cellSpecPattern.MatchString(strings.SplitN(line, "|", 2)[0])) {
+			// Continuation of the previous cell
+			if len(cells) > 0 {
+				cells[len(cells)-1].text += " " + line
+			}
+			continue
+		}
+
+		parts := strings.Split(line, "|")
+		added := 0
+		for _, part := range parts[1:] {
+			cells = append(cells, cell{line: lineNum, text: strings.TrimSpace(part)})
+			added++
+		}
+		if firstRowLine < 0 {
+			firstRowLine = lineNum
+			firstRowCells = added
+		}
+	}
+
+	columns := tableColumns(attr)
+	if columns == 0 {
+		columns = firstRowCells
+	}
+	if columns == 0 {
+		return table
+	}
+
+	hasHeader := headerByBlank || strings.Contains(attr, "header")
+	for start := 0; start < len(cells); start += columns {
+		end := min(start+columns, len(cells))
+		row := TableRow{Line: cells[start].line}
+		for _, c := range cells[start:end] {
+			row.Cells = append(row.Cells, c.text)
+		}
+		if hasHeader && table.Header == nil && start == 0 {
+			table.Header = row.Cells
+			continue
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table
+}
+
+// tableColumns returns the column count declared by a cols= attribute, 0 if none
+func tableColumns(attr string) int {
+	matches := colsAttrPattern.FindStringSubmatch(attr)
+	if matches == nil {
+		return 0
+	}
+	spec := strings.TrimSpace(matches[1])
+	if m := colsMultiplierSpec.FindStringSubmatch(spec); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return len(strings.Split(spec, ","))
+}
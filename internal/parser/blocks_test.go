@@ -0,0 +1,68 @@
+package parser
+
+import "testing"
+
+func TestParseDocumentContent(t *testing.T) {
+	content := `== Stack
+
+Runtime is Go 1.22.
+
+[source,go]
+----
+type User struct {
+    ID string
+}
+----
+
+// A comment
+[cols="1,1",options="header"]
+|===
+| Component | Version
+| PostgreSQL | 16.2
+| Redis
+| 7.2
+|===
+
+== API
+
+` + "```json\n{\"id\": 1}\n```\n"
+
+	doc := ParseDocumentContent(content, "stack.adoc")
+
+	if len(doc.Prose) != 1 || doc.Prose[0].Text != "Runtime is Go 1.22." || doc.Prose[0].Line != 3 || doc.Prose[0].Section != "Stack" {
+		t.Errorf("unexpected prose: %+v", doc.Prose)
+	}
+
+	if len(doc.Blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(doc.Blocks))
+	}
+	if doc.Blocks[0].Language != "go" || doc.Blocks[0].StartLine != 7 || len(doc.Blocks[0].Lines) != 3 {
+		t.Errorf("unexpected go block: %+v", doc.Blocks[0])
+	}
+	if doc.Blocks[1].Language != "json" || doc.Blocks[1].Section != "API" {
+		t.Errorf("unexpected json block: %+v", doc.Blocks[1])
+	}
+
+	if len(doc.Tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(doc.Tables))
+	}
+	table := doc.Tables[0]
+	if len(table.Header) != 2 || table.Header[0] != "Component" {
+		t.Errorf("unexpected header: %v", table.Header)
+	}
+	if len(table.Rows) != 2 || table.Rows[1].Cells[0] != "Redis" || table.Rows[1].Cells[1] != "7.2" || table.Rows[1].Line != 17 {
+		t.Errorf("unexpected rows: %+v", table.Rows)
+	}
+}
+
+func TestParseTable_ImplicitHeader(t *testing.T) {
+	content := "|===\n| Name | Type\n\n| id | uuid\n| email | text\n|===\n"
+	doc := ParseDocumentContent(content, "t.adoc")
+
+	if len(doc.Tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(doc.Tables))
+	}
+	if len(doc.Tables[0].Header) != 2 || len(doc.Tables[0].Rows) != 2 {
+		t.Errorf("unexpected table: %+v", doc.Tables[0])
+	}
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// OutlineSection is a section in compiled document order with the text under its heading
+type OutlineSection struct {
+	SectionInfo
+	Body []string // Lines up to the next heading, with included content expanded
+}
+
+// Include options that shift heading levels, e.g. include::api.adoc[leveloffset=+1]
+var levelOffsetPattern = regexp.MustCompile(`leveloffset=([+-]?\d+)`)
+
+// BuildOutline walks the manifest in document order, expanding includes in place
+// Headings inside delimited blocks are ignored; leveloffset on includes is applied,
+// tag filters are not (the whole included file is walked)
+func BuildOutline(manifestPath string) ([]OutlineSection, error) {
+	absPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var outline []OutlineSection
+	if err := walkOutline(absPath, 0, make(map[string]bool), &outline); err != nil {
+		return nil, err
+	}
+	return outline, nil
+}
+
+func walkOutline(filePath string, offset int, visiting map[string]bool, outline *[]OutlineSection) error {
+	if visiting[filePath] {
+		return nil // Include cycle
+	}
+	visiting[filePath] = true
+	defer delete(visiting, filePath)
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	delimiter := "" // Open block delimiter, "" when outside blocks
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimRight(raw, " \t\r")
+
+		if delimiter != "" {
+			if line == delimiter || (delimiter == "```" && strings.HasPrefix(line, "```")) {
+				delimiter = ""
+			}
+			appendBody(outline, line)
+			continue
+		}
+
+		switch {
+		case listingDelimiter.MatchString(line), commentDelimiter.MatchString(line), tableDelimiter.MatchString(line):
+			delimiter = line
+			appendBody(outline, line)
+
+		case fenceDelimiter.MatchString(line):
+			delimiter = "```"
+			appendBody(outline, line)
+
+		case includePattern.MatchString(strings.TrimSpace(line)):
+			matches := includePattern.FindStringSubmatch(strings.TrimSpace(line))
+			childOffset := offset
+			if m := levelOffsetPattern.FindStringSubmatch(matches[2]); m != nil {
+				n, _ := strconv.Atoi(m[1])
+				if strings.HasPrefix(m[1], "+") || strings.HasPrefix(m[1], "-") {
+					childOffset += n
+				} else {
+					childOffset = n
+				}
+			}
+			child := ResolveIncludePath(filepath.Dir(filePath), matches[1])
+			_ = walkOutline(child, childOffset, visiting, outline) // Missing includes are reported by the compile check
+
+		case sectionPattern.MatchString(line):
+			matches := sectionPattern.FindStringSubmatch(line)
+			*outline = append(*outline, OutlineSection{SectionInfo: SectionInfo{
+				Title:     strings.TrimSpace(matches[2]),
+				Level:     len(matches[1]) - 1 + offset,
+				FilePath:  filePath,
+				StartLine: i + 1,
+				EndLine:   -1,
+			}})
+
+		default:
+			appendBody(outline, line)
+		}
+	}
+	return nil
+}
+
+// appendBody adds a line to the most recent section (text before the first heading is dropped)
+func appendBody(outline *[]OutlineSection, line string) {
+	if n := len(*outline); n > 0 {
+		(*outline)[n-1].Body = append((*outline)[n-1].Body, line)
+	}
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildOutline(t *testing.T) {
+	dir := t.TempDir()
+	manifest := "= Spec\n\n== Context\n\ninclude::identity.adoc[leveloffset=+1]\n\n== Core Types\n\n[source,asciidoc]\n----\n== Not A Heading\n----\n"
+	identity := "== Identity\n\n*Name:* Orders\n"
+	if err := os.WriteFile(filepath.Join(dir, "MANIFEST.adoc"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "identity.adoc"), []byte(identity), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outline, err := BuildOutline(filepath.Join(dir, "MANIFEST.adoc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		title string
+		level int
+	}{{"Spec", 0}, {"Context", 1}, {"Identity", 2}, {"Core Types", 1}}
+	if len(outline) != len(want) {
+		t.Fatalf("expected %d sections, got %+v", len(want), outline)
+	}
+	for i, w := range want {
+		if outline[i].Title != w.title || outline[i].Level != w.level {
+			t.Errorf("section %d = %q level %d, want %q level %d", i, outline[i].Title, outline[i].Level, w.title, w.level)
+		}
+	}
+
+	if outline[2].FilePath != filepath.Join(dir, "identity.adoc") || outline[2].StartLine != 1 {
+		t.Errorf("Identity location = %s:%d", outline[2].FilePath, outline[2].StartLine)
+	}
+	found := false
+	for _, line := range outline[2].Body {
+		if line == "*Name:* Orders" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Identity body missing field line: %q", outline[2].Body)
+	}
+}
@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+// AnalyzerIssue is a problem found by a native analyzer, located in the AsciiDoc sources
+type AnalyzerIssue struct {
+	Rule     string `json:"rule"`     // Checklist rule or analyzer ID
+	Severity string `json:"severity"` // error, warning or info
+	File     string `json:"file"`     // Relative to the manifest directory
+	Line     int    `json:"line"`
+	Message  string `json:"message"`
+}
+
+// Location formats the issue position as file:line
+func (i AnalyzerIssue) Location() string {
+	return fmt.Sprintf("%s:%d", i.File, i.Line)
+}
+
+// Analyzer is a native check over the spec sources that needs no LLM
+type Analyzer struct {
+	ID   string
+	Name string
+	Run  func(src *SpecSources) []AnalyzerIssue
+}
+
+// SpecSources is the parsed AsciiDoc source of a spec, shared by all analyzers
+type SpecSources struct {
+	ManifestPath string
+	Structure    *parser.SpecStructure
+	Documents    []*parser.Document // Manifest first, then included files in include order
+	Content      map[string]string  // Raw source by file path
+	Attributes   map[string]string
+	Schema       *SpecSchema // nil uses the embedded default
+
+	attrIndex *attributeIndex // Built on first use by the attribute analyzers
+	types     *TypeSources    // Built on first use by the type analyzers
+}
+
+// Analyzers returns the native analyzers run with the structural checks
+func Analyzers() []Analyzer {
+	return []Analyzer{
+		schemaAnalyzer,
+		dependencyAnalyzer,
+		undefinedAttributeAnalyzer,
+		unusedAttributeAnalyzer,
+		redefinedAttributeAnalyzer,
+		hardcodedValueAnalyzer,
+		typesSQLAnalyzer,
+		typesJSONAnalyzer,
+		undefinedTypeAnalyzer,
+		perfQuantifiedAnalyzer,
+		numericDerivationAnalyzer,
+	}
+}
+
+// LoadSpecSources parses the manifest and every included file
+func LoadSpecSources(manifestPath string) (*SpecSources, error) {
+	structure, err := parser.BuildStructure(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec structure: %w", err)
+	}
+
+	src := &SpecSources{
+		ManifestPath: manifestPath,
+		Structure:    structure,
+		Content:      make(map[string]string),
+		Attributes:   structure.GetAttributeMap(),
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range append([]string{manifestPath}, structure.Files...) {
+		abs, _ := filepath.Abs(path)
+		if seen[abs] {
+			continue
+		}
+		seen[abs] = true
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue // Missing includes are reported by the compile check
+		}
+		src.Content[path] = string(data)
+		src.Documents = append(src.Documents, parser.ParseDocumentContent(string(data), path))
+
+		// Attributes defined in included files apply to the compiled spec too
+		for _, def := range parser.ExtractAttributeDefinitions(string(data), path) {
+			src.Attributes[def.Name] = def.Value
+		}
+	}
+
+	return src, nil
+}
+
+// RelPath returns a source path relative to the manifest directory
+func (s *SpecSources) RelPath(path string) string {
+	base := filepath.Dir(s.ManifestPath)
+	if filepath.IsAbs(path) && !filepath.IsAbs(base) {
+		base, _ = filepath.Abs(base) // Outline paths are absolute
+	}
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// runAnalyzers runs every analyzer and reports each as a structural check
+// Analyzer checks never block semantic validation: their issues are findings
+// counted against --fail-on (see AnalyzerFindings)
+func runAnalyzers(src *SpecSources) []StructuralCheck {
+	var checks []StructuralCheck
+	for _, analyzer := range Analyzers() {
+		checks = append(checks, analyzerCheck(analyzer, analyzer.Run(src)))
+	}
+	return checks
+}
+
+// analyzerCheck reports an analyzer's issues as a structural check
+func analyzerCheck(analyzer Analyzer, issues []AnalyzerIssue) StructuralCheck {
+	check := StructuralCheck{
+		ID:     analyzer.ID,
+		Name:   analyzer.Name,
+		Passed: true,
+		Issues: issues,
+	}
+	if len(issues) == 0 {
+		check.Message = "OK"
+	} else {
+		check.Message = fmt.Sprintf("%d issue(s): %s", len(issues), formatIssueCounts(issues))
+	}
+	return check
+}
+
+// AnalyzerFindings returns the located analyzer issues of checks as findings
+func AnalyzerFindings(checks []StructuralCheck) []Finding {
+	var findings []Finding
+	for _, check := range checks {
+		for _, issue := range check.Issues {
+			findings = append(findings, Finding{
+				Rule:     issue.Rule,
+				Location: issue.Location(),
+				Issue:    issue.Message,
+				Severity: issue.Severity,
+			})
+		}
+	}
+	return findings
+}
+
+func formatIssueCounts(issues []AnalyzerIssue) string {
+	findings := make([]Finding, len(issues))
+	for i, issue := range issues {
+		findings[i].Severity = issue.Severity
+	}
+	return FormatSeverityCounts(findings)
+}
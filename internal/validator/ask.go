@@ -0,0 +1,387 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+// Retrieval limits for cca ask
+const (
+	DefaultAskSections = 5     // Sections sent with a question
+	askExcerptTokens   = 12000 // Excerpts beyond the best match stop at this many tokens
+)
+
+// Words that carry no meaning for section retrieval
+var askStopwords = map[string]bool{
+	"a": true, "about": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"can": true, "do": true, "does": true, "for": true, "from": true, "happen": true, "happens": true,
+	"how": true, "if": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"should": true, "the": true, "there": true, "to": true, "we": true, "what": true, "when": true,
+	"where": true, "which": true, "who": true, "why": true, "with": true, "our": true, "this": true,
+	"that": true, "spec": true, "get": true, "use": true, "used": true,
+}
+
+// Runs of letters and digits; identifiers like rate-limit split into words
+var askWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// Attribute references in source lines, e.g. {login-rate-limit}
+var askAttrPattern = regexp.MustCompile(`\{([A-Za-z0-9_][A-Za-z0-9_-]*)\}`)
+
+// SpecExcerpt is a section retrieved for a question
+type SpecExcerpt struct {
+	Path    string  `json:"section"` // Section path, e.g. "API > Authentication > Login"
+	File    string  `json:"file"`    // Relative to the manifest directory
+	Line    int     `json:"line"`    // Heading line
+	EndLine int     `json:"end_line"`
+	Score   float64 `json:"score"`
+	Content string  `json:"-"` // Attribute-resolved source lines prefixed with "N | "
+}
+
+// Citation points part of an answer at the spec source
+type Citation struct {
+	Section string `json:"section"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+}
+
+// Answer is the provider's answer to a question about the spec
+// Unanswered questions point at gaps in the spec, like a targeted completeness check.
+type Answer struct {
+	Question  string        `json:"question"`
+	Answered  bool          `json:"answered"`
+	Text      string        `json:"answer"`
+	Missing   string        `json:"missing,omitempty"` // What the spec would have to state
+	Citations []Citation    `json:"citations"`
+	Dropped   []string      `json:"dropped,omitempty"` // Citations outside the excerpts sent
+	Excerpts  []SpecExcerpt `json:"excerpts"`
+	Usage     *UsageReport  `json:"usage,omitempty"` // Tokens and cost of the ask call, nil when nothing was sent
+}
+
+// askSection is a candidate section for a question
+type askSection struct {
+	excerpt SpecExcerpt
+	title   []string       // Words of the section's own title
+	parents []string       // Words of the ancestor titles
+	words   map[string]int // Words of the resolved body and the attributes it references
+	order   int            // Position in the document
+}
+
+// RetrieveSections scores every section of the spec against a question and returns up to
+// limit of the best, in document order
+// Scoring is keyword based: body matches over attribute-resolved content, weighted by how
+// rare the word is across sections, plus matches in the section's title and its parents' titles.
+func RetrieveSections(manifestPath, question string, limit int) ([]SpecExcerpt, error) {
+	terms := askTerms(question)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("question has no searchable words: %q", question)
+	}
+	if limit <= 0 {
+		limit = DefaultAskSections
+	}
+
+	src, err := LoadSpecSources(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := askSections(src)
+	if err != nil {
+		return nil, err
+	}
+
+	df := make(map[string]int)
+	for _, s := range sections {
+		for _, term := range terms {
+			if s.words[term] > 0 || slices.Contains(s.title, term) || slices.Contains(s.parents, term) {
+				df[term]++
+			}
+		}
+	}
+
+	var scored []*askSection
+	for _, s := range sections {
+		matched, score := 0, 0.0
+		for _, term := range terms {
+			weight := 0.0
+			if tf := s.words[term]; tf > 0 {
+				weight += 1 + math.Log(float64(tf))
+			}
+			if slices.Contains(s.title, term) {
+				weight += 3
+			} else if slices.Contains(s.parents, term) {
+				weight += 1
+			}
+			if weight > 0 {
+				matched++
+				score += weight * math.Log(1+float64(len(sections))/float64(df[term]))
+			}
+		}
+		// Sections matching more of the question rank above repeated mentions of one word
+		if matched > 0 {
+			s.excerpt.Score = math.Round(score*float64(matched)/float64(len(terms))*100) / 100
+			scored = append(scored, s)
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].excerpt.Score > scored[j].excerpt.Score })
+
+	var picked []*askSection
+	tokens := 0
+	for _, s := range scored {
+		if len(picked) == limit {
+			break
+		}
+		n := EstimateTokens(s.excerpt.Content)
+		if len(picked) > 0 && tokens+n > askExcerptTokens {
+			continue
+		}
+		picked = append(picked, s)
+		tokens += n
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i].order < picked[j].order })
+
+	excerpts := make([]SpecExcerpt, len(picked))
+	for i, s := range picked {
+		excerpts[i] = s.excerpt
+	}
+	return excerpts, nil
+}
+
+// askSections splits the spec into sections with their paths and attribute-resolved source lines
+func askSections(src *SpecSources) ([]*askSection, error) {
+	outline, err := parser.BuildOutline(src.ManifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec outline: %w", err)
+	}
+	resolve := attributeReplacer(src.Attributes)
+
+	files := make(map[string][]string)
+	type parent struct {
+		level int
+		title string
+	}
+	var stack []parent
+	var sections []*askSection
+	for i, section := range outline {
+		for len(stack) > 0 && stack[len(stack)-1].level >= section.Level {
+			stack = stack[:len(stack)-1]
+		}
+		var path, parents []string
+		for _, p := range stack {
+			if p.level > 0 {
+				path = append(path, p.title)
+				parents = append(parents, askWords(p.title)...)
+			}
+		}
+		stack = append(stack, parent{level: section.Level, title: section.Title})
+		path = append(path, section.Title)
+
+		lines, ok := files[section.FilePath]
+		if !ok {
+			data, err := os.ReadFile(section.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			files[section.FilePath] = lines
+		}
+
+		// The section's own lines run to the next heading in the same file
+		end := len(lines)
+		for _, next := range outline[i+1:] {
+			if next.FilePath == section.FilePath && next.StartLine > section.StartLine {
+				end = next.StartLine - 1
+				break
+			}
+		}
+		for end > section.StartLine && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+
+		s := &askSection{
+			excerpt: SpecExcerpt{
+				Path:    strings.Join(path, " > "),
+				File:    src.RelPath(section.FilePath),
+				Line:    section.StartLine,
+				EndLine: end,
+			},
+			title:   askWords(section.Title),
+			parents: parents,
+			words:   make(map[string]int),
+			order:   i,
+		}
+
+		width := len(fmt.Sprint(end))
+		var content strings.Builder
+		for j, line := range lines[section.StartLine-1 : end] {
+			resolved := resolve.Replace(line)
+			fmt.Fprintf(&content, "%*d | %s\n", width, section.StartLine+j, resolved)
+			if j == 0 {
+				continue // The heading is scored as the title
+			}
+			for _, word := range askWords(resolved) {
+				s.words[word]++
+			}
+			for _, ref := range askAttrPattern.FindAllStringSubmatch(line, -1) {
+				for _, word := range askWords(ref[1]) {
+					s.words[word]++
+				}
+			}
+		}
+		s.excerpt.Content = strings.TrimSuffix(content.String(), "\n")
+		sections = append(sections, s)
+	}
+	return sections, nil
+}
+
+// AskSpec retrieves the sections relevant to a question and asks the provider to answer
+// from them with citations
+// When no section matches, the question is reported unanswered without calling the provider.
+// Citations outside the excerpts sent are dropped. A prompt estimated over the configured
+// budget is refused with a *BudgetError.
+func AskSpec(ctx context.Context, manifestPath, question string, limit int, opts ValidationOptions) (*Answer, error) {
+	excerpts, err := RetrieveSections(manifestPath, question, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := &Answer{Question: question, Excerpts: excerpts}
+	if len(excerpts) == 0 {
+		answer.Missing = fmt.Sprintf("No section of the spec mentions %s.", strings.Join(askTerms(question), ", "))
+		return answer, nil
+	}
+
+	prompt, err := opts.Prompts.Render("ask", TemplateData{Question: question, Excerpts: excerpts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render ask prompt: %w", err)
+	}
+
+	stop := startSpinner(ctx, "Searching the spec")
+	resp, usage, err := completeWithinBudget(ctx, "ask", prompt, opts)
+	stop()
+	if err != nil {
+		return nil, err
+	}
+	answer.Usage = usage
+
+	var reply struct {
+		Answered  bool       `json:"answered"`
+		Answer    string     `json:"answer"`
+		Citations []Citation `json:"citations"`
+		Missing   string     `json:"missing"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(resp.Text)), &reply); err != nil {
+		return nil, fmt.Errorf("provider did not return valid answer JSON: %w", err)
+	}
+	answer.Answered = reply.Answered
+	answer.Text = strings.TrimSpace(reply.Answer)
+	answer.Missing = strings.TrimSpace(reply.Missing)
+
+	for _, citation := range reply.Citations {
+		citation.File = filepath.ToSlash(filepath.Clean(citation.File))
+		excerpt := excerptAt(excerpts, citation.File, citation.Line)
+		if excerpt == nil {
+			answer.Dropped = append(answer.Dropped, fmt.Sprintf("%s:%d: not in the sections sent", citation.File, citation.Line))
+			continue
+		}
+		citation.Section = excerpt.Path // The path of the line cited, whatever the provider named
+		answer.Citations = append(answer.Citations, citation)
+	}
+	return answer, nil
+}
+
+// excerptAt returns the excerpt holding a source line, nil if none does
+func excerptAt(excerpts []SpecExcerpt, file string, line int) *SpecExcerpt {
+	for i, e := range excerpts {
+		if e.File == file && line >= e.Line && line <= e.EndLine {
+			return &excerpts[i]
+		}
+	}
+	return nil
+}
+
+// FormatAnswer formats an answer with its citations, or what the spec is missing
+func FormatAnswer(answer *Answer) string {
+	var sb strings.Builder
+	if answer.Text != "" {
+		sb.WriteString(answer.Text + "\n")
+	}
+	if len(answer.Citations) > 0 {
+		sb.WriteString("\nSources:\n")
+		for _, c := range answer.Citations {
+			fmt.Fprintf(&sb, "  %s:%d  %s\n", c.File, c.Line, c.Section)
+		}
+	}
+	if !answer.Answered {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\033[33m⚠\033[0m The spec does not answer this question")
+		if answer.Missing != "" {
+			sb.WriteString(": " + answer.Missing)
+		}
+		sb.WriteString("\n")
+	}
+	for _, dropped := range answer.Dropped {
+		fmt.Fprintf(&sb, "Dropped citation %s\n", dropped)
+	}
+
+	if len(answer.Excerpts) > 0 {
+		sb.WriteString("\nSections searched:\n")
+		for _, e := range answer.Excerpts {
+			fmt.Fprintf(&sb, "  %s:%d  %s\n", e.File, e.Line, e.Path)
+		}
+	}
+	return sb.String()
+}
+
+// FormatAnswerJSON formats the answer as JSON
+func FormatAnswerJSON(answer *Answer) string {
+	if answer.Citations == nil {
+		answer.Citations = []Citation{}
+	}
+	if answer.Excerpts == nil {
+		answer.Excerpts = []SpecExcerpt{}
+	}
+	data, _ := json.MarshalIndent(answer, "", "  ")
+	return string(data)
+}
+
+// askTerms returns the distinct searchable words of a question
+func askTerms(question string) []string {
+	var terms []string
+	for _, word := range askWordPattern.FindAllString(strings.ToLower(question), -1) {
+		if askStopwords[word] {
+			continue
+		}
+		if term := askStem(word); !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// askWords lowercases text into words, folding plurals so "limits" matches "limit"
+func askWords(text string) []string {
+	words := askWordPattern.FindAllString(strings.ToLower(text), -1)
+	for i, word := range words {
+		words[i] = askStem(word)
+	}
+	return words
+}
+
+// askStem drops a plural "s" (but not the "ss" of "address")
+func askStem(word string) string {
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func writeAskSpec(t *testing.T) string {
+	t.Helper()
+	manifest := "= Accounts\n:login-rate-limit: 5 attempts per minute\n\n== Context\n\nAccount service for the storefront.\n\ninclude::api.adoc[]\n"
+	api := "== API\n\n=== Authentication\n\nTokens are JWTs signed with RS256.\n\n==== Login\n\nPOST /login checks the password.\nRate limit: {login-rate-limit} per IP.\n\n=== Users\n\nPOST /users registers an account.\nA duplicate email returns 409 Conflict.\n"
+	return writeSpecFiles(t, map[string]string{"MANIFEST.adoc": manifest, "api.adoc": api})
+}
+
+func TestRetrieveSections(t *testing.T) {
+	manifest := writeAskSpec(t)
+
+	excerpts, err := RetrieveSections(manifest, "What's the rate limit for login?", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(excerpts) == 0 {
+		t.Fatal("no sections retrieved")
+	}
+	var login *SpecExcerpt
+	for i := range excerpts {
+		if excerpts[i].Path == "API > Authentication > Login" {
+			login = &excerpts[i]
+		}
+	}
+	if login == nil {
+		t.Fatalf("login section not retrieved: %+v", excerpts)
+	}
+	if login.File != "api.adoc" || login.Line != 7 || login.EndLine != 10 {
+		t.Errorf("login excerpt = %s:%d-%d", login.File, login.Line, login.EndLine)
+	}
+	if !strings.Contains(login.Content, "10 | Rate limit: 5 attempts per minute per IP.") {
+		t.Errorf("excerpt is not numbered and resolved:\n%s", login.Content)
+	}
+
+	excerpts, err = RetrieveSections(manifest, "What happens on duplicate emails?", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(excerpts) != 1 || excerpts[0].Path != "API > Users" {
+		t.Errorf("excerpts = %+v, want API > Users", excerpts)
+	}
+
+	if _, err := RetrieveSections(manifest, "what is it?", 0); err == nil {
+		t.Error("expected an error for a question without searchable words")
+	}
+}
+
+func TestAskSpec(t *testing.T) {
+	manifest := writeAskSpec(t)
+	p := NewScriptedProvider("```json\n" + `{"answered": true, "answer": "5 attempts per minute per IP.", "citations": [
+		{"section": "Login", "file": "api.adoc", "line": 10},
+		{"section": "Context", "file": "MANIFEST.adoc", "line": 99}
+	], "missing": ""}` + "\n```")
+
+	answer, err := AskSpec(context.Background(), manifest, "What's the rate limit for login?", 0, ValidationOptions{Provider: p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(p.Prompts()[0], "### API > Authentication > Login (api.adoc)") {
+		t.Errorf("prompt lacks the login excerpt:\n%s", p.Prompts()[0])
+	}
+	if !answer.Answered || len(answer.Citations) != 1 || answer.Citations[0].Section != "API > Authentication > Login" {
+		t.Errorf("answer = %+v", answer)
+	}
+	if answer.Usage == nil || len(answer.Usage.Calls) != 1 || answer.Usage.Calls[0].Label != "ask" {
+		t.Errorf("expected usage for one ask call, got %+v", answer.Usage)
+	}
+	if len(answer.Dropped) != 1 || !strings.HasPrefix(answer.Dropped[0], "MANIFEST.adoc:99") {
+		t.Errorf("dropped = %v", answer.Dropped)
+	}
+	if out := FormatAnswer(answer); !strings.Contains(out, "api.adoc:10  API > Authentication > Login") {
+		t.Errorf("formatted answer:\n%s", out)
+	}
+}
+
+func TestAskSpec_OverBudget(t *testing.T) {
+	manifest := writeAskSpec(t)
+	p := NewScriptedProvider(`{"answered": true}`)
+
+	_, err := AskSpec(context.Background(), manifest, "What's the rate limit for login?", 0, ValidationOptions{Provider: p, Budget: config.BudgetConfig{Tokens: 100}})
+
+	var overBudget *BudgetError
+	if !errors.As(err, &overBudget) {
+		t.Fatalf("err = %v, want a BudgetError", err)
+	}
+	if len(p.Prompts()) != 0 {
+		t.Error("over-budget question should not call the provider")
+	}
+}
+
+func TestAskSpec_NoMatchingSection(t *testing.T) {
+	manifest := writeAskSpec(t)
+	p := NewScriptedProvider()
+
+	answer, err := AskSpec(context.Background(), manifest, "Which message broker?", 0, ValidationOptions{Provider: p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer.Answered || len(p.Prompts()) != 0 {
+		t.Errorf("expected an unanswered question without a provider call, got %+v", answer)
+	}
+	if out := FormatAnswer(answer); !strings.Contains(out, "The spec does not answer this question: No section of the spec mentions message, broker.") {
+		t.Errorf("formatted answer:\n%s", out)
+	}
+}
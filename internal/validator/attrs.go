@@ -0,0 +1,429 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+// Attribute hygiene analyzer IDs
+const (
+	ruleUndefinedAttribute = "undefined-attribute"
+	ruleUnusedAttribute    = "unused-attribute"
+	ruleRedefinedAttribute = "redefined-attribute"
+	ruleHardcodedValue     = "hardcoded-value"
+)
+
+var (
+	undefinedAttributeAnalyzer = Analyzer{
+		ID:   ruleUndefinedAttribute,
+		Name: "Attribute references defined",
+		Run:  analyzeUndefinedAttributes,
+	}
+	unusedAttributeAnalyzer = Analyzer{
+		ID:   ruleUnusedAttribute,
+		Name: "Attributes used",
+		Run:  analyzeUnusedAttributes,
+	}
+	redefinedAttributeAnalyzer = Analyzer{
+		ID:   ruleRedefinedAttribute,
+		Name: "Attributes defined once",
+		Run:  analyzeRedefinedAttributes,
+	}
+	hardcodedValueAnalyzer = Analyzer{
+		ID:   ruleHardcodedValue,
+		Name: "Attribute values referenced, not repeated",
+		Run:  analyzeHardcodedValues,
+	}
+)
+
+// builtinAttributes are provided or consumed by asciidoctor itself, so they are
+// neither undefined when referenced nor unused when set
+var builtinAttributes = map[string]bool{
+	// Character replacements
+	"amp": true, "apos": true, "asterisk": true, "backslash": true, "backtick": true,
+	"blank": true, "brvbar": true, "caret": true, "cpp": true, "deg": true, "empty": true,
+	"endsb": true, "gt": true, "ldquo": true, "lsquo": true, "lt": true, "nbsp": true,
+	"plus": true, "pp": true, "quot": true, "rdquo": true, "rsquo": true, "sp": true,
+	"startsb": true, "tilde": true, "two-colons": true, "two-semicolons": true,
+	"vbar": true, "wj": true, "zwsp": true,
+	// Document metadata and intrinsics
+	"author": true, "authors": true, "email": true, "revnumber": true, "revdate": true,
+	"revremark": true, "description": true, "keywords": true, "doctitle": true,
+	"docname": true, "docdate": true, "doctime": true, "docyear": true, "docdir": true,
+	"docfile": true, "localdate": true, "localtime": true, "localyear": true,
+	"attribute-missing": true, "attribute-undefined": true,
+	// Processing configuration
+	"doctype": true, "toc": true, "toclevels": true, "toc-title": true, "sectnums": true,
+	"sectnumlevels": true, "sectanchors": true, "sectlinks": true, "idprefix": true,
+	"idseparator": true, "icons": true, "imagesdir": true, "source-highlighter": true,
+	"source-language": true, "experimental": true, "stem": true, "nofooter": true,
+	"noheader": true, "hardbreaks": true, "linkattrs": true, "xrefstyle": true,
+	"table-caption": true, "figure-caption": true, "example-caption": true,
+	"appendix-caption": true, "version-label": true, "lang": true, "stylesheet": true,
+	"linkcss": true, "data-uri": true, "outfilesuffix": true, "leveloffset": true,
+	"includedir": true, "partnums": true, "chapter-signifier": true,
+}
+
+// inlineCodePattern matches `inline code` spans, where {name} is usually a literal placeholder
+var inlineCodePattern = regexp.MustCompile("`[^`]*`")
+
+// attributeIndex collects attribute definitions and references across all spec files
+type attributeIndex struct {
+	Definitions []parser.AttributeDefinition
+	Usages      []parser.AttributeUsage // All references, including source blocks and comments
+	TextUsages  []parser.AttributeUsage // References in text that asciidoctor substitutes
+}
+
+// attributes builds the attribute index on first use
+func (s *SpecSources) attributes() *attributeIndex {
+	if s.attrIndex != nil {
+		return s.attrIndex
+	}
+
+	index := &attributeIndex{}
+	for _, doc := range s.Documents {
+		content := s.Content[doc.FilePath]
+		literal := literalLines(doc)
+
+		for _, def := range parser.ExtractAttributeDefinitions(content, doc.FilePath) {
+			if !literal[def.Line] {
+				index.Definitions = append(index.Definitions, def)
+			}
+		}
+		for _, usage := range parser.FindAllAttributeUsages(content, doc.FilePath) {
+			index.Usages = append(index.Usages, usage)
+			if literal[usage.Line] || strings.HasPrefix(usage.Context, "//") {
+				continue
+			}
+			// `/users/{id}` is a route placeholder, not an attribute reference
+			if !strings.Contains(inlineCodePattern.ReplaceAllString(usage.Context, ""), "{"+usage.Name+"}") {
+				continue
+			}
+			index.TextUsages = append(index.TextUsages, usage)
+		}
+	}
+
+	s.attrIndex = index
+	return index
+}
+
+// literalLines returns the lines inside source and listing blocks, which are not substituted
+func literalLines(doc *parser.Document) map[int]bool {
+	lines := make(map[int]bool)
+	for _, block := range doc.Blocks {
+		for i := range block.Lines {
+			lines[block.StartLine+i] = true
+		}
+	}
+	return lines
+}
+
+// defined returns the names with at least one definition
+func (idx *attributeIndex) defined() map[string]bool {
+	names := make(map[string]bool)
+	for _, def := range idx.Definitions {
+		names[def.Name] = true
+	}
+	return names
+}
+
+// analyzeUndefinedAttributes reports {name} references with no definition
+// asciidoctor leaves them in the output as literal text
+func analyzeUndefinedAttributes(src *SpecSources) []AnalyzerIssue {
+	idx := src.attributes()
+	defined := idx.defined()
+
+	var issues []AnalyzerIssue
+	for _, usage := range idx.TextUsages {
+		if defined[usage.Name] || builtinAttributes[usage.Name] {
+			continue
+		}
+		msg := fmt.Sprintf("{%s} is not defined", usage.Name)
+		if suggestion := closestAttribute(usage.Name, defined); suggestion != "" {
+			msg += fmt.Sprintf(" (did you mean {%s}?)", suggestion)
+		}
+		issues = append(issues, AnalyzerIssue{
+			Rule:     ruleUndefinedAttribute,
+			Severity: SeverityError,
+			File:     src.RelPath(usage.FilePath),
+			Line:     usage.Line,
+			Message:  msg,
+		})
+	}
+	return issues
+}
+
+// analyzeUnusedAttributes reports definitions that nothing references
+func analyzeUnusedAttributes(src *SpecSources) []AnalyzerIssue {
+	idx := src.attributes()
+
+	used := make(map[string]bool)
+	for _, usage := range idx.Usages {
+		used[usage.Name] = true
+	}
+
+	var issues []AnalyzerIssue
+	reported := make(map[string]bool)
+	for _, def := range idx.Definitions {
+		if used[def.Name] || builtinAttributes[def.Name] || reported[def.Name] {
+			continue
+		}
+		reported[def.Name] = true
+		issues = append(issues, AnalyzerIssue{
+			Rule:     ruleUnusedAttribute,
+			Severity: SeverityWarning,
+			File:     src.RelPath(def.FilePath),
+			Line:     def.Line,
+			Message:  fmt.Sprintf(":%s: is defined but never referenced", def.Name),
+		})
+	}
+	return issues
+}
+
+// analyzeRedefinedAttributes reports attributes set more than once
+// The later definition silently wins for everything after it
+func analyzeRedefinedAttributes(src *SpecSources) []AnalyzerIssue {
+	idx := src.attributes()
+
+	first := make(map[string]parser.AttributeDefinition)
+	var issues []AnalyzerIssue
+	for _, def := range idx.Definitions {
+		prev, ok := first[def.Name]
+		if !ok {
+			first[def.Name] = def
+			continue
+		}
+
+		severity := SeverityWarning
+		msg := fmt.Sprintf(":%s: is redefined with the same value (first defined at %s:%d)",
+			def.Name, src.RelPath(prev.FilePath), prev.Line)
+		if def.Value != prev.Value {
+			severity = SeverityError
+			msg = fmt.Sprintf(":%s: is redefined as %q, shadowing %q from %s:%d",
+				def.Name, def.Value, prev.Value, src.RelPath(prev.FilePath), prev.Line)
+		}
+		issues = append(issues, AnalyzerIssue{
+			Rule:     ruleRedefinedAttribute,
+			Severity: severity,
+			File:     src.RelPath(def.FilePath),
+			Line:     def.Line,
+			Message:  msg,
+		})
+	}
+	return issues
+}
+
+// analyzeHardcodedValues reports literal text that repeats a distinctive attribute value,
+// e.g. "100ms" written by hand where {api-p99-latency} exists
+func analyzeHardcodedValues(src *SpecSources) []AnalyzerIssue {
+	idx := src.attributes()
+
+	// Value -> attribute names, for values specific enough to be a deliberate repeat
+	byValue := make(map[string][]string)
+	for _, def := range idx.Definitions {
+		if !distinctiveValue(def.Value) {
+			continue
+		}
+		names := byValue[def.Value]
+		if !slices.Contains(names, def.Name) {
+			byValue[def.Value] = append(names, def.Name)
+		}
+	}
+	if len(byValue) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(byValue))
+	for value := range byValue {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	patterns := make(map[string]*regexp.Regexp, len(values))
+	for _, value := range values {
+		patterns[value] = regexp.MustCompile(`(?:^|[^\w.{}-])` + regexp.QuoteMeta(value) + `(?:$|[^\w.}-]|\.(?:\W|$))`)
+	}
+
+	var issues []AnalyzerIssue
+	check := func(file string, line int, text string) {
+		for _, value := range values {
+			if !patterns[value].MatchString(text) {
+				continue
+			}
+			refs := make([]string, len(byValue[value]))
+			for i, name := range byValue[value] {
+				refs[i] = "{" + name + "}"
+			}
+			issues = append(issues, AnalyzerIssue{
+				Rule:     ruleHardcodedValue,
+				Severity: SeverityInfo,
+				File:     src.RelPath(file),
+				Line:     line,
+				Message:  fmt.Sprintf("%q is hard-coded - use %s", value, strings.Join(refs, " or ")),
+			})
+		}
+	}
+
+	for _, doc := range src.Documents {
+		for _, prose := range doc.Prose {
+			check(doc.FilePath, prose.Line, prose.Text)
+		}
+		for _, table := range doc.Tables {
+			for _, row := range table.Rows {
+				for _, cell := range row.Cells {
+					check(doc.FilePath, row.Line, cell)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// distinctiveValue reports values unlikely to repeat by coincidence: a number with a
+// unit or version dots ("100ms", "16.2"), or a bare number of four or more digits
+func distinctiveValue(value string) bool {
+	if len(value) < 3 || len(value) > 40 || strings.ContainsAny(value, " {") {
+		return false
+	}
+	hasDigit, hasOther := false, false
+	for _, r := range value {
+		if unicode.IsDigit(r) {
+			hasDigit = true
+		} else {
+			hasOther = true
+		}
+	}
+	if !hasDigit {
+		return false
+	}
+	return hasOther || len(value) >= 4
+}
+
+// closestAttribute suggests a defined name within two edits of a misspelled reference
+func closestAttribute(name string, defined map[string]bool) string {
+	best, bestDist := "", 3
+	for candidate := range defined {
+		if d := editDistance(name, candidate); d < bestDist || (d == bestDist && candidate < best) {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+// AttributeIssues runs the attribute hygiene analyzers only
+func AttributeIssues(src *SpecSources) []AnalyzerIssue {
+	var issues []AnalyzerIssue
+	for _, analyzer := range []Analyzer{undefinedAttributeAnalyzer, unusedAttributeAnalyzer, redefinedAttributeAnalyzer, hardcodedValueAnalyzer} {
+		issues = append(issues, analyzer.Run(src)...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// AttributeSummary is one defined attribute with its reference count
+type AttributeSummary struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	References int    `json:"references"`
+}
+
+// SummarizeAttributes lists each defined attribute at its first definition
+func SummarizeAttributes(src *SpecSources) []AttributeSummary {
+	idx := src.attributes()
+
+	refs := make(map[string]int)
+	for _, usage := range idx.Usages {
+		refs[usage.Name]++
+	}
+
+	var summaries []AttributeSummary
+	seen := make(map[string]bool)
+	for _, def := range idx.Definitions {
+		if seen[def.Name] {
+			continue
+		}
+		seen[def.Name] = true
+		summaries = append(summaries, AttributeSummary{
+			Name:       def.Name,
+			Value:      def.Value,
+			File:       src.RelPath(def.FilePath),
+			Line:       def.Line,
+			References: refs[def.Name],
+		})
+	}
+	return summaries
+}
+
+// FormatIssues lists analyzer issues one per line as file:line: [severity] rule: message
+func FormatIssues(issues []AnalyzerIssue) string {
+	var sb strings.Builder
+	for _, issue := range issues {
+		sb.WriteString(fmt.Sprintf("%s: [%s] %s: %s\n", issue.Location(), issue.Severity, issue.Rule, issue.Message))
+	}
+	return sb.String()
+}
+
+// FormatIssuesJSON formats analyzer issues as JSON
+func FormatIssuesJSON(issues []AnalyzerIssue) string {
+	if issues == nil {
+		issues = []AnalyzerIssue{}
+	}
+	data, _ := json.MarshalIndent(issues, "", "  ")
+	return string(data)
+}
+
+// FormatAttributeSummary renders defined attributes as an aligned table
+func FormatAttributeSummary(summaries []AttributeSummary) string {
+	if len(summaries) == 0 {
+		return "No attributes defined.\n"
+	}
+
+	rows := [][]string{{"NAME", "VALUE", "REFS", "DEFINED"}}
+	for _, a := range summaries {
+		rows = append(rows, []string{a.Name, a.Value, fmt.Sprint(a.References), fmt.Sprintf("%s:%d", a.File, a.Line)})
+	}
+	return formatColumns(rows)
+}
+
+// FormatAttributeSummaryJSON formats defined attributes as JSON
+func FormatAttributeSummaryJSON(summaries []AttributeSummary) string {
+	if summaries == nil {
+		summaries = []AttributeSummary{}
+	}
+	data, _ := json.MarshalIndent(summaries, "", "  ")
+	return string(data)
+}
@@ -0,0 +1,113 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func writeAttrSpec(t *testing.T) string {
+	t.Helper()
+	manifest := `= Orders
+:api-p99-latency: 100ms
+:db-pool: 25
+:unused-port: 8080
+:toc: left
+
+include::api.adoc[]
+
+== Performance
+
+Requests complete within {api-p99-latency}; the pool holds {db-pool} connections.
+Reads are served from cache within {api-p99-latancy}.
+`
+	api := `:db-pool: 40
+
+== API
+
+Every call must return within 100ms.
+
+` + "`GET /orders/{id}`" + ` returns one order.
+
+[source,go]
+----
+var routes = map[string]string{"{id}": "order"}
+----
+
+|===
+| Endpoint | Latency
+
+| /orders
+| 100ms
+|===
+`
+	return writeSpecFiles(t, map[string]string{"MANIFEST.adoc": manifest, "api.adoc": api})
+}
+
+func TestAttributeIssues(t *testing.T) {
+	src, err := LoadSpecSources(writeAttrSpec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues := AttributeIssues(src)
+	got := make([]string, len(issues))
+	for i, issue := range issues {
+		got[i] = issue.Location() + " " + issue.Rule
+	}
+
+	want := []string{
+		"MANIFEST.adoc:4 unused-attribute",
+		"MANIFEST.adoc:12 undefined-attribute",
+		"api.adoc:1 redefined-attribute",
+		"api.adoc:5 hardcoded-value",
+		"api.adoc:17 hardcoded-value",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if !strings.Contains(issues[1].Message, "did you mean {api-p99-latency}?") {
+		t.Errorf("missing suggestion: %q", issues[1].Message)
+	}
+	if issues[2].Severity != SeverityError || !strings.Contains(issues[2].Message, `shadowing "25" from MANIFEST.adoc:3`) {
+		t.Errorf("unexpected redefinition issue: %+v", issues[2])
+	}
+	if !strings.Contains(issues[3].Message, "use {api-p99-latency}") {
+		t.Errorf("unexpected hard-coded message: %q", issues[3].Message)
+	}
+}
+
+func TestSummarizeAttributes(t *testing.T) {
+	src, err := LoadSpecSources(writeAttrSpec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summaries := SummarizeAttributes(src)
+	if len(summaries) != 4 {
+		t.Fatalf("expected 4 attributes, got %+v", summaries)
+	}
+	if summaries[0].Name != "api-p99-latency" || summaries[0].References != 1 {
+		t.Errorf("unexpected summary: %+v", summaries[0])
+	}
+	if summaries[1].Name != "db-pool" || summaries[1].Line != 3 {
+		t.Errorf("redefinition should keep the first location: %+v", summaries[1])
+	}
+}
+
+func TestDistinctiveValue(t *testing.T) {
+	tests := map[string]bool{
+		"100ms": true,
+		"16.2":  true,
+		"8080":  true,
+		"25":    false,
+		"100":   false,
+		"left":  false,
+		"5 min": false,
+	}
+	for value, want := range tests {
+		if got := distinctiveValue(value); got != want {
+			t.Errorf("distinctiveValue(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
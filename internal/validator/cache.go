@@ -0,0 +1,213 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultCacheMaxAge is how old an entry must be before `cca cache prune` removes it
+const DefaultCacheMaxAge = 7 * 24 * time.Hour
+
+// CacheKey identifies a semantic validation result
+// Any change to the spec, the prompt, the provider/model or the rule set yields a new key
+type CacheKey struct {
+	Mode       string `json:"mode"` // "standard" or "ultra"
+	SpecHash   string `json:"spec_hash"`
+	PromptHash string `json:"prompt_hash"`
+	Provider   string `json:"provider"`
+	Model      string `json:"model"`
+	RulesHash  string `json:"rules_hash"`
+}
+
+// ID returns the content-addressed identifier used as the cache file name
+func (k CacheKey) ID() string {
+	return hashStrings(k.Mode, k.SpecHash, k.PromptHash, k.Provider, k.Model, k.RulesHash)
+}
+
+// CacheEntry is a stored provider result
+type CacheEntry struct {
+	Key       CacheKey  `json:"key"`
+	Output    string    `json:"output"`
+	Findings  []Finding `json:"findings"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ResponseCache stores semantic validation results on disk
+type ResponseCache struct {
+	Dir string
+}
+
+// CacheStats summarizes the cache contents
+type CacheStats struct {
+	Entries int
+	Bytes   int64
+}
+
+// NewResponseCache returns the cache stored under the spec's .cca directory
+func NewResponseCache(manifestPath string) *ResponseCache {
+	return &ResponseCache{Dir: filepath.Join(StateDir(manifestPath), "cache")}
+}
+
+// Get returns the entry for key, or nil if there is none
+func (c *ResponseCache) Get(key CacheKey) (*CacheEntry, error) {
+	data, err := os.ReadFile(c.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, nil // Corrupt entries are treated as misses and overwritten
+	}
+	return &entry, nil
+}
+
+// Put stores output and the findings extracted from it under key
+func (c *ResponseCache) Put(key CacheKey, output string, findings []Finding) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(CacheEntry{
+		Key:       key,
+		Output:    output,
+		Findings:  findings,
+		CreatedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path(key), data, 0644)
+}
+
+// Prune removes entries older than maxAge (0 removes everything)
+func (c *ResponseCache) Prune(maxAge time.Duration) (int, error) {
+	entries, err := os.ReadDir(c.Dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	cutoff := time.Now().Add(-maxAge)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if maxAge > 0 && info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.Dir, e.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Stats counts the entries and bytes in the cache
+func (c *ResponseCache) Stats() (CacheStats, error) {
+	var stats CacheStats
+	entries, err := os.ReadDir(c.Dir)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			stats.Entries++
+			stats.Bytes += info.Size()
+		}
+	}
+	return stats, nil
+}
+
+func (c *ResponseCache) path(key CacheKey) string {
+	return filepath.Join(c.Dir, key.ID()+".json")
+}
+
+// semanticCacheKey builds the cache key for a semantic validation run
+func semanticCacheKey(compiledSpec string, opts ValidationOptions) (CacheKey, error) {
+	prompt, err := opts.Prompts.Render("validate", opts.validateData(compiledSpec))
+	if err != nil {
+		return CacheKey{}, err
+	}
+
+	mode := "standard"
+	promptHash := hashStrings(prompt)
+	if opts.Ultra {
+		mode = fmt.Sprintf("ultra:%d:%.2f:%t", opts.ultraRuns(), opts.consensusThreshold(), opts.Synthesize)
+		synthesis, err := opts.Prompts.Source("synthesize")
+		if err != nil {
+			return CacheKey{}, err
+		}
+		promptHash = hashStrings(prompt, synthesis)
+	}
+	if opts.Chunks != nil {
+		mode += fmt.Sprintf(":chunked:%d", len(opts.Chunks.Chunks))
+		outline, err := opts.Prompts.Source("outline")
+		if err != nil {
+			return CacheKey{}, err
+		}
+		// Chunk sizes that give the same number of chunks still split the spec differently
+		parts := []string{promptHash, outline, opts.Chunks.Preamble}
+		for _, chunk := range opts.Chunks.Chunks {
+			parts = append(parts, chunk.Content)
+		}
+		promptHash = hashStrings(parts...)
+	}
+
+	p := opts.provider()
+	return CacheKey{
+		Mode:       mode,
+		SpecHash:   hashStrings(compiledSpec),
+		PromptHash: promptHash,
+		Provider:   p.Name(),
+		Model:      p.Model(),
+		RulesHash:  rulesFingerprint(opts.Prompts),
+	}, nil
+}
+
+// rulesFingerprint hashes the enabled built-in and custom rule set
+func rulesFingerprint(prompts PromptConfig) string {
+	var parts []string
+	for _, rule := range checklistRules(prompts.Disabled) {
+		parts = append(parts, rule.ID, rule.Checklist)
+	}
+	for _, rule := range prompts.CustomRules {
+		parts = append(parts, rule.ID, rule.Description, strings.Join(rule.Examples, "\n"))
+	}
+	return hashStrings(parts...)
+}
+
+// hashStrings returns a hex SHA-256 over the given parts
+func hashStrings(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"testing"
+)
+
+func TestResponseCache_RoundTrip(t *testing.T) {
+	cache := &ResponseCache{Dir: t.TempDir()}
+	key := CacheKey{Mode: "standard", SpecHash: "a", PromptHash: "b", Provider: "claude-cli"}
+
+	if entry, err := cache.Get(key); err != nil || entry != nil {
+		t.Fatalf("expected miss on empty cache, got %v / %v", entry, err)
+	}
+
+	if err := cache.Put(key, "Specification passes all checks.", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry, err := cache.Get(key)
+	if err != nil || entry == nil {
+		t.Fatalf("expected hit, got %v / %v", entry, err)
+	}
+	if entry.Output != "Specification passes all checks." {
+		t.Errorf("unexpected output: %q", entry.Output)
+	}
+
+	other := key
+	other.Model = "different-model"
+	if entry, _ := cache.Get(other); entry != nil {
+		t.Error("changing the model should miss the cache")
+	}
+}
+
+func TestResponseCache_Prune(t *testing.T) {
+	cache := &ResponseCache{Dir: t.TempDir()}
+	cache.Put(CacheKey{SpecHash: "1"}, "one", nil)
+	cache.Put(CacheKey{SpecHash: "2"}, "two", nil)
+
+	removed, err := cache.Prune(DefaultCacheMaxAge)
+	if err != nil || removed != 0 {
+		t.Errorf("fresh entries should survive, removed %d (%v)", removed, err)
+	}
+
+	removed, err = cache.Prune(0)
+	if err != nil || removed != 2 {
+		t.Errorf("expected 2 entries removed, got %d (%v)", removed, err)
+	}
+
+	stats, _ := cache.Stats()
+	if stats.Entries != 0 {
+		t.Errorf("expected empty cache, got %d entries", stats.Entries)
+	}
+}
+
+func TestSemanticCacheKey_ChunkBoundaries(t *testing.T) {
+	spec := "## A\n\nOne.\n\n## B\n\nTwo.\n\n## C\n\nThree.\n"
+	key := func(chunks ...string) CacheKey {
+		plan := &ChunkPlan{Preamble: "## Context\n"}
+		for _, content := range chunks {
+			plan.Chunks = append(plan.Chunks, SpecChunk{Content: content})
+		}
+		k, err := semanticCacheKey(spec, ValidationOptions{Provider: NewScriptedProvider(), Chunks: plan})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	// Two chunk sizes that both give two chunks, split at different sections
+	small := key("## A\n\nOne.\n\n", "## B\n\nTwo.\n\n## C\n\nThree.\n")
+	large := key("## A\n\nOne.\n\n## B\n\nTwo.\n\n", "## C\n\nThree.\n")
+	if small.ID() == large.ID() {
+		t.Error("chunk plans with different boundaries share a cache key")
+	}
+	if again := key("## A\n\nOne.\n\n", "## B\n\nTwo.\n\n## C\n\nThree.\n"); again.ID() != small.ID() {
+		t.Error("identical chunk plans should share a cache key")
+	}
+}
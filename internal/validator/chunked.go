@@ -0,0 +1,369 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/compiler"
+)
+
+// Chunked validation defaults
+const (
+	DefaultChunkSize        = 30 * 1024 // Leaves room for the prompt, preamble and reply
+	DefaultChunkConcurrency = 4
+)
+
+// Rules that need the whole spec: chunk prompts skip them and the outline pass checks them
+var crossChunkRules = []string{"types-complete", "file-tree", "context-section"}
+
+// outlineRules returns the cross-chunk rules that are not disabled
+func outlineRules(disabled map[string]bool) []Rule {
+	var rules []Rule
+	for _, id := range crossChunkRules {
+		if rule, ok := LookupRule(id); ok && !disabled[id] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// outlineRuleIDs returns the IDs of the cross-chunk rules that are not disabled
+func outlineRuleIDs(disabled map[string]bool) []string {
+	var ids []string
+	for _, rule := range outlineRules(disabled) {
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
+
+// Matches declaration lines inside code blocks, kept in the outline for cross-chunk checks
+var declarationPattern = regexp.MustCompile(`(?i)^\s*(export\s+)?(type|struct|interface|class|enum|message|record|create\s+table)\s+\S`)
+
+// SpecChunk is a section-aligned slice of a compiled spec
+type SpecChunk struct {
+	Titles  []string // Section titles in this chunk, in document order
+	Content string
+}
+
+// ChunkPlan is a compiled spec split for chunked validation
+type ChunkPlan struct {
+	Preamble string      // Context section and attribute table shared by every chunk
+	Chunks   []SpecChunk // Sections outside the context, in document order
+	Outline  string      // Headings and declarations of the whole spec, for cross-chunk rules
+	Titles   []string    // Every section title in the spec
+}
+
+// ChunkScope tells the validate prompt which part of a larger spec it is reviewing
+type ChunkScope struct {
+	Part      int
+	Total     int
+	Sections  []string
+	SkipRules []string // Rules checked separately over the outline
+}
+
+// BuildChunkPlan splits a compiled spec into chunks of roughly maxSize bytes
+// Chunks break at headings; a chunk starting inside a subsection repeats its parent headings.
+// A section larger than maxSize on its own is split between paragraphs, repeating its heading.
+// Context sections go to the shared preamble together with the attribute table.
+func BuildChunkPlan(compiledSpec string, attrs map[string]string, maxSize int) *ChunkPlan {
+	if maxSize <= 0 {
+		maxSize = DefaultChunkSize
+	}
+
+	sections := compiler.SplitMarkdownSections(compiledSpec)
+	plan := &ChunkPlan{Outline: buildOutline(sections)}
+
+	isContext := make(map[int]bool)
+	var preamble strings.Builder
+	for _, i := range contextSectionIndexes(sections) {
+		isContext[i] = true
+		preamble.WriteString(sections[i].Content)
+	}
+	preamble.WriteString(formatAttributeTable(attrs))
+	plan.Preamble = strings.TrimSpace(preamble.String())
+
+	var chunk SpecChunk
+	var body strings.Builder
+	var parents []compiler.MarkdownSection // Enclosing headings of the current section
+
+	for i, section := range sections {
+		plan.Titles = append(plan.Titles, section.Title)
+
+		for len(parents) > 0 && parents[len(parents)-1].Level >= section.Level {
+			parents = parents[:len(parents)-1]
+		}
+
+		if !isContext[i] {
+			for j, piece := range splitSection(section.Content, maxSize) {
+				if body.Len() > 0 && body.Len()+len(piece) > maxSize {
+					chunk.Content = body.String()
+					plan.Chunks = append(plan.Chunks, chunk)
+					chunk = SpecChunk{}
+					body.Reset()
+				}
+				if body.Len() == 0 {
+					for _, parent := range parents {
+						body.WriteString(headingLine(parent) + "\n\n")
+					}
+					if j > 0 {
+						body.WriteString(headingLine(section) + "\n\n")
+					}
+				}
+				body.WriteString(piece)
+				if j == 0 || len(chunk.Titles) == 0 {
+					chunk.Titles = append(chunk.Titles, section.Title)
+				}
+			}
+		}
+
+		parents = append(parents, section)
+	}
+	if len(chunk.Titles) > 0 {
+		chunk.Content = body.String()
+		plan.Chunks = append(plan.Chunks, chunk)
+	}
+
+	return plan
+}
+
+// headingLine returns the heading line of a section
+func headingLine(section compiler.MarkdownSection) string {
+	return strings.SplitN(section.Content, "\n", 2)[0]
+}
+
+// splitSection splits a section's content between paragraphs into pieces of at most
+// maxSize bytes; a single paragraph or code block larger than that stays whole
+func splitSection(content string, maxSize int) []string {
+	if len(content) <= maxSize {
+		return []string{content}
+	}
+
+	var pieces []string
+	var piece strings.Builder
+	inFence := false
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		piece.WriteString(line)
+		// A blank line outside a code block ends a paragraph
+		if trimmed == "" && !inFence && piece.Len() > 0 {
+			pieces = appendParagraph(pieces, piece.String(), maxSize)
+			piece.Reset()
+		}
+	}
+	if piece.Len() > 0 {
+		pieces = appendParagraph(pieces, piece.String(), maxSize)
+	}
+	return pieces
+}
+
+// appendParagraph adds a paragraph to the last piece, or starts a new piece when it would overflow
+func appendParagraph(pieces []string, paragraph string, maxSize int) []string {
+	if n := len(pieces); n > 0 && len(pieces[n-1])+len(paragraph) <= maxSize {
+		pieces[n-1] += paragraph
+		return pieces
+	}
+	return append(pieces, paragraph)
+}
+
+// RunChunkedValidation validates each chunk in parallel (bounded by opts.chunkConcurrency())
+// plus a final outline pass for cross-chunk rules, and merges the findings
+func RunChunkedValidation(ctx context.Context, plan *ChunkPlan, opts ValidationOptions) ([]Finding, error) {
+	p := opts.provider()
+
+	prompts, err := chunkPrompts(plan, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failed chunk fails the run, so stop the others early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	replies := make([]string, len(prompts))
+	errs := make([]error, len(prompts))
+	sem := make(chan struct{}, opts.chunkConcurrency())
+	var wg sync.WaitGroup
+
+	for i, prompt := range prompts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			label := "outline"
+			if i < len(plan.Chunks) {
+				label = fmt.Sprintf("chunk %d/%d", i+1, len(plan.Chunks))
+			}
+			resp, err := p.Complete(withCallLabel(ctx, label), prompt)
+			if err != nil {
+				errs[i] = err
+				cancel()
+				return
+			}
+			replies[i] = resp.Text
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil || errors.Is(err, context.Canceled) {
+			continue
+		}
+		if i == len(plan.Chunks) {
+			return nil, fmt.Errorf("outline pass failed: %w", err)
+		}
+		return nil, fmt.Errorf("chunk %d of %d failed: %w", i+1, len(plan.Chunks), err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool)
+	for _, rule := range crossChunkRules {
+		skip[rule] = true
+	}
+
+	var findings []Finding
+	for _, reply := range replies[:len(plan.Chunks)] {
+		for _, f := range ParseFindings(reply) {
+			if !skip[strings.ToLower(f.Rule)] {
+				findings = append(findings, f)
+			}
+		}
+	}
+	if len(replies) > len(plan.Chunks) {
+		findings = append(findings, ParseFindings(replies[len(plan.Chunks)])...)
+	}
+
+	AssignSections(findings, plan.Titles)
+	return dedupeFindings(findings), nil
+}
+
+// chunkPrompts renders the validate prompt for every chunk, followed by the outline prompt
+func chunkPrompts(plan *ChunkPlan, opts ValidationOptions) ([]string, error) {
+	var prompts []string
+	for i, chunk := range plan.Chunks {
+		prompt, err := opts.Prompts.Render("validate", TemplateData{
+			CompiledSpec: chunk.Content,
+			Preamble:     plan.Preamble,
+			Scope: &ChunkScope{
+				Part:      i + 1,
+				Total:     len(plan.Chunks),
+				Sections:  chunk.Titles,
+				SkipRules: outlineRuleIDs(opts.Prompts.Disabled),
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render prompt: %w", err)
+		}
+		prompts = append(prompts, prompt)
+	}
+
+	// With every cross-chunk rule disabled there is nothing for the outline pass to check
+	if len(outlineRules(opts.Prompts.Disabled)) == 0 {
+		return prompts, nil
+	}
+	outlinePrompt, err := opts.Prompts.Render("outline", TemplateData{
+		CompiledSpec: plan.Outline,
+		Preamble:     plan.Preamble,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render outline prompt: %w", err)
+	}
+	return append(prompts, outlinePrompt), nil
+}
+
+// dedupeFindings merges findings for the same rule, location and issue (e.g. context issues
+// reported by every chunk), keeping the most detailed suggestion
+func dedupeFindings(findings []Finding) []Finding {
+	var merged []Finding
+	index := make(map[string]int)
+
+	for _, f := range findings {
+		key := consensusKey(f) + "|" + normalizeIssue(f.Issue)
+		idx, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, f)
+			continue
+		}
+		if len(f.Suggestion) > len(merged[idx].Suggestion) {
+			merged[idx].Suggestion = f.Suggestion
+		}
+		merged[idx].Severity = moreSevere(merged[idx].Severity, f.Severity)
+	}
+	return merged
+}
+
+// normalizeIssue reduces issue text to lower-case words, so case and punctuation do not matter
+func normalizeIssue(issue string) string {
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(issue), " "), " ")
+}
+
+// buildOutline lists every heading with the declarations found in its code blocks
+func buildOutline(sections []compiler.MarkdownSection) string {
+	var sb strings.Builder
+	for _, section := range sections {
+		indent := strings.Repeat("  ", max(section.Level-1, 0))
+		sb.WriteString(fmt.Sprintf("%s- %s\n", indent, section.Title))
+
+		inFence := false
+		for _, line := range strings.Split(section.Content, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				inFence = !inFence
+				continue
+			}
+			if inFence && declarationPattern.MatchString(trimmed) {
+				sb.WriteString(fmt.Sprintf("%s    %s\n", indent, truncateLine(trimmed, 100)))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// formatAttributeTable renders spec attributes as a Markdown table
+func formatAttributeTable(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("\n## Attributes\n\n| Attribute | Value |\n|---|---|\n")
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", name, strings.ReplaceAll(attrs[name], "|", `\|`)))
+	}
+	return sb.String()
+}
+
+// truncateLine cuts line to at most n bytes without splitting a multi-byte character
+func truncateLine(line string, n int) string {
+	if len(line) <= n {
+		return line
+	}
+	for n > 0 && !utf8.RuneStart(line[n]) {
+		n--
+	}
+	return line[:n] + "..."
+}
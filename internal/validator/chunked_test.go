@@ -0,0 +1,167 @@
+package validator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const chunkedSpec = `# Orders Service
+
+## Context
+
+### Identity
+
+Order intake for the storefront.
+
+## Storage
+
+` + "```go\ntype Order struct {\n\tID string\n\tItems []LineItem\n}\n```" + `
+
+PostgreSQL stores orders.
+
+## API
+
+### Create Order
+
+POST /orders creates an order.
+
+### Get Order
+
+GET /orders/{id} returns an order.
+`
+
+func TestBuildChunkPlan(t *testing.T) {
+	plan := BuildChunkPlan(chunkedSpec, map[string]string{"db-version": "16.2"}, 80)
+
+	if !strings.Contains(plan.Preamble, "### Identity") || !strings.Contains(plan.Preamble, "| db-version | 16.2 |") {
+		t.Errorf("preamble missing context or attributes:\n%s", plan.Preamble)
+	}
+
+	var titles []string
+	for _, chunk := range plan.Chunks {
+		if strings.Contains(chunk.Content, "Order intake") {
+			t.Errorf("context section leaked into chunk %v", chunk.Titles)
+		}
+		titles = append(titles, chunk.Titles...)
+	}
+	// Storage alone is larger than a chunk, so it is split after its code block
+	want := []string{"Orders Service", "Storage", "Storage", "API", "Create Order", "Get Order"}
+	if strings.Join(titles, "|") != strings.Join(want, "|") {
+		t.Errorf("chunk titles = %v, want %v", titles, want)
+	}
+	if len(plan.Chunks) < 3 {
+		t.Fatalf("expected the spec to be split, got %d chunks", len(plan.Chunks))
+	}
+
+	// A chunk starting inside a subsection repeats its parent heading,
+	// and one continuing a split section repeats the section heading
+	for _, chunk := range plan.Chunks {
+		if chunk.Titles[0] == "Get Order" && !strings.Contains(chunk.Content, "## API\n\n### Get Order") {
+			t.Errorf("chunk for Get Order lacks parent heading:\n%s", chunk.Content)
+		}
+		if strings.Contains(chunk.Content, "PostgreSQL stores orders.") && !strings.Contains(chunk.Content, "## Storage\n\nPostgreSQL") {
+			t.Errorf("continued Storage chunk lacks its heading:\n%s", chunk.Content)
+		}
+		if len(chunk.Content) > 80+len("# Orders Service\n\n## Storage\n\n") {
+			t.Errorf("chunk over the size limit:\n%s", chunk.Content)
+		}
+	}
+
+	if !strings.Contains(plan.Outline, "- Storage") || !strings.Contains(plan.Outline, "type Order struct {") {
+		t.Errorf("outline missing heading or declaration:\n%s", plan.Outline)
+	}
+}
+
+func TestRunChunkedValidation(t *testing.T) {
+	plan := BuildChunkPlan(chunkedSpec, nil, 80)
+
+	// Every call returns the same reply: the context issue must be merged across chunks
+	// and types-complete must only survive from the outline pass
+	reply := "- Rule: context-section\n- Location: Context\n- Issue: Missing Stack\n\n" +
+		"- Rule: types-complete\n- Location: Storage\n- Issue: LineItem is never defined\n"
+	p := &ScriptedProvider{}
+	for i := 0; i <= len(plan.Chunks); i++ {
+		p.Replies = append(p.Replies, ScriptedReply{Text: reply})
+	}
+
+	findings, err := RunChunkedValidation(context.Background(), plan, ValidationOptions{Provider: p, ChunkConcurrency: 2})
+	if err != nil {
+		t.Fatalf("RunChunkedValidation failed: %v", err)
+	}
+
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 merged findings, got %d: %+v", len(findings), findings)
+	}
+	if calls := len(p.Prompts()); calls != len(plan.Chunks)+1 {
+		t.Errorf("calls = %d, want %d (chunks + outline)", calls, len(plan.Chunks)+1)
+	}
+
+	parts := 0
+	for _, prompt := range p.Prompts() {
+		if strings.Contains(prompt, "You are reviewing part") {
+			parts++
+		}
+	}
+	if parts != len(plan.Chunks) {
+		t.Errorf("%d prompts were scoped to a chunk, want %d", parts, len(plan.Chunks))
+	}
+}
+
+func TestDedupeFindings(t *testing.T) {
+	findings := dedupeFindings([]Finding{
+		{Rule: "error-handling", Section: "API", Issue: "POST /orders has no error responses"},
+		{Rule: "error-handling", Section: "API", Issue: "POST /orders has no error responses.", Suggestion: "List 400 and 409"},
+		{Rule: "error-handling", Section: "API", Issue: "DELETE /orders/{id} has no 404"},
+	})
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	if findings[0].Suggestion != "List 400 and 409" {
+		t.Errorf("suggestion not kept from the duplicate: %+v", findings[0])
+	}
+}
+
+func TestChunkPrompts_DisabledOutlineRules(t *testing.T) {
+	plan := BuildChunkPlan(chunkedSpec, nil, 80)
+
+	opts := ValidationOptions{Prompts: PromptConfig{Disabled: map[string]bool{"file-tree": true}}}
+	prompts, err := chunkPrompts(plan, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outline := prompts[len(prompts)-1]
+	if !strings.Contains(outline, "1. **types-complete**") || !strings.Contains(outline, "2. **context-section**") || strings.Contains(outline, "file-tree") {
+		t.Errorf("outline rules not filtered:\n%s", outline)
+	}
+
+	opts.Prompts.Disabled = map[string]bool{"file-tree": true, "types-complete": true, "context-section": true}
+	if prompts, err = chunkPrompts(plan, opts); err != nil || len(prompts) != len(plan.Chunks) {
+		t.Errorf("got %d prompts (%v), want only the %d chunks", len(prompts), err, len(plan.Chunks))
+	}
+}
+
+func TestTruncateLine(t *testing.T) {
+	if got := truncateLine("type Größe struct", 8); got != "type Gr..." {
+		t.Errorf("got %q, want the cut before the multi-byte ö", got)
+	}
+	if got := truncateLine("type Order", 20); got != "type Order" {
+		t.Errorf("short line changed: %q", got)
+	}
+}
+
+func TestSplitSection(t *testing.T) {
+	content := "## Schema\n\nFirst paragraph.\n\n```sql\nCREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n```\n\nLast paragraph.\n"
+	pieces := splitSection(content, 40)
+	if strings.Join(pieces, "") != content {
+		t.Fatalf("pieces do not add up to the section: %q", pieces)
+	}
+	for _, piece := range pieces {
+		if strings.Contains(piece, "CREATE TABLE a") != strings.Contains(piece, "CREATE TABLE b") {
+			t.Errorf("code block split across pieces: %q", pieces)
+		}
+	}
+	if len(pieces) != 3 {
+		t.Errorf("got %d pieces, want 3: %q", len(pieces), pieces)
+	}
+}
@@ -4,167 +4,188 @@ import (
 	"bufio"
 	"bytes"
 	"context"
-	"embed"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
-	"os/signal"
 	"strings"
-	"syscall"
-	"text/template"
-	"time"
 
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
 	"golang.org/x/term"
 )
 
-//go:embed prompts/*.tmpl
-var promptTemplates embed.FS
-
 // Size thresholds for warnings
 const (
-	SizeWarningThreshold = 20 * 1024  // 20KB - warn user
-	SizeLargeThreshold   = 50 * 1024  // 50KB - strongly warn
+	SizeWarningThreshold = 20 * 1024 // 20KB - warn user
+	SizeLargeThreshold   = 50 * 1024 // 50KB - strongly warn
 )
 
 // ValidationOptions controls validation behavior
 type ValidationOptions struct {
-	SkipConfirm bool // --yes flag: skip size confirmation
-	Ultra       bool // --ultra flag: multi-run validation with synthesis
-	JSON        bool // --json flag: output JSON (for CI)
+	SkipConfirm bool                    // --yes flag: skip size confirmation
+	Ultra       bool                    // --ultra flag: multi-run validation with consensus scoring
+	Runs        int                     // --runs flag: ultra run count (0 uses DefaultUltraRuns)
+	Threshold   float64                 // --threshold flag: minimum consensus confidence (0 uses default)
+	Synthesize  bool                    // --synthesize flag: add an LLM synthesis of all ultra runs
+	Quorum      int                     // --quorum flag: minimum successful ultra runs (0 uses a majority)
+	JSON        bool                    // --json flag: output JSON (for CI)
+	Since       string                  // --since flag: only validate sections changed since this git ref
+	Stream      bool                    // Print replies as they arrive (the CLI sets this when stdout is a terminal)
+	Provider    Provider                // LLM used for semantic validation (nil uses the claude CLI)
+	NoCache     bool                    // --no-cache flag: always call the provider
+	Prompts     PromptConfig            // Template overrides and custom rules from .spec.yaml
+	Severities  map[string]string       // Configured rule severities from .spec.yaml
+	Disabled    map[string]bool         // Rules turned off in .spec.yaml
+	FailOn      string                  // --fail-on flag: lowest severity that fails validation
+	Pricing     map[string]config.Price // Per-model prices for cost estimates
+	Budget      config.BudgetConfig     // Refuse runs estimated above this instead of prompting
+	Schema      *SpecSchema             // Required sections and fields (nil uses the embedded default)
+	Section     string                  // --section flag: validate one section and its subsections
+	File        string                  // --file flag: validate one spec file
+	Slice       *SpecSlice              // Set by Validate for --section and --file
+	Incremental *IncrementalSpec        // Set by Validate for --since
+
+	NoChunk          bool       // --no-chunk flag: send large specs in a single call
+	ChunkSize        int        // Target chunk size in bytes (0 uses DefaultChunkSize)
+	ChunkConcurrency int        // Chunks validated in parallel (0 uses DefaultChunkConcurrency)
+	Chunks           *ChunkPlan // Set by Validate when a large spec is split into chunks
 }
 
-// TemplateData holds data passed to prompt templates
-type TemplateData struct {
-	CompiledSpec string
-	Run1         string
-	Run2         string
-	Run3         string
+// validateData is the validate prompt data for the spec, with the slice scope for --section
+// and --file or the changed sections for --since
+func (o ValidationOptions) validateData(compiledSpec string) TemplateData {
+	data := TemplateData{CompiledSpec: compiledSpec}
+	if o.Slice != nil {
+		data.Slice = o.Slice
+		data.Preamble = o.Slice.Context
+	}
+	if o.Incremental != nil {
+		data.Incremental = o.Incremental
+	}
+	return data
 }
 
-// LoadPromptTemplate loads and parses a prompt template
-func LoadPromptTemplate(name string) (*template.Template, error) {
-	return template.ParseFS(promptTemplates, "prompts/"+name+".tmpl")
+// ultraRuns returns the number of ultra runs to perform
+func (o ValidationOptions) ultraRuns() int {
+	if o.Runs <= 0 {
+		return DefaultUltraRuns
+	}
+	return o.Runs
 }
 
-// RenderPrompt renders a prompt template with data
-func RenderPrompt(templateName string, data TemplateData) (string, error) {
-	tmpl, err := LoadPromptTemplate(templateName)
-	if err != nil {
-		return "", fmt.Errorf("failed to load template: %w", err)
+// quorum returns the minimum number of ultra runs that must succeed
+func (o ValidationOptions) quorum() int {
+	runs := o.ultraRuns()
+	if o.Quorum <= 0 {
+		return runs/2 + 1
 	}
-
-	var buf bytes.Buffer
-	if err := tmpl.ExecuteTemplate(&buf, templateName, data); err != nil {
-		return "", fmt.Errorf("failed to render template: %w", err)
+	if o.Quorum > runs {
+		return runs
 	}
+	return o.Quorum
+}
 
-	return buf.String(), nil
+// consensusThreshold returns the minimum confidence for ultra findings
+func (o ValidationOptions) consensusThreshold() float64 {
+	if o.Threshold <= 0 {
+		return DefaultConsensusThreshold
+	}
+	return o.Threshold
 }
 
-// runClaudeValidationQuiet runs validation without spinner (for parallel runs)
-func runClaudeValidationQuiet(ctx context.Context, compiledSpec string, output io.Writer) error {
-	prompt, err := RenderPrompt("validate", TemplateData{
-		CompiledSpec: compiledSpec,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to render prompt: %w", err)
+// chunkConcurrency returns how many chunks are validated at once
+func (o ValidationOptions) chunkConcurrency() int {
+	if o.ChunkConcurrency <= 0 {
+		return DefaultChunkConcurrency
 	}
+	return o.ChunkConcurrency
+}
 
-	cmd := exec.CommandContext(ctx, "claude", "--print", "--no-session-persistence")
-	cmd.Stdin = strings.NewReader(prompt)
-	cmd.Stdout = output
-	cmd.Stderr = os.Stderr
+// provider returns the configured provider, defaulting to the claude CLI
+func (o ValidationOptions) provider() Provider {
+	if o.Provider == nil {
+		return DefaultProvider()
+	}
+	return o.Provider
+}
 
-	if err := cmd.Run(); err != nil {
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
-		return fmt.Errorf("claude CLI failed: %w", err)
+// runValidationQuiet runs validation without spinner (for parallel runs)
+func runValidationQuiet(ctx context.Context, compiledSpec string, output io.Writer, opts ValidationOptions) error {
+	text, err := validateText(ctx, compiledSpec, opts)
+	if err != nil {
+		return err
 	}
 
+	fmt.Fprint(output, text)
 	return nil
 }
 
-// RunClaudeValidation shells out to claude CLI for semantic validation
-// It streams output directly to the provided writer
-func RunClaudeValidation(compiledSpec string, output io.Writer) error {
-	// Render the prompt
-	prompt, err := RenderPrompt("validate", TemplateData{
-		CompiledSpec: compiledSpec,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to render prompt: %w", err)
+// validateText runs the validate prompt over the spec and returns the reply
+// With a chunk plan the merged chunk findings are returned in the same format
+func validateText(ctx context.Context, compiledSpec string, opts ValidationOptions) (string, error) {
+	if opts.Chunks != nil {
+		findings, err := RunChunkedValidation(ctx, opts.Chunks, opts)
+		if err != nil {
+			return "", err
+		}
+		if len(findings) == 0 {
+			return "Specification passes all checks.\n", nil
+		}
+		return FormatFindings(findings), nil
 	}
 
-	// Check if claude CLI is available
-	if _, err := exec.LookPath("claude"); err != nil {
-		return fmt.Errorf("claude CLI not found in PATH - install from https://claude.ai/code")
+	prompt, err := opts.Prompts.Render("validate", opts.validateData(compiledSpec))
+	if err != nil {
+		return "", fmt.Errorf("failed to render prompt: %w", err)
 	}
 
-	// Run claude with prompt via stdin (avoids command line length limits)
-	// Using --print for non-interactive mode
-	cmd := exec.Command("claude", "--print", "--no-session-persistence")
-	cmd.Stdin = strings.NewReader(prompt)
-
-	// Capture stdout to buffer while showing spinner
-	var resultBuf bytes.Buffer
-	cmd.Stdout = &resultBuf
-	cmd.Stderr = os.Stderr
-
-	// Show spinner only if stderr is a terminal
-	showSpinner := isTTY()
-	if showSpinner {
-		fmt.Fprint(os.Stderr, "Running Claude validation ")
+	resp, err := opts.provider().Complete(ctx, prompt)
+	if err != nil {
+		return "", err
 	}
+	return resp.Text, nil
+}
 
-	done := make(chan bool)
-	if showSpinner {
-		go func() {
-			spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-			i := 0
-			for {
-				select {
-				case <-done:
-					return
-				default:
-					fmt.Fprintf(os.Stderr, "\rRunning Claude validation %s", spinner[i%len(spinner)])
-					i++
-					time.Sleep(100 * time.Millisecond)
-				}
-			}
-		}()
+// RunSemanticValidation sends the validate prompt to the provider, writes the reply
+// to the provided writer and returns it
+// With opts.Stream the reply is written as it arrives and the progress indicator moves to a
+// status line below it; chunked specs are validated in parallel and always buffered.
+func RunSemanticValidation(ctx context.Context, compiledSpec string, output io.Writer, opts ValidationOptions) (string, error) {
+	if err := opts.provider().Available(); err != nil {
+		return "", err
 	}
 
-	err = cmd.Run()
-	if showSpinner {
-		done <- true
-		fmt.Fprint(os.Stderr, "\r                                    \r")
+	label := "Running " + ProviderLabel(opts.provider()) + " validation"
+	if opts.Stream && opts.Chunks == nil {
+		stream := startStream(ctx, output, label)
+		text, err := validateText(withStream(ctx, stream), compiledSpec, opts)
+		streamed := stream.Stop()
+		if err != nil {
+			return "", err
+		}
+		if !streamed {
+			fmt.Fprint(output, text)
+		}
+		return text, nil
 	}
 
+	stop := startSpinner(ctx, label)
+	text, err := validateText(ctx, compiledSpec, opts)
+	stop()
+
 	if err != nil {
-		return fmt.Errorf("claude CLI failed: %w", err)
+		return "", err
 	}
 
 	// Write the captured output
-	fmt.Fprint(output, resultBuf.String())
-
-	return nil
-}
+	fmt.Fprint(output, text)
 
-// RunClaudeValidationToString runs validation and returns result as string
-func RunClaudeValidationToString(compiledSpec string) (string, error) {
-	var buf bytes.Buffer
-	if err := RunClaudeValidation(compiledSpec, &buf); err != nil {
-		return "", err
-	}
-	return buf.String(), nil
+	return text, nil
 }
 
-// IsClaudeAvailable checks if claude CLI is installed
-func IsClaudeAvailable() bool {
-	_, err := exec.LookPath("claude")
-	return err == nil
+// RunSemanticValidationToString runs validation and returns result as string
+func RunSemanticValidationToString(ctx context.Context, compiledSpec string, opts ValidationOptions) (string, error) {
+	opts.Stream = false
+	return RunSemanticValidation(ctx, compiledSpec, io.Discard, opts)
 }
 
 // isTTY checks if stderr is a terminal (for spinner support)
@@ -172,133 +193,191 @@ func isTTY() bool {
 	return term.IsTerminal(int(os.Stderr.Fd()))
 }
 
-// RunUltraValidation runs validation 3 times in parallel and synthesizes results
-func RunUltraValidation(compiledSpec string, output io.Writer) error {
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer cancel()
+// UltraResult is the outcome of a multi-run validation
+type UltraResult struct {
+	Findings        []Finding // Consensus findings at or above the threshold
+	DegradedRuns    []int     // Runs (1-based) that failed and were left out of the consensus
+	SynthesisFailed bool      // Synthesis was requested but failed
+}
+
+// RunUltraValidation runs validation N times in parallel and scores agreement in Go
+// Issues are clustered by rule and location; confidence is the fraction of completed runs that found them.
+// Failed runs are reported as degraded; the report is produced as long as opts.quorum() runs succeed.
+// With opts.Synthesize an LLM synthesis of all runs is appended to the report.
+func RunUltraValidation(ctx context.Context, compiledSpec string, output io.Writer, opts ValidationOptions) (*UltraResult, error) {
+	p := opts.provider()
+	runCount := opts.ultraRuns()
+	quorum := opts.quorum()
+
+	if err := p.Available(); err != nil {
+		return nil, err
+	}
 
-	// Run 3 validations concurrently (silent)
+	// Run N validations concurrently (silent)
 	type result struct {
 		output string
 		err    error
 		index  int
 	}
-	results := make(chan result, 3)
+	results := make(chan result, runCount)
 
-	for i := 0; i < 3; i++ {
+	progress := startRunProgress(ctx, runCount)
+	for i := 0; i < runCount; i++ {
 		go func(idx int) {
 			var buf bytes.Buffer
-			err := runClaudeValidationQuiet(ctx, compiledSpec, &buf)
+			err := runValidationQuiet(withCallLabel(ctx, fmt.Sprintf("run %d", idx+1)), compiledSpec, &buf, opts)
+			progress.Finish(idx, err)
 			results <- result{output: buf.String(), err: err, index: idx}
 		}(i)
 	}
 
-	// Show spinner only if stderr is a terminal
-	showSpinner := isTTY()
-	done := make(chan bool)
-
-	if showSpinner {
-		go func() {
-			spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-			i := 0
-			for {
-				select {
-				case <-done:
-					return
-				case <-ctx.Done():
-					return
-				default:
-					fmt.Fprintf(os.Stderr, "\rRunning Claude validation %s", spinner[i%len(spinner)])
-					i++
-					time.Sleep(100 * time.Millisecond)
-				}
-			}
-		}()
-	}
-
-	// Collect results
-	runs := make([]string, 3)
-	for i := 0; i < 3; i++ {
+	// Collect results, keeping successful runs when others fail
+	runs := make([]string, runCount)
+	runErrs := make([]error, runCount)
+	for i := 0; i < runCount; i++ {
 		r := <-results
-		if r.err != nil {
-			if showSpinner {
-				done <- true
-			}
-			if ctx.Err() != nil {
-				return fmt.Errorf("cancelled")
-			}
-			return fmt.Errorf("validation failed: %w", r.err)
-		}
 		runs[r.index] = r.output
+		runErrs[r.index] = r.err
 	}
+	progress.Stop()
 
-	if showSpinner {
-		done <- true
-		fmt.Fprint(os.Stderr, "\r                                    \r")
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
 	}
 
-	// Synthesize results
-	synthesisPrompt, err := RenderPrompt("synthesize", TemplateData{
-		Run1: runs[0],
-		Run2: runs[1],
-		Run3: runs[2],
-	})
-	if err != nil {
-		return fmt.Errorf("failed to render synthesis prompt: %w", err)
+	ultra := &UltraResult{}
+	runFindings := make([][]Finding, runCount)
+	var completedRuns []string
+	for i, err := range runErrs {
+		if err != nil {
+			ultra.DegradedRuns = append(ultra.DegradedRuns, i+1)
+			fmt.Fprintf(output, "Run %d: failed (degraded): %v\n", i+1, err)
+			continue // nil entry: BuildConsensus treats it as a failed run
+		}
+		runFindings[i] = ParseFindings(runs[i])
+		if runFindings[i] == nil {
+			runFindings[i] = []Finding{} // Completed run with no findings
+		}
+		completedRuns = append(completedRuns, runs[i])
 	}
 
-	cmd := exec.CommandContext(ctx, "claude", "--print", "--no-session-persistence")
-	cmd.Stdin = strings.NewReader(synthesisPrompt)
+	completed := len(completedRuns)
+	if completed < quorum {
+		return nil, fmt.Errorf("only %d of %d runs succeeded (quorum %d)", completed, runCount, quorum)
+	}
+	if len(ultra.DegradedRuns) > 0 {
+		fmt.Fprintf(output, "Continuing with %d of %d runs (quorum %d)\n\n", completed, runCount, quorum)
+	}
 
-	var resultBuf bytes.Buffer
-	cmd.Stdout = &resultBuf
-	cmd.Stderr = os.Stderr
+	threshold := opts.consensusThreshold()
+	consensus := BuildConsensus(runFindings, sectionTitles(compiledSpec))
+	accepted, rejected := SplitByThreshold(consensus, threshold)
+	ultra.Findings = accepted
+	fmt.Fprint(output, FormatConsensus(accepted, rejected, completed, threshold))
 
-	if err := cmd.Run(); err != nil {
-		if ctx.Err() != nil {
-			return fmt.Errorf("cancelled")
+	if !opts.Synthesize {
+		return ultra, nil
+	}
+
+	// Optional LLM synthesis of the successful runs
+	synthesisPrompt, err := opts.Prompts.Render("synthesize", TemplateData{Runs: completedRuns})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render synthesis prompt: %w", err)
+	}
+
+	if opts.Stream {
+		// The header goes first so the synthesis can stream under it
+		fmt.Fprint(output, "\n=== Synthesis ===\n\n")
+		stream := startStream(ctx, output, "Synthesizing runs")
+		resp, err := p.Complete(withStream(withCallLabel(ctx, "synthesis"), stream), synthesisPrompt)
+		streamed := stream.Stop()
+		if err != nil {
+			return synthesisFailed(ctx, ultra, output, err)
 		}
-		return fmt.Errorf("synthesis failed: %w", err)
+		if !streamed {
+			fmt.Fprint(output, resp.Text)
+		}
+		return ultra, nil
 	}
 
-	fmt.Fprint(output, resultBuf.String())
-	return nil
+	stop := startSpinner(ctx, "Synthesizing runs")
+	resp, err := p.Complete(withCallLabel(ctx, "synthesis"), synthesisPrompt)
+	stop()
+	if err != nil {
+		return synthesisFailed(ctx, ultra, output, err)
+	}
+
+	fmt.Fprint(output, "\n=== Synthesis ===\n\n")
+	fmt.Fprint(output, resp.Text)
+	return ultra, nil
 }
 
-// CheckSpecSize checks spec size and prompts for confirmation if large
+// synthesisFailed reports a failed synthesis call; the consensus report is already complete,
+// so synthesis is best effort unless the run was cancelled
+func synthesisFailed(ctx context.Context, ultra *UltraResult, output io.Writer, err error) (*UltraResult, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	fmt.Fprintf(output, "\nSynthesis failed (degraded): %v\n", err)
+	ultra.SynthesisFailed = true
+	return ultra, nil
+}
+
+// CheckSpecSize estimates the run and prompts for confirmation if the spec is large
+// With a budget configured, runs within budget proceed without prompting and runs over it are refused
 // Returns true if should proceed, false if user cancelled
 func CheckSpecSize(compiledSpec string, opts ValidationOptions, output io.Writer) (bool, error) {
 	size := len(compiledSpec)
+	budgeted := opts.Budget.Tokens > 0 || opts.Budget.Cost > 0
 
-	if size < SizeWarningThreshold {
+	if size < SizeWarningThreshold && !budgeted {
 		return true, nil // Small spec, no warning needed
 	}
 
-	// Calculate approximate tokens (rough: 1 token ≈ 4 chars)
-	approxTokens := size / 4
+	estimate, err := EstimateRun(compiledSpec, opts)
+	if err != nil {
+		return false, err
+	}
+	if budgeted {
+		if err := checkBudget(estimate, opts); err != nil {
+			return false, err
+		}
+	}
+	if size < SizeWarningThreshold {
+		return true, nil
+	}
 
-	if opts.Ultra {
-		approxTokens *= 4 // 3 validations + 1 synthesis
+	summary := fmt.Sprintf("~%d input + ~%d output tokens over %d call(s)", estimate.InputTokens, estimate.OutputTokens, estimate.Calls)
+	if price, ok := PriceFor(opts.Pricing, opts.provider()); ok {
+		summary += fmt.Sprintf(", ~$%.2f", usageCost(estimate.Usage, price))
+	}
+
+	ultraRuns := fmt.Sprintf("%d runs", opts.ultraRuns())
+	if opts.Synthesize {
+		ultraRuns += " + synthesis"
 	}
 
 	if size >= SizeLargeThreshold {
-		fmt.Fprintf(output, "Large spec detected: %dKB (~%d tokens)\n", size/1024, approxTokens)
+		fmt.Fprintf(output, "Large spec detected: %dKB (%s)\n", size/1024, summary)
 		if opts.Ultra {
-			fmt.Fprintf(output, "  Ultra mode: 3 validation runs + synthesis\n")
+			fmt.Fprintf(output, "  Ultra mode: %s\n", ultraRuns)
 		}
-		fmt.Fprintf(output, "  This will use significant Claude API capacity.\n\n")
+		if opts.Chunks != nil {
+			fmt.Fprintf(output, "  Split into %d chunks + outline pass (--no-chunk to send at once)\n", len(opts.Chunks.Chunks))
+		}
+		fmt.Fprintf(output, "  This will use significant %s API capacity.\n\n", ProviderLabel(opts.provider()))
 	} else if opts.Ultra {
-		fmt.Fprintf(output, "Ultra mode: %dKB (~%d tokens with 3 runs + synthesis)\n\n", size/1024, approxTokens)
+		fmt.Fprintf(output, "Ultra mode: %dKB (%s with %s)\n\n", size/1024, summary, ultraRuns)
 	} else {
-		fmt.Fprintf(output, "Spec size: %dKB (~%d tokens)\n\n", size/1024, approxTokens)
+		fmt.Fprintf(output, "Spec size: %dKB (%s)\n\n", size/1024, summary)
 	}
 
-	if opts.SkipConfirm {
+	if opts.SkipConfirm || budgeted {
 		return true, nil
 	}
 
 	// Interactive confirmation
-	fmt.Fprint(output, "Proceed with Claude validation? [y/N]: ")
+	fmt.Fprintf(output, "Proceed with %s validation? [y/N]: ", ProviderLabel(opts.provider()))
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
 	if err != nil {
@@ -308,4 +387,3 @@ func CheckSpecSize(compiledSpec string, opts ValidationOptions, output io.Writer
 	response = strings.TrimSpace(strings.ToLower(response))
 	return response == "y" || response == "yes", nil
 }
-
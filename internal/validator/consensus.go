@@ -0,0 +1,137 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Ultra mode defaults
+const (
+	DefaultUltraRuns          = 3
+	DefaultConsensusThreshold = 0.5
+)
+
+// Matches anything that is not a letter or digit, for location normalization
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// BuildConsensus clusters equivalent findings across runs and scores each cluster
+// Findings are equivalent when they share a rule and resolve to the same section
+// (or, when no section resolves, the same normalized location). A run reporting
+// several such findings keeps them apart: its nth one joins the nth cluster of the key.
+// runs[i] holds the findings of run i+1; nil entries are runs that produced no result
+func BuildConsensus(runs [][]Finding, titles []string) []Finding {
+	completed := 0
+	for _, run := range runs {
+		if run != nil {
+			completed++
+		}
+	}
+	if completed == 0 {
+		return nil
+	}
+
+	var clusters []Finding
+	index := make(map[string]int)
+
+	for i, run := range runs {
+		AssignSections(run, titles)
+		occurrences := make(map[string]int)
+		for _, f := range run {
+			key := consensusKey(f)
+			occurrences[key]++
+			key = fmt.Sprintf("%s#%d", key, occurrences[key])
+			idx, ok := index[key]
+			if !ok {
+				idx = len(clusters)
+				index[key] = idx
+				clusters = append(clusters, f)
+				clusters[idx].Runs = nil
+			}
+			if !containsInt(clusters[idx].Runs, i+1) {
+				clusters[idx].Runs = append(clusters[idx].Runs, i+1)
+			}
+			// Keep the most detailed wording of the issue
+			if len(f.Issue) > len(clusters[idx].Issue) {
+				clusters[idx].Issue = f.Issue
+				clusters[idx].Suggestion = f.Suggestion
+			}
+			clusters[idx].Severity = moreSevere(clusters[idx].Severity, f.Severity)
+		}
+	}
+
+	for i := range clusters {
+		clusters[i].Confidence = float64(len(clusters[i].Runs)) / float64(completed)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Confidence > clusters[j].Confidence
+	})
+
+	return clusters
+}
+
+// SplitByThreshold separates findings at or above the confidence threshold from the rest
+func SplitByThreshold(findings []Finding, threshold float64) (accepted, rejected []Finding) {
+	for _, f := range findings {
+		if f.Confidence >= threshold {
+			accepted = append(accepted, f)
+		} else {
+			rejected = append(rejected, f)
+		}
+	}
+	return accepted, rejected
+}
+
+// FormatConsensus formats the consensus report with per-issue confidence
+func FormatConsensus(accepted, rejected []Finding, completedRuns int, threshold float64) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("=== Consensus (%d runs, threshold %.2f) ===\n\n", completedRuns, threshold))
+
+	if len(accepted) == 0 {
+		sb.WriteString("Specification passes all checks.\n")
+	} else {
+		sb.WriteString(FormatFindings(accepted))
+	}
+
+	if len(rejected) > 0 {
+		sb.WriteString(fmt.Sprintf("\nBelow threshold (%d):\n", len(rejected)))
+		for _, f := range rejected {
+			sb.WriteString(fmt.Sprintf("  - %s @ %s: %.2f (%s)\n", f.Rule, f.Location, f.Confidence, formatRuns(f.Runs)))
+		}
+	}
+
+	return sb.String()
+}
+
+// consensusKey identifies equivalent findings across runs
+func consensusKey(f Finding) string {
+	location := f.Section
+	if location == "" {
+		location = strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(f.Location), " "), " ")
+	}
+	return strings.ToLower(f.Rule) + "|" + location
+}
+
+func formatRuns(runs []int) string {
+	parts := make([]string, len(runs))
+	for i, r := range runs {
+		parts[i] = fmt.Sprintf("%d", r)
+	}
+	label := "run"
+	if len(runs) != 1 {
+		label = "runs"
+	}
+	return label + " " + strings.Join(parts, ", ")
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildConsensus(t *testing.T) {
+	titles := []string{"Stack", "Performance"}
+	runs := [][]Finding{
+		{
+			{Rule: "exact-versions", Location: "Context > Stack", Issue: "Redis unpinned"},
+			{Rule: "perf-quantified", Location: "Performance", Issue: "fast"},
+		},
+		{
+			{Rule: "exact-versions", Location: "Stack section, line 4", Issue: "Redis has no exact version"},
+		},
+		{
+			{Rule: "Exact-Versions", Location: "stack", Issue: "Redis"},
+			{Rule: "no-weak-language", Location: "Intro paragraph", Issue: "should"},
+		},
+	}
+
+	consensus := BuildConsensus(runs, titles)
+	if len(consensus) != 3 {
+		t.Fatalf("expected 3 clusters, got %d: %+v", len(consensus), consensus)
+	}
+
+	top := consensus[0]
+	if top.Rule != "exact-versions" || top.Confidence != 1 {
+		t.Errorf("expected exact-versions at confidence 1, got %s at %.2f", top.Rule, top.Confidence)
+	}
+	if len(top.Runs) != 3 {
+		t.Errorf("expected runs 1-3, got %v", top.Runs)
+	}
+	if top.Issue != "Redis has no exact version" {
+		t.Errorf("expected most detailed issue wording, got %q", top.Issue)
+	}
+
+	accepted, rejected := SplitByThreshold(consensus, 0.5)
+	if len(accepted) != 1 || len(rejected) != 2 {
+		t.Errorf("expected 1 accepted / 2 rejected, got %d / %d", len(accepted), len(rejected))
+	}
+}
+
+func TestBuildConsensus_KeepsSameRunFindingsApart(t *testing.T) {
+	runs := [][]Finding{
+		{
+			{Rule: "error-handling", Location: "API", Issue: "POST /orders has no error responses"},
+			{Rule: "error-handling", Location: "API", Issue: "DELETE /orders/{id} has no 404"},
+		},
+		{
+			{Rule: "error-handling", Location: "API", Issue: "POST /orders lists no errors"},
+		},
+	}
+
+	consensus := BuildConsensus(runs, []string{"API"})
+	if len(consensus) != 2 {
+		t.Fatalf("expected 2 clusters, got %+v", consensus)
+	}
+	if consensus[0].Confidence != 1 || consensus[1].Confidence != 0.5 || len(consensus[1].Runs) != 1 {
+		t.Errorf("unexpected scores: %+v", consensus)
+	}
+}
+
+func TestBuildConsensus_FailedRunsExcluded(t *testing.T) {
+	runs := [][]Finding{
+		{{Rule: "db-schema", Location: "Database"}},
+		nil, // failed run
+	}
+
+	consensus := BuildConsensus(runs, nil)
+	if len(consensus) != 1 || consensus[0].Confidence != 1 {
+		t.Errorf("confidence should be relative to completed runs, got %+v", consensus)
+	}
+}
+
+func TestFormatConsensus(t *testing.T) {
+	accepted := []Finding{{Rule: "db-schema", Location: "Database", Issue: "no indexes", Confidence: 2.0 / 3, Runs: []int{1, 3}}}
+	rejected := []Finding{{Rule: "file-tree", Location: "Layout", Confidence: 1.0 / 3, Runs: []int{2}}}
+
+	report := FormatConsensus(accepted, rejected, 3, 0.5)
+	for _, expect := range []string{
+		"3 runs, threshold 0.50",
+		"- Confidence: 0.67 (runs 1, 3)",
+		"Below threshold (1)",
+		"file-tree @ Layout: 0.33 (run 2)",
+	} {
+		if !strings.Contains(report, expect) {
+			t.Errorf("expected report to contain %q:\n%s", expect, report)
+		}
+	}
+}
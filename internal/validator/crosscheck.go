@@ -0,0 +1,672 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	specparser "github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+// Type consistency analyzers: Go structs against SQL tables and JSON examples
+const (
+	ruleTypesSQL  = "types-sql"
+	ruleTypesJSON = "types-json"
+)
+
+var (
+	typesSQLAnalyzer = Analyzer{
+		ID:   ruleTypesSQL,
+		Name: "Go types match SQL schema",
+		Run:  analyzeTypesSQL,
+	}
+	typesJSONAnalyzer = Analyzer{
+		ID:   ruleTypesJSON,
+		Name: "JSON examples match Go types",
+		Run:  analyzeTypesJSON,
+	}
+)
+
+// GoStruct is a struct type declared in a Go source block
+type GoStruct struct {
+	Name   string
+	Fields []GoField
+	File   string
+	Line   int
+}
+
+// GoField is one named struct field
+type GoField struct {
+	Name string
+	Type string // As written, e.g. "*time.Time"
+	JSON string // Name from the json tag, "" if untagged, "-" if excluded
+	DB   string // Name from the db tag, "" if untagged
+	Line int
+}
+
+// SQLTable is a CREATE TABLE statement from an SQL block
+type SQLTable struct {
+	Name    string
+	Columns []SQLColumn
+	File    string
+	Line    int
+}
+
+// SQLColumn is one column definition
+type SQLColumn struct {
+	Name string
+	Type string // Lower-cased, without length or precision, e.g. "varchar", "timestamp with time zone"
+	Line int
+}
+
+// JSONExample is a JSON document found in a json or http block
+type JSONExample struct {
+	Value     any
+	File      string
+	StartLine int      // Line of the opening { or [
+	Lines     []string // Source lines from StartLine, for locating keys
+}
+
+// TypeSources are the type declarations and examples extracted from a spec
+type TypeSources struct {
+	Structs  []GoStruct
+	Tables   []SQLTable
+	Examples []JSONExample
+}
+
+// ExtractTypeSources parses Go, SQL and JSON blocks across all spec files
+func ExtractTypeSources(src *SpecSources) *TypeSources {
+	ts := &TypeSources{}
+	for _, doc := range src.Documents {
+		file := src.RelPath(doc.FilePath)
+		for _, block := range doc.Blocks {
+			switch block.Language {
+			case "go", "golang":
+				ts.Structs = append(ts.Structs, parseGoStructs(block, file)...)
+			case "sql", "postgresql", "postgres", "mysql", "sqlite":
+				ts.Tables = append(ts.Tables, parseSQLTables(block, file)...)
+			case "json", "http", "javascript", "js", "":
+				if example, ok := parseJSONExample(block, file); ok {
+					ts.Examples = append(ts.Examples, example)
+				}
+			}
+		}
+	}
+	return ts
+}
+
+// typeDeclPattern finds struct declarations when a block does not parse as a whole
+var typeDeclPattern = regexp.MustCompile(`(?m)^type\s+\w+\s+struct\s*\{`)
+
+// parseGoStructs parses struct declarations from a Go block
+// Blocks are often fragments, so on a parse error each struct is parsed on its own
+func parseGoStructs(block specparser.SourceBlock, file string) []GoStruct {
+	content := strings.Join(block.Lines, "\n")
+	if structs, ok := goStructsFromSource(content, block.StartLine, file); ok {
+		return structs
+	}
+
+	var structs []GoStruct
+	for _, loc := range typeDeclPattern.FindAllStringIndex(content, -1) {
+		end := matchingBrace(content, loc[1]-1)
+		if end < 0 {
+			continue
+		}
+		offset := strings.Count(content[:loc[0]], "\n")
+		found, _ := goStructsFromSource(content[loc[0]:end+1], block.StartLine+offset, file)
+		structs = append(structs, found...)
+	}
+	return structs
+}
+
+// goStructsFromSource parses Go declarations; startLine is the file line of the first source line
+func goStructsFromSource(source string, startLine int, file string) ([]GoStruct, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(source), "package ") {
+		source = "package spec\n" + source
+		startLine--
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", source, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, false
+	}
+	line := func(pos token.Pos) int { return startLine + fset.Position(pos).Line - 1 }
+
+	var structs []GoStruct
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+
+		s := GoStruct{Name: spec.Name.Name, File: file, Line: line(spec.Pos())}
+		for _, field := range st.Fields.List {
+			var tag reflect.StructTag
+			if field.Tag != nil {
+				if unquoted, err := strconv.Unquote(field.Tag.Value); err == nil {
+					tag = reflect.StructTag(unquoted)
+				}
+			}
+			for _, name := range field.Names {
+				s.Fields = append(s.Fields, GoField{
+					Name: name.Name,
+					Type: types.ExprString(field.Type),
+					JSON: strings.Split(tag.Get("json"), ",")[0],
+					DB:   strings.Split(tag.Get("db"), ",")[0],
+					Line: line(name.Pos()),
+				})
+			}
+		}
+		structs = append(structs, s)
+		return true
+	})
+	return structs, true
+}
+
+// matchingBrace returns the index of the bracket closing the one at open, -1 if unbalanced
+func matchingBrace(s string, open int) int {
+	closer := map[byte]byte{'{': '}', '(': ')', '[': ']'}[s[open]]
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case s[open]:
+			depth++
+		case closer:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// SQL DDL syntax
+var (
+	createTablePattern = regexp.MustCompile(`(?i)\bCREATE\s+(?:(?:GLOBAL\s+|LOCAL\s+)?(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([\w."` + "`" + `]+)\s*\(`)
+	sqlTypeEnd         = regexp.MustCompile(`(?i)\s+(?:NOT\b|NULL\b|DEFAULT\b|PRIMARY\b|REFERENCES\b|UNIQUE\b|CHECK\b|CONSTRAINT\b|GENERATED\b|COLLATE\b|AUTO_INCREMENT\b|AUTOINCREMENT\b)`)
+	sqlTypeParams      = regexp.MustCompile(`\s*\([^)]*\)`)
+	tableConstraints   = map[string]bool{"constraint": true, "primary": true, "unique": true, "foreign": true, "check": true, "index": true, "key": true, "exclude": true, "like": true}
+)
+
+// parseSQLTables extracts CREATE TABLE statements with their column types
+func parseSQLTables(block specparser.SourceBlock, file string) []SQLTable {
+	content := stripSQLComments(strings.Join(block.Lines, "\n"))
+
+	var tables []SQLTable
+	for _, m := range createTablePattern.FindAllStringSubmatchIndex(content, -1) {
+		open := m[1] - 1
+		end := matchingBrace(content, open)
+		if end < 0 {
+			continue
+		}
+
+		name := unquoteIdent(content[m[2]:m[3]])
+		if dot := strings.LastIndex(name, "."); dot >= 0 {
+			name = name[dot+1:] // Drop the schema
+		}
+		table := SQLTable{Name: name, File: file, Line: block.StartLine + strings.Count(content[:m[0]], "\n")}
+
+		// Split the body on top-level commas
+		body := content[open+1 : end]
+		start, depth := 0, 0
+		for i := 0; i <= len(body); i++ {
+			if i < len(body) {
+				switch body[i] {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+				if body[i] != ',' || depth != 0 {
+					continue
+				}
+			}
+			item := strings.TrimSpace(body[start:i])
+			itemStart := open + 1 + start + strings.Index(body[start:i], item)
+			start = i + 1
+
+			fields := strings.Fields(item)
+			if len(fields) < 2 || tableConstraints[strings.ToLower(fields[0])] {
+				continue
+			}
+			colType := strings.TrimSpace(item[len(fields[0]):])
+			if loc := sqlTypeEnd.FindStringIndex(colType); loc != nil {
+				colType = colType[:loc[0]]
+			}
+			colType = strings.ToLower(strings.Join(strings.Fields(sqlTypeParams.ReplaceAllString(colType, "")), " "))
+
+			table.Columns = append(table.Columns, SQLColumn{
+				Name: unquoteIdent(fields[0]),
+				Type: colType,
+				Line: block.StartLine + strings.Count(content[:itemStart], "\n"),
+			})
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// stripSQLComments blanks -- comments, keeping line positions
+func stripSQLComments(sql string) string {
+	lines := strings.Split(sql, "\n")
+	for i, line := range lines {
+		if idx := strings.Index(line, "--"); idx >= 0 {
+			lines[i] = line[:idx]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func unquoteIdent(s string) string {
+	return strings.Trim(s, "\"`[]")
+}
+
+// parseJSONExample decodes the first JSON object or array in a block
+// HTTP examples have headers before the body, so decoding starts at the first line opening a value
+func parseJSONExample(block specparser.SourceBlock, file string) (JSONExample, bool) {
+	for i, line := range block.Lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+			continue
+		}
+
+		var value any
+		dec := json.NewDecoder(strings.NewReader(strings.Join(block.Lines[i:], "\n")))
+		if err := dec.Decode(&value); err != nil {
+			return JSONExample{}, false
+		}
+		switch value.(type) {
+		case map[string]any, []any:
+			return JSONExample{Value: value, File: file, StartLine: block.StartLine + i, Lines: block.Lines[i:]}, true
+		}
+		return JSONExample{}, false
+	}
+	return JSONExample{}, false
+}
+
+// normalizeName folds user_id, userId and UserID to the same key
+func normalizeName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
+
+// columnName returns the column a field maps to, normalized
+func (f GoField) columnName() string {
+	if f.DB != "" && f.DB != "-" {
+		return normalizeName(f.DB)
+	}
+	return normalizeName(f.Name)
+}
+
+// jsonName returns the JSON key a field encodes to, normalized ("" when excluded)
+func (f GoField) jsonName() string {
+	switch f.JSON {
+	case "-":
+		return ""
+	case "":
+		return normalizeName(f.Name)
+	default:
+		return normalizeName(f.JSON)
+	}
+}
+
+// singular turns a table name into the struct name it usually maps to
+func singular(name string) string {
+	name = normalizeName(name)
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
+
+// tableStruct finds the struct a table maps to: users -> User, order_items -> OrderItem
+func tableStruct(table SQLTable, structs []GoStruct) *GoStruct {
+	for i := range structs {
+		name := normalizeName(structs[i].Name)
+		if name == singular(table.Name) || name == normalizeName(table.Name) {
+			return &structs[i]
+		}
+	}
+	return nil
+}
+
+// analyzeTypesSQL compares each table with its struct: missing columns, missing fields, type mismatches
+// Type mismatches keep their severity; columns and fields without a counterpart are drift, reported as warnings
+func analyzeTypesSQL(src *SpecSources) []AnalyzerIssue {
+	ts := src.typeSources()
+	declared := make(map[string]bool)
+	for _, st := range ts.Structs {
+		declared[st.Name] = true
+	}
+
+	var issues []AnalyzerIssue
+	for _, table := range ts.Tables {
+		st := tableStruct(table, ts.Structs)
+		if st == nil {
+			continue
+		}
+
+		fields := make(map[string]GoField)
+		for _, f := range st.Fields {
+			if f.DB != "-" {
+				fields[f.columnName()] = f
+			}
+		}
+		matched := make(map[string]bool)
+
+		for _, col := range table.Columns {
+			field, ok := columnField(col.Name, fields)
+			if !ok {
+				issues = append(issues, AnalyzerIssue{
+					Rule:     ruleTypesSQL,
+					Severity: SeverityWarning,
+					File:     table.File,
+					Line:     col.Line,
+					Message:  fmt.Sprintf("column %s.%s has no field in %s (%s:%d)", table.Name, col.Name, st.Name, st.File, st.Line),
+				})
+				continue
+			}
+			matched[field.Name] = true
+			if relationField(field, declared) {
+				continue // order_id holding an *Order has no comparable type
+			}
+			if severity, hint := sqlTypeMismatch(col.Type, field.Type); severity != "" {
+				issues = append(issues, AnalyzerIssue{
+					Rule:     ruleTypesSQL,
+					Severity: severity,
+					File:     st.File,
+					Line:     field.Line,
+					Message:  fmt.Sprintf("%s.%s is %s but column %s.%s is %s (%s:%d)%s", st.Name, field.Name, field.Type, table.Name, col.Name, strings.ToUpper(col.Type), table.File, col.Line, hint),
+				})
+			}
+		}
+
+		for _, f := range st.Fields {
+			if f.DB == "-" || matched[f.Name] || relationField(f, declared) {
+				continue
+			}
+			issues = append(issues, AnalyzerIssue{
+				Rule:     ruleTypesSQL,
+				Severity: SeverityWarning,
+				File:     st.File,
+				Line:     f.Line,
+				Message:  fmt.Sprintf("%s.%s has no column in table %s (%s:%d)", st.Name, f.Name, table.Name, table.File, table.Line),
+			})
+		}
+	}
+	return issues
+}
+
+// columnField finds the field a column maps to; a foreign key x_id also maps to a field X
+func columnField(column string, fields map[string]GoField) (GoField, bool) {
+	if f, ok := fields[normalizeName(column)]; ok {
+		return f, true
+	}
+	if base, ok := strings.CutSuffix(strings.ToLower(column), "_id"); ok {
+		f, ok := fields[normalizeName(base)]
+		return f, ok
+	}
+	return GoField{}, false
+}
+
+// relationField reports whether a field holds a slice, a map or a struct declared in the spec,
+// which are loaded from other tables or columns rather than stored in one column
+func relationField(f GoField, declared map[string]bool) bool {
+	t := strings.TrimPrefix(f.Type, "*")
+	return strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || strings.HasPrefix(t, "struct") || declared[t]
+}
+
+// SQL type families and the Go types that hold them
+var sqlTypeFamilies = []struct {
+	sqlTypes []string
+	family   string
+}{
+	{[]string{"uuid"}, "uuid"},
+	{[]string{"text", "varchar", "character varying", "char", "character", "citext", "nvarchar", "nchar", "string", "clob", "tinytext", "mediumtext", "longtext", "inet", "cidr"}, "string"},
+	{[]string{"bigint", "int8", "bigserial", "serial8"}, "bigint"},
+	{[]string{"integer", "int", "int4", "serial", "serial4", "smallint", "int2", "smallserial", "serial2", "tinyint", "mediumint"}, "int"},
+	{[]string{"boolean", "bool"}, "bool"},
+	{[]string{"timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone", "datetime", "date", "time", "timetz", "time with time zone", "time without time zone"}, "time"},
+	{[]string{"numeric", "decimal", "money"}, "decimal"},
+	{[]string{"real", "float4", "float8", "double precision", "double", "float"}, "float"},
+	{[]string{"json", "jsonb"}, "json"},
+	{[]string{"bytea", "blob", "binary", "varbinary", "longblob"}, "bytes"},
+	{[]string{"interval"}, "duration"},
+}
+
+// goTypeFamily classifies a Go type, unwrapping pointers and sql.Null* wrappers
+func goTypeFamily(goType string) string {
+	t := strings.TrimPrefix(goType, "*")
+	switch t {
+	case "sql.NullString":
+		t = "string"
+	case "sql.NullInt64":
+		t = "int64"
+	case "sql.NullInt32", "sql.NullInt16":
+		t = "int32"
+	case "sql.NullBool":
+		t = "bool"
+	case "sql.NullTime":
+		t = "time.Time"
+	case "sql.NullFloat64":
+		t = "float64"
+	}
+
+	switch {
+	case t == "string":
+		return "string"
+	case t == "int" || t == "int64" || t == "uint" || t == "uint64":
+		return "int64"
+	case t == "int32" || t == "int16" || t == "int8" || t == "uint32" || t == "uint16" || t == "uint8":
+		return "int32"
+	case t == "bool":
+		return "bool"
+	case t == "float64" || t == "float32":
+		return "float"
+	case t == "time.Time" || strings.HasSuffix(t, ".Date") || strings.HasSuffix(t, ".Timestamp"):
+		return "time"
+	case t == "time.Duration":
+		return "duration"
+	case t == "[]byte" || t == "json.RawMessage":
+		return "bytes"
+	case strings.HasSuffix(t, ".UUID") || t == "[16]byte":
+		return "uuid"
+	case strings.HasSuffix(t, ".Decimal") || t == "big.Rat" || t == "big.Float":
+		return "decimal"
+	case strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "any" || t == "interface{}":
+		return "composite"
+	}
+	return "" // Named or unknown types are not checked
+}
+
+// sqlTypeMismatch reports whether a Go type cannot hold a column type
+// It returns the issue severity and a hint, or "" when the types are compatible
+func sqlTypeMismatch(sqlType, goType string) (string, string) {
+	goFamily := goTypeFamily(goType)
+	if goFamily == "" {
+		return "", ""
+	}
+	if strings.HasSuffix(sqlType, "[]") || strings.HasPrefix(sqlType, "array") {
+		if goFamily == "composite" || strings.Contains(goType, "Array") {
+			return "", ""
+		}
+		return SeverityError, ""
+	}
+
+	family := ""
+	for _, f := range sqlTypeFamilies {
+		for _, t := range f.sqlTypes {
+			if sqlType == t {
+				family = f.family
+			}
+		}
+	}
+
+	compatible := map[string][]string{
+		"uuid":     {"uuid"},
+		"string":   {"string"},
+		"bigint":   {"int64"},
+		"int":      {"int64", "int32"},
+		"bool":     {"bool"},
+		"time":     {"time"},
+		"decimal":  {"decimal", "float", "string"},
+		"float":    {"float"},
+		"json":     {"bytes", "composite", "string"},
+		"bytes":    {"bytes"},
+		"duration": {"duration", "int64"},
+	}
+	accepted, known := compatible[family]
+	if !known || slices.Contains(accepted, goFamily) {
+		return "", ""
+	}
+
+	switch {
+	case family == "uuid" && goFamily == "string":
+		// Common and workable, but the type no longer says what the value is
+		return SeverityInfo, " - consider uuid.UUID"
+	case family == "bigint" && goFamily == "int32":
+		return SeverityError, " - values above 2^31 overflow"
+	}
+	return SeverityError, ""
+}
+
+// jsonMatchThreshold is the fraction of an example's keys a struct must have to be its type
+// Request bodies share a few keys with the entity type, so a lower bar flags their extra keys
+const jsonMatchThreshold = 0.75
+
+// analyzeTypesJSON reports JSON example keys that the matching Go type does not have
+func analyzeTypesJSON(src *SpecSources) []AnalyzerIssue {
+	ts := src.typeSources()
+	byName := make(map[string]*GoStruct)
+	for i := range ts.Structs {
+		byName[ts.Structs[i].Name] = &ts.Structs[i]
+	}
+
+	var issues []AnalyzerIssue
+	for _, example := range ts.Examples {
+		checkJSONValue(example, example.Value, nil, ts.Structs, byName, &issues)
+	}
+	return issues
+}
+
+// checkJSONValue matches objects to a struct (given, or the best match) and recurses into nested values
+func checkJSONValue(example JSONExample, value any, st *GoStruct, structs []GoStruct, byName map[string]*GoStruct, issues *[]AnalyzerIssue) {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			checkJSONValue(example, item, st, structs, byName, issues)
+		}
+	case map[string]any:
+		if st == nil {
+			st = bestJSONMatch(v, structs)
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fields := make(map[string]GoField)
+		if st != nil {
+			for _, f := range st.Fields {
+				if name := f.jsonName(); name != "" {
+					fields[name] = f
+				}
+			}
+		}
+
+		for _, key := range keys {
+			if st == nil {
+				checkJSONValue(example, v[key], nil, structs, byName, issues)
+				continue
+			}
+			field, ok := fields[normalizeName(key)]
+			if !ok {
+				*issues = append(*issues, AnalyzerIssue{
+					Rule:     ruleTypesJSON,
+					Severity: SeverityWarning,
+					File:     example.File,
+					Line:     example.keyLine(key),
+					Message:  fmt.Sprintf("JSON key %q is not a field of %s (%s:%d)", key, st.Name, st.File, st.Line),
+				})
+				continue
+			}
+			checkJSONValue(example, v[key], byName[elementType(field.Type)], structs, byName, issues)
+		}
+	}
+}
+
+// bestJSONMatch returns the struct sharing the most keys with obj, if it covers enough of them
+func bestJSONMatch(obj map[string]any, structs []GoStruct) *GoStruct {
+	var best *GoStruct
+	bestMatched := 0
+	for i := range structs {
+		names := make(map[string]bool)
+		for _, f := range structs[i].Fields {
+			if name := f.jsonName(); name != "" {
+				names[name] = true
+			}
+		}
+		matched := 0
+		for key := range obj {
+			if names[normalizeName(key)] {
+				matched++
+			}
+		}
+		if matched > bestMatched || (matched == bestMatched && best != nil && len(structs[i].Fields) < len(best.Fields)) {
+			best, bestMatched = &structs[i], matched
+		}
+	}
+	if bestMatched < 2 || float64(bestMatched) < jsonMatchThreshold*float64(len(obj)) {
+		return nil
+	}
+	return best
+}
+
+// elementType strips pointers, slices and the package from a Go type: []*models.Item -> Item
+func elementType(goType string) string {
+	t := strings.TrimLeft(goType, "*[]")
+	if dot := strings.LastIndex(t, "."); dot >= 0 {
+		t = t[dot+1:]
+	}
+	return t
+}
+
+// keyLine returns the file line where a key first appears in the example
+func (e JSONExample) keyLine(key string) int {
+	quoted := strconv.Quote(key)
+	for i, line := range e.Lines {
+		if strings.Contains(line, quoted) {
+			return e.StartLine + i
+		}
+	}
+	return e.StartLine
+}
+
+// typeSources extracts type declarations on first use
+func (s *SpecSources) typeSources() *TypeSources {
+	if s.types == nil {
+		s.types = ExtractTypeSources(s)
+	}
+	return s.types
+}
@@ -0,0 +1,160 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func writeTypesSpec(t *testing.T) *SpecSources {
+	t.Helper()
+	manifest := `= Orders
+
+== Core Types
+
+[source,go]
+----
+type Order struct {
+    ID         int64
+    CustomerID string    ` + "`json:\"customerId\" db:\"customer_id\"`" + `
+    Total      float64
+    Lines      []OrderLine
+    PlacedAt   string
+}
+
+type OrderLine struct {
+    SKU      string
+    Quantity int
+}
+
+func (o Order) Valid() bool { ... }
+----
+
+[source,sql]
+----
+CREATE TABLE IF NOT EXISTS orders (
+    id          UUID PRIMARY KEY,
+    customer_id TEXT NOT NULL REFERENCES customers(id),
+    total       NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    placed_at   TIMESTAMPTZ NOT NULL,
+    status      VARCHAR(16), -- pending, paid
+    CONSTRAINT positive_total CHECK (total >= 0)
+);
+----
+
+== API
+
+[source,http]
+----
+HTTP/1.1 200 OK
+Content-Type: application/json
+
+{
+  "id": 7,
+  "customerId": "c-1",
+  "total": 12.5,
+  "currency": "EUR",
+  "lines": [{"sku": "A-1", "quantity": 2, "price": 3}]
+}
+----
+
+== Storage
+
+[source,go]
+----
+type Shipment struct {
+    ID      string
+    Order   *Order
+    Carrier string
+    Labels  map[string]string
+}
+----
+
+[source,sql]
+----
+CREATE TABLE shipments (
+    id       TEXT PRIMARY KEY,
+    order_id UUID NOT NULL,
+    carrier  TEXT
+);
+----
+`
+	return loadSpecFiles(t, map[string]string{"MANIFEST.adoc": manifest})
+}
+
+func TestExtractTypeSources(t *testing.T) {
+	ts := ExtractTypeSources(writeTypesSpec(t))
+
+	if len(ts.Structs) != 3 || ts.Structs[0].Name != "Order" || ts.Structs[0].Line != 7 {
+		t.Fatalf("unexpected structs: %+v", ts.Structs)
+	}
+	if f := ts.Structs[0].Fields[1]; f.DB != "customer_id" || f.JSON != "customerId" || f.Line != 9 {
+		t.Errorf("unexpected field: %+v", f)
+	}
+
+	if len(ts.Tables) != 2 || len(ts.Tables[0].Columns) != 5 {
+		t.Fatalf("unexpected tables: %+v", ts.Tables)
+	}
+	if col := ts.Tables[0].Columns[2]; col.Name != "total" || col.Type != "numeric" || col.Line != 28 {
+		t.Errorf("unexpected column: %+v", col)
+	}
+
+	if len(ts.Examples) != 1 || ts.Examples[0].StartLine != 42 {
+		t.Errorf("unexpected examples: %+v", ts.Examples)
+	}
+}
+
+func TestAnalyzeTypesSQL(t *testing.T) {
+	issues := analyzeTypesSQL(writeTypesSpec(t))
+	got := make([]string, len(issues))
+	for i, issue := range issues {
+		got[i] = issue.Location() + " " + issue.Severity + " " + issue.Message
+	}
+
+	want := []string{
+		"MANIFEST.adoc:8 error Order.ID is int64 but column orders.id is UUID (MANIFEST.adoc:26)",
+		"MANIFEST.adoc:12 error Order.PlacedAt is string but column orders.placed_at is TIMESTAMPTZ (MANIFEST.adoc:29)",
+		"MANIFEST.adoc:30 warning column orders.status has no field in Order (MANIFEST.adoc:7)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAnalyzeTypesJSON(t *testing.T) {
+	issues := analyzeTypesJSON(writeTypesSpec(t))
+	got := make([]string, len(issues))
+	for i, issue := range issues {
+		got[i] = issue.Location() + " " + issue.Message
+	}
+
+	want := []string{
+		`MANIFEST.adoc:46 JSON key "currency" is not a field of Order (MANIFEST.adoc:7)`,
+		`MANIFEST.adoc:47 JSON key "price" is not a field of OrderLine (MANIFEST.adoc:15)`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSQLTypeMismatch(t *testing.T) {
+	tests := []struct {
+		sqlType, goType, severity string
+	}{
+		{"timestamptz", "time.Time", ""},
+		{"timestamp with time zone", "*time.Time", ""},
+		{"uuid", "uuid.UUID", ""},
+		{"uuid", "string", SeverityInfo},
+		{"bigint", "int32", SeverityError},
+		{"integer", "sql.NullInt64", ""},
+		{"jsonb", "map[string]any", ""},
+		{"text[]", "[]string", ""},
+		{"boolean", "string", SeverityError},
+		{"status_enum", "string", ""},
+		{"text", "OrderStatus", ""},
+	}
+	for _, tt := range tests {
+		if got, _ := sqlTypeMismatch(tt.sqlType, tt.goType); got != tt.severity {
+			t.Errorf("sqlTypeMismatch(%q, %q) = %q, want %q", tt.sqlType, tt.goType, got, tt.severity)
+		}
+	}
+}
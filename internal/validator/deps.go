@@ -0,0 +1,467 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Dependency kinds
+const (
+	DepGo      = "go"
+	DepNPM     = "npm"
+	DepDocker  = "docker"
+	DepProduct = "product"
+)
+
+// Dependency version statuses
+const (
+	DepPinned       = "pinned"
+	DepUnpinned     = "unpinned"
+	DepFloating     = "floating"
+	DepInconsistent = "inconsistent"
+)
+
+// Dependency is one dependency declaration found in the spec sources
+type Dependency struct {
+	Name    string `json:"name"`              // Module path, package, image or canonical product name
+	Version string `json:"version,omitempty"` // As written, "" when absent
+	Kind    string `json:"kind"`
+	Status  string `json:"status"`
+	File    string `json:"file"` // Relative to the manifest directory
+	Line    int    `json:"line"`
+}
+
+// Location formats the declaration position as file:line
+func (d Dependency) Location() string {
+	return fmt.Sprintf("%s:%d", d.File, d.Line)
+}
+
+// ruleExactVersions is the checklist rule the dependency analyzer enforces
+const ruleExactVersions = "exact-versions"
+
+var dependencyAnalyzer = Analyzer{
+	ID:   ruleExactVersions,
+	Name: "Dependency versions pinned",
+	Run:  analyzeDependencies,
+}
+
+// Dependency declaration syntax
+var (
+	goModulePattern   = regexp.MustCompile(`\b((?:[a-z0-9-]+\.)+[a-z]{2,}(?:/[\w.~-]+)+)(?:@(\S+)|\s+(v\d+\.\d+\.\d+[\w.+-]*))`)
+	npmPackagePattern = regexp.MustCompile("(?:^|[\\s(`\"'])((?:@[\\w.-]+/)?[a-z][\\w.-]*)@((?:\\^|~|>=?)?\\s*(?:\\d[\\w.*+-]*|latest|next))")
+	npmInstallPattern = regexp.MustCompile(`\b(?:npm\s+(?:install|i|add)|yarn\s+add|pnpm\s+add)\s+(.+)`)
+	npmDepsKeyPattern = regexp.MustCompile(`"(?:dev|peer|optional)?[dD]ependencies"\s*:\s*\{`)
+	npmEntryPattern   = regexp.MustCompile(`^\s*"((?:@[\w.-]+/)?[\w.-]+)"\s*:\s*"([^"]*)"`)
+	imageKeyPattern   = regexp.MustCompile(`^\s*(?:[-*]\s*)?image:\s*["']?([^\s"'#]+)`)
+	fromPattern       = regexp.MustCompile(`(?i)^\s*FROM\s+(?:--platform=\S+\s+)?(\S+)(?:\s+AS\s+(\S+))?`)
+	dockerPullPattern = regexp.MustCompile(`\bdocker\s+pull\s+(\S+)`)
+	imageTagPattern   = regexp.MustCompile("(?:^|[\\s(`\"'])((?:[a-z0-9][a-z0-9._-]*/)*[a-z][a-z0-9._-]*):(\\w[\\w.-]*)")
+	bareModulePattern = regexp.MustCompile("(?:^|[\\s(`\"'])((?:[a-z0-9-]+\\.)+[a-z]{2,}(?:/[\\w.~-]+)+)")
+	stackSection      = regexp.MustCompile(`(?i)stack|dependenc|technolog|tooling|platform|runtime`)
+	productPattern    = regexp.MustCompile(`\b(PostgreSQL|Postgres|MySQL|MariaDB|SQLite|Redis|MongoDB|Kafka|RabbitMQ|Elasticsearch|NATS|Node\.js|NodeJS|Node|Golang|Go|Python|Java|Rust|Ruby|Rails|Django|Flask|React|Vue|Angular|Next\.js|TypeScript|Nginx|NGINX|Kubernetes|Terraform)\b(?:\s*(?:@|:|version|v\b)?\s*((?:\^|~|>=?)\s*v?\d[\w.]*\+?|v?\d+(?:\.\d+)*(?:\.[xX*]|\+)?))?`)
+	floatingOperator  = regexp.MustCompile(`^(?:\^|~|>|\*|latest$|next$)|\.[xX*]$|\+$`)
+)
+
+// productNames maps product spellings to their canonical name
+var productNames = map[string]string{
+	"postgres": "PostgreSQL",
+	"nodejs":   "Node.js",
+	"node":     "Node.js",
+	"golang":   "Go",
+	"nginx":    "Nginx",
+}
+
+// ExtractDependencies finds dependency declarations in source blocks, prose and tables
+// Product names ("PostgreSQL 16") are only recognized in stack and dependency sections
+func ExtractDependencies(src *SpecSources) []Dependency {
+	resolve := attributeReplacer(src.Attributes)
+
+	var deps []Dependency
+	for _, doc := range src.Documents {
+		file := src.RelPath(doc.FilePath)
+		add := func(line int, found []Dependency) {
+			for _, d := range found {
+				d.File, d.Line = file, line
+				deps = append(deps, d)
+			}
+		}
+
+		for _, block := range doc.Blocks {
+			stages := make(map[string]bool) // Multi-stage build aliases are not images
+			inNPMDeps := false
+			for i, raw := range block.Lines {
+				line := resolve.Replace(raw)
+				lineNum := block.StartLine + i
+
+				if npmDepsKeyPattern.MatchString(line) {
+					inNPMDeps = true
+					continue
+				}
+				if inNPMDeps {
+					if strings.Contains(line, "}") {
+						inNPMDeps = false
+					} else if m := npmEntryPattern.FindStringSubmatch(line); m != nil {
+						add(lineNum, []Dependency{{Name: m[1], Version: m[2], Kind: DepNPM}})
+					}
+					continue
+				}
+
+				if m := fromPattern.FindStringSubmatch(line); m != nil {
+					if m[2] != "" {
+						stages[strings.ToLower(m[2])] = true
+					}
+					if m[1] != "scratch" && !stages[strings.ToLower(m[1])] {
+						add(lineNum, []Dependency{dockerDependency(m[1])})
+					}
+					continue
+				}
+
+				add(lineNum, lineDependencies(line))
+			}
+		}
+
+		for _, prose := range doc.Prose {
+			add(prose.Line, textDependencies(resolve.Replace(prose.Text), stackSection.MatchString(prose.Section)))
+		}
+
+		for _, table := range doc.Tables {
+			for _, row := range table.Rows {
+				line := resolve.Replace(strings.Join(row.Cells, " "))
+				add(row.Line, textDependencies(line, stackSection.MatchString(table.Section)))
+			}
+		}
+	}
+
+	return classifyDependencies(deps)
+}
+
+// lineDependencies finds Go modules, npm packages and Docker images on one line
+func lineDependencies(line string) []Dependency {
+	var deps []Dependency
+
+	for _, m := range goModulePattern.FindAllStringSubmatch(line, -1) {
+		version := m[2]
+		if version == "" {
+			version = m[3]
+		}
+		deps = append(deps, Dependency{Name: m[1], Version: trimVersion(version), Kind: DepGo})
+	}
+	// Mask module paths so their @version is not read as an npm package
+	line = goModulePattern.ReplaceAllStringFunc(line, func(s string) string { return strings.Repeat(" ", len(s)) })
+
+	if m := npmInstallPattern.FindStringSubmatch(line); m != nil {
+		for _, arg := range strings.Fields(m[1]) {
+			if strings.HasPrefix(arg, "-") || strings.ContainsAny(arg, "&|;") {
+				continue
+			}
+			name, version := splitNPMSpec(strings.Trim(arg, "`'\".,;)"))
+			deps = append(deps, Dependency{Name: name, Version: version, Kind: DepNPM})
+		}
+	} else {
+		for _, m := range npmPackagePattern.FindAllStringSubmatch(line, -1) {
+			deps = append(deps, Dependency{Name: m[1], Version: trimVersion(m[2]), Kind: DepNPM})
+		}
+	}
+
+	if m := imageKeyPattern.FindStringSubmatch(line); m != nil {
+		deps = append(deps, dockerDependency(m[1]))
+	}
+	if m := dockerPullPattern.FindStringSubmatch(line); m != nil {
+		deps = append(deps, dockerDependency(strings.Trim(m[1], "`'\"")))
+	}
+
+	return deps
+}
+
+// textDependencies finds the dependencies on a prose line or table row
+// Bare module paths and name:tag images are only read from inline code, or anywhere in stack sections
+func textDependencies(line string, stack bool) []Dependency {
+	found := lineDependencies(line)
+	scope := inlineCodePattern.FindAllString(line, -1)
+	if stack {
+		found = append(found, productDependencies(line)...)
+		scope = []string{line}
+	}
+	for _, text := range scope {
+		found = append(found, looseDependencies(text, found)...)
+	}
+	return found
+}
+
+// looseDependencies finds unversioned Go module paths and Docker images written as name:tag
+// Dependencies already in found are skipped
+func looseDependencies(text string, found []Dependency) []Dependency {
+	seen := make(map[string]bool)
+	for _, d := range found {
+		seen[d.Kind+"\x00"+d.Name] = true
+	}
+	var deps []Dependency
+	add := func(d Dependency) {
+		if !seen[d.Kind+"\x00"+d.Name] {
+			seen[d.Kind+"\x00"+d.Name] = true
+			deps = append(deps, d)
+		}
+	}
+
+	for _, m := range bareModulePattern.FindAllStringSubmatch(text, -1) {
+		add(Dependency{Name: strings.TrimRight(m[1], "."), Kind: DepGo})
+	}
+	for _, m := range imageTagPattern.FindAllStringSubmatch(text, -1) {
+		// localhost:8080 and api.example.com:443 are addresses, not images
+		if m[1] == "localhost" || !strings.Contains(m[1], "/") && strings.Contains(m[1], ".") {
+			continue
+		}
+		add(Dependency{Name: m[1], Version: trimVersion(m[2]), Kind: DepDocker})
+	}
+	return deps
+}
+
+// productDependencies finds known products such as "PostgreSQL 16" or "Go 1.22"
+func productDependencies(line string) []Dependency {
+	var deps []Dependency
+	for _, m := range productPattern.FindAllStringSubmatch(line, -1) {
+		name := m[1]
+		if canonical, ok := productNames[strings.ToLower(name)]; ok {
+			name = canonical
+		}
+		deps = append(deps, Dependency{Name: name, Version: trimVersion(m[2]), Kind: DepProduct})
+	}
+	return deps
+}
+
+// dockerDependency splits an image reference into name and tag (or digest)
+func dockerDependency(ref string) Dependency {
+	ref = strings.Trim(ref, "`'\"")
+	if at := strings.Index(ref, "@"); at >= 0 {
+		return Dependency{Name: ref[:at], Version: ref[at+1:], Kind: DepDocker}
+	}
+	slash := strings.LastIndex(ref, "/")
+	if colon := strings.LastIndex(ref, ":"); colon > slash {
+		return Dependency{Name: ref[:colon], Version: ref[colon+1:], Kind: DepDocker}
+	}
+	return Dependency{Name: ref, Kind: DepDocker}
+}
+
+// splitNPMSpec splits "pkg@1.2.3" or "@scope/pkg@1.2.3" into name and version
+func splitNPMSpec(spec string) (string, string) {
+	if at := strings.LastIndex(spec, "@"); at > 0 {
+		return spec[:at], spec[at+1:]
+	}
+	return spec, ""
+}
+
+// trimVersion strips punctuation that ends a sentence or inline code span
+func trimVersion(v string) string {
+	return strings.TrimRight(strings.TrimSpace(v), ".,;:)`'\"")
+}
+
+// versionStatus classifies a single version string
+func versionStatus(version string) string {
+	switch {
+	case version == "":
+		return DepUnpinned
+	case floatingOperator.MatchString(version):
+		return DepFloating
+	default:
+		return DepPinned
+	}
+}
+
+// classifyDependencies sets each status and drops redundant unversioned product and module mentions
+// Products and modules are often named in prose after the versioned declaration, so a
+// bare mention only counts when the dependency has no version anywhere in the spec
+func classifyDependencies(deps []Dependency) []Dependency {
+	key := func(d Dependency) string { return d.Kind + "\x00" + strings.ToLower(d.Name) }
+
+	versioned := make(map[string]bool)
+	pinned := make(map[string]map[string]bool)
+	for i := range deps {
+		deps[i].Status = versionStatus(deps[i].Version)
+		k := key(deps[i])
+		if deps[i].Version != "" {
+			versioned[k] = true
+		}
+		if deps[i].Status == DepPinned {
+			if pinned[k] == nil {
+				pinned[k] = make(map[string]bool)
+			}
+			pinned[k][normalizeVersion(deps[i].Version)] = true
+		}
+	}
+
+	var result []Dependency
+	reported := make(map[string]bool)
+	for _, d := range deps {
+		k := key(d)
+		if (d.Kind == DepProduct || d.Kind == DepGo) && d.Version == "" {
+			if versioned[k] || reported[k] {
+				continue
+			}
+			reported[k] = true
+		}
+		if d.Status == DepPinned && len(pinned[k]) > 1 {
+			d.Status = DepInconsistent
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+// normalizeVersion makes "v1.2.3" and "1.2.3" compare equal
+func normalizeVersion(v string) string {
+	return strings.TrimPrefix(strings.ToLower(v), "v")
+}
+
+// analyzeDependencies reports every dependency that is not pinned consistently
+// A missing version is a warning; floating and conflicting versions are errors
+func analyzeDependencies(src *SpecSources) []AnalyzerIssue {
+	deps := ExtractDependencies(src)
+
+	// First location of each version of an inconsistently pinned dependency, in document order
+	type conflict struct {
+		first    Dependency
+		versions []string
+		seen     map[string]bool
+	}
+	conflicts := make(map[string]*conflict)
+	for _, d := range deps {
+		if d.Status != DepInconsistent {
+			continue
+		}
+		k := d.Kind + "\x00" + strings.ToLower(d.Name)
+		c, ok := conflicts[k]
+		if !ok {
+			c = &conflict{first: d, seen: make(map[string]bool)}
+			conflicts[k] = c
+		}
+		if v := normalizeVersion(d.Version); !c.seen[v] {
+			c.seen[v] = true
+			c.versions = append(c.versions, fmt.Sprintf("%s at %s", d.Version, d.Location()))
+		}
+	}
+
+	var issues []AnalyzerIssue
+	for _, d := range deps {
+		var msg string
+		severity := SeverityError
+		switch d.Status {
+		case DepUnpinned:
+			msg = fmt.Sprintf("%s has no version", d.Name)
+			severity = SeverityWarning
+		case DepFloating:
+			msg = fmt.Sprintf("%s %s is a floating version - pin an exact version", d.Name, d.Version)
+		case DepInconsistent:
+			// One issue per dependency, at its first declaration
+			c := conflicts[d.Kind+"\x00"+strings.ToLower(d.Name)]
+			if c.first != d {
+				continue
+			}
+			msg = fmt.Sprintf("%s has conflicting versions: %s", d.Name, strings.Join(c.versions, ", "))
+		default:
+			continue
+		}
+		issues = append(issues, AnalyzerIssue{
+			Rule:     ruleExactVersions,
+			Severity: severity,
+			File:     d.File,
+			Line:     d.Line,
+			Message:  msg,
+		})
+	}
+	return issues
+}
+
+// attributeReplacer substitutes {name} references with attribute values
+func attributeReplacer(attrs map[string]string) *strings.Replacer {
+	var pairs []string
+	for name, value := range attrs {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// DependencyInventory collapses repeated declarations into one entry per name, version and status
+type DependencyInventory struct {
+	Dependency
+	Count int `json:"count"` // Declarations with this version
+}
+
+// BuildDependencyInventory groups dependencies, keeping the first location of each
+func BuildDependencyInventory(deps []Dependency) []DependencyInventory {
+	var inventory []DependencyInventory
+	index := make(map[string]int)
+	for _, d := range deps {
+		k := d.Kind + "\x00" + strings.ToLower(d.Name) + "\x00" + d.Version + "\x00" + d.Status
+		if i, ok := index[k]; ok {
+			inventory[i].Count++
+			continue
+		}
+		index[k] = len(inventory)
+		inventory = append(inventory, DependencyInventory{Dependency: d, Count: 1})
+	}
+
+	sort.SliceStable(inventory, func(i, j int) bool {
+		if inventory[i].Kind != inventory[j].Kind {
+			return inventory[i].Kind < inventory[j].Kind
+		}
+		return strings.ToLower(inventory[i].Name) < strings.ToLower(inventory[j].Name)
+	})
+	return inventory
+}
+
+// FormatDependencyTable renders the inventory as an aligned table
+func FormatDependencyTable(inventory []DependencyInventory) string {
+	if len(inventory) == 0 {
+		return "No dependencies found.\n"
+	}
+
+	rows := [][]string{{"NAME", "VERSION", "KIND", "STATUS", "LOCATION"}}
+	for _, d := range inventory {
+		version := d.Version
+		if version == "" {
+			version = "-"
+		}
+		location := d.Location()
+		if d.Count > 1 {
+			location += fmt.Sprintf(" (+%d more)", d.Count-1)
+		}
+		rows = append(rows, []string{d.Name, version, d.Kind, d.Status, location})
+	}
+
+	return formatColumns(rows)
+}
+
+// formatColumns left-aligns rows of cells into columns; the first row is the header
+func formatColumns(rows [][]string) string {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	var sb strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				sb.WriteString(cell)
+			} else {
+				sb.WriteString(fmt.Sprintf("%-*s  ", widths[i], cell))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatDependencyJSON formats the inventory as JSON
+func FormatDependencyJSON(inventory []DependencyInventory) string {
+	if inventory == nil {
+		inventory = []DependencyInventory{}
+	}
+	data, _ := json.MarshalIndent(inventory, "", "  ")
+	return string(data)
+}
@@ -0,0 +1,271 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+func depSources(files map[string]string, attrs map[string]string) *SpecSources {
+	src := &SpecSources{ManifestPath: "MANIFEST.adoc", Attributes: attrs}
+	for _, name := range []string{"MANIFEST.adoc", "stack.adoc", "deploy.adoc"} {
+		if content, ok := files[name]; ok {
+			src.Documents = append(src.Documents, parser.ParseDocumentContent(content, name))
+		}
+	}
+	return src
+}
+
+func findDep(deps []Dependency, name string) *Dependency {
+	for i := range deps {
+		if deps[i].Name == name {
+			return &deps[i]
+		}
+	}
+	return nil
+}
+
+func TestExtractDependencies(t *testing.T) {
+	src := depSources(map[string]string{
+		"MANIFEST.adoc": `= Orders
+:pg-version: 16.2
+
+== Stack
+
+* PostgreSQL {pg-version}
+* Redis
+* Node.js 20.x
+* github.com/lib/pq@v1.10.9
+
+|===
+| Component | Version
+
+| Kafka
+| 3.7.0
+|===
+
+== Overview
+
+Go is mentioned here without a version, outside the stack section.
+`,
+		"deploy.adoc": `== Deployment
+
+[source,yaml]
+----
+services:
+  db:
+    image: postgres:16.1
+  cache:
+    image: redis
+  proxy:
+    image: nginx:latest
+----
+
+[source,dockerfile]
+----
+FROM golang:1.22.3 AS build
+FROM build
+----
+
+[source,json]
+----
+{
+  "dependencies": {
+    "react": "^18.2.0",
+    "zod": "3.23.8"
+  }
+}
+----
+
+Install with ` + "`npm install left-pad`" + `.
+`,
+	}, map[string]string{"pg-version": "16.2"})
+
+	deps := ExtractDependencies(src)
+
+	tests := []struct {
+		name    string
+		version string
+		kind    string
+		status  string
+	}{
+		{"PostgreSQL", "16.2", DepProduct, DepPinned},
+		{"Redis", "", DepProduct, DepUnpinned},
+		{"Node.js", "20.x", DepProduct, DepFloating},
+		{"github.com/lib/pq", "v1.10.9", DepGo, DepPinned},
+		{"Kafka", "3.7.0", DepProduct, DepPinned},
+		{"postgres", "16.1", DepDocker, DepPinned},
+		{"redis", "", DepDocker, DepUnpinned},
+		{"nginx", "latest", DepDocker, DepFloating},
+		{"golang", "1.22.3", DepDocker, DepPinned},
+		{"react", "^18.2.0", DepNPM, DepFloating},
+		{"zod", "3.23.8", DepNPM, DepPinned},
+		{"left-pad", "", DepNPM, DepUnpinned},
+	}
+	for _, tt := range tests {
+		d := findDep(deps, tt.name)
+		if d == nil {
+			t.Errorf("%s not found in %+v", tt.name, deps)
+			continue
+		}
+		if d.Version != tt.version || d.Kind != tt.kind || d.Status != tt.status {
+			t.Errorf("%s = %s %s %s, want %s %s %s", tt.name, d.Version, d.Kind, d.Status, tt.version, tt.kind, tt.status)
+		}
+	}
+
+	if findDep(deps, "Go") != nil {
+		t.Error("product names outside stack sections should be ignored")
+	}
+	if findDep(deps, "build") != nil {
+		t.Error("build stage alias reported as an image")
+	}
+	if d := findDep(deps, "PostgreSQL"); d.File != "MANIFEST.adoc" || d.Line != 6 {
+		t.Errorf("PostgreSQL location = %s", d.Location())
+	}
+}
+
+func TestExtractDependencies_LooseReferences(t *testing.T) {
+	src := depSources(map[string]string{
+		"MANIFEST.adoc": `= Auth
+
+== Stack
+
+*Dependencies:*
+
+* PostgreSQL 16 (Docker: postgres:16-alpine)
+* Redis 7 (Docker: redis:7-alpine)
+* github.com/gorilla/mux
+* github.com/lib/pq@v1.10.9
+
+Sessions are cached at localhost:6379 and api.example.com:443 serves tokens.
+
+== Operations
+
+Run ` + "`rabbitmq:latest`" + ` locally, see https://github.com/acme/runbook for details.
+Logs go through ` + "`github.com/acme/logkit`" + `; github.com/acme/tracing is not code.
+Queries go through ` + "`github.com/lib/pq`" + `, pinned above.
+
+* image: nginx:latest
+`,
+	}, nil)
+
+	deps := ExtractDependencies(src)
+
+	tests := []struct {
+		name    string
+		version string
+		kind    string
+		status  string
+		line    int
+	}{
+		{"postgres", "16-alpine", DepDocker, DepPinned, 7},
+		{"redis", "7-alpine", DepDocker, DepPinned, 8},
+		{"github.com/gorilla/mux", "", DepGo, DepUnpinned, 9},
+		{"rabbitmq", "latest", DepDocker, DepFloating, 16},
+		{"github.com/acme/logkit", "", DepGo, DepUnpinned, 17},
+		{"nginx", "latest", DepDocker, DepFloating, 20},
+	}
+	for _, tt := range tests {
+		d := findDep(deps, tt.name)
+		if d == nil {
+			t.Errorf("%s not found in %+v", tt.name, deps)
+			continue
+		}
+		if d.Version != tt.version || d.Kind != tt.kind || d.Status != tt.status || d.Line != tt.line {
+			t.Errorf("%s = %s %s %s at line %d, want %s %s %s at line %d", tt.name, d.Version, d.Kind, d.Status, d.Line, tt.version, tt.kind, tt.status, tt.line)
+		}
+	}
+
+	for _, name := range []string{"localhost", "api.example.com", "github.com/acme/runbook", "github.com/acme/tracing"} {
+		if findDep(deps, name) != nil {
+			t.Errorf("%s reported as a dependency", name)
+		}
+	}
+	if d := findDep(deps, "github.com/lib/pq"); d == nil || d.Version != "v1.10.9" {
+		t.Errorf("unversioned mention of a pinned module reported: %+v", deps)
+	}
+	if n := len(deps); n != len(tests)+3 {
+		t.Errorf("got %d dependencies, want %d: %+v", n, len(tests)+3, deps)
+	}
+}
+
+func TestAnalyzeDependenciesInconsistent(t *testing.T) {
+	src := depSources(map[string]string{
+		"MANIFEST.adoc": "= Orders\n\n== Stack\n\n* PostgreSQL 16.2\n\nPostgreSQL stores orders.\n",
+		"stack.adoc":    "== Dependencies\n\n* PostgreSQL 15\n* github.com/lib/pq v1.10.9\n",
+	}, nil)
+
+	issues := analyzeDependencies(src)
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %+v", issues)
+	}
+	if issues[0].Rule != "exact-versions" || issues[0].Severity != SeverityError || issues[0].Location() != "MANIFEST.adoc:5" {
+		t.Errorf("unexpected rule/severity/location: %+v", issues[0])
+	}
+	if issues[0].Message != "PostgreSQL has conflicting versions: 16.2 at MANIFEST.adoc:5, 15 at stack.adoc:3" {
+		t.Errorf("message = %q", issues[0].Message)
+	}
+
+	// Both versions on one line are still a single issue
+	src = depSources(map[string]string{
+		"MANIFEST.adoc": "= Orders\n\n== Stack\n\n* Node.js 20 (CI runs Node.js 18)\n",
+	}, nil)
+	issues = analyzeDependencies(src)
+	if len(issues) != 1 || issues[0].Message != "Node.js has conflicting versions: 20 at MANIFEST.adoc:5, 18 at MANIFEST.adoc:5" {
+		t.Errorf("issues = %+v", issues)
+	}
+}
+
+func TestVersionStatus(t *testing.T) {
+	tests := map[string]string{
+		"":         DepUnpinned,
+		"1.2.3":    DepPinned,
+		"v1.10.9":  DepPinned,
+		"16":       DepPinned,
+		"7-alpine": DepPinned,
+		"latest":   DepFloating,
+		"^18.2.0":  DepFloating,
+		"~1.2":     DepFloating,
+		">= 3.10":  DepFloating,
+		"3.12+":    DepFloating,
+		"20.x":     DepFloating,
+		"*":        DepFloating,
+	}
+	for version, want := range tests {
+		if got := versionStatus(version); got != want {
+			t.Errorf("versionStatus(%q) = %s, want %s", version, got, want)
+		}
+	}
+}
+
+func TestDependencyInventory(t *testing.T) {
+	deps := []Dependency{
+		{Name: "redis", Version: "7", Kind: DepDocker, Status: DepPinned, File: "a.adoc", Line: 3},
+		{Name: "redis", Version: "7", Kind: DepDocker, Status: DepPinned, File: "b.adoc", Line: 9},
+		{Name: "Go", Version: "1.22", Kind: DepProduct, Status: DepPinned, File: "a.adoc", Line: 1},
+	}
+	inventory := BuildDependencyInventory(deps)
+	if len(inventory) != 2 || inventory[0].Name != "redis" || inventory[0].Count != 2 {
+		t.Fatalf("unexpected inventory: %+v", inventory)
+	}
+
+	table := FormatDependencyTable(inventory)
+	if !strings.Contains(table, "a.adoc:3 (+1 more)") || !strings.HasPrefix(table, "NAME") {
+		t.Errorf("unexpected table:\n%s", table)
+	}
+}
+
+func TestAnalyzeDependenciesSeverity(t *testing.T) {
+	src := depSources(map[string]string{
+		"MANIFEST.adoc": "= Orders\n\n== Stack\n\n* Redis\n* Node.js 20.x\n",
+	}, nil)
+
+	severities := make(map[string]string)
+	for _, issue := range analyzeDependencies(src) {
+		severities[strings.Fields(issue.Message)[0]] = issue.Severity
+	}
+	if severities["Redis"] != SeverityWarning || severities["Node.js"] != SeverityError {
+		t.Errorf("severities = %v, want a warning for Redis and an error for Node.js", severities)
+	}
+}
@@ -0,0 +1,203 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/compiler"
+)
+
+// DryRun is every prompt a semantic validation would send, rendered without calling the provider
+type DryRun struct {
+	Provider     string          `json:"provider"`
+	Prompts      []PlannedPrompt `json:"prompts"`
+	Calls        int             `json:"calls"`
+	InputTokens  int             `json:"input_tokens"`
+	OutputTokens int             `json:"output_tokens"` // estimatedOutputTokens per call
+	Cost         float64         `json:"cost_usd,omitempty"`
+	Priced       bool            `json:"-"`
+	Note         string          `json:"note,omitempty"` // Why nothing would be sent, e.g. no changes since --since
+}
+
+// PlannedPrompt is one distinct prompt and the calls that would send it
+// Ultra runs send the same prompts, so each is listed once with every run's call label
+type PlannedPrompt struct {
+	Name     string   `json:"name"` // File name stem with --out: validate, chunk-2, outline, synthesize
+	Template string   `json:"template"`
+	Calls    []string `json:"calls"`        // Labels as in the usage report, e.g. "run 2 / chunk 1/3"
+	Tokens   int      `json:"input_tokens"` // Per call, including the run reports a synthesis reads
+	Skeleton bool     `json:"skeleton,omitempty"`
+	Text     string   `json:"prompt"`
+}
+
+// PlanDryRun renders the prompts Validate would send for the options, applying --section,
+// --file, --since, chunking and ultra runs the same way, and estimates their tokens
+// The synthesis prompt is a skeleton: the run reports it reads are placeholders
+func PlanDryRun(manifestPath string, opts ValidationOptions) (*DryRun, error) {
+	compiledSpec, err := compiler.Compile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile spec: %w", err)
+	}
+
+	if opts.Section != "" || opts.File != "" {
+		if opts.Slice, err = ResolveSlice(manifestPath, opts.Section, opts.File); err != nil {
+			return nil, err
+		}
+	}
+	return planDryRun(manifestPath, compiledSpec, opts)
+}
+
+// planDryRun renders the prompts for an already compiled spec
+func planDryRun(manifestPath, compiledSpec string, opts ValidationOptions) (*DryRun, error) {
+	p := opts.provider()
+	plan := &DryRun{Provider: ProviderLabel(p)}
+
+	specToValidate, incremental, err := semanticInput(manifestPath, compiledSpec, &opts)
+	if err != nil {
+		return nil, err
+	}
+	if incremental != nil && len(incremental.Changed) == 0 {
+		plan.Note = fmt.Sprintf("No section changes since %s - recorded findings would be reused without calling the provider.", opts.Since)
+		return plan, nil
+	}
+
+	if opts.Chunks != nil {
+		prompts, err := chunkPrompts(opts.Chunks, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i, prompt := range prompts {
+			name, template, label := "outline", "outline", "outline"
+			if i < len(opts.Chunks.Chunks) {
+				name, template = fmt.Sprintf("chunk-%d", i+1), "validate"
+				label = fmt.Sprintf("chunk %d/%d", i+1, len(opts.Chunks.Chunks))
+			}
+			plan.Prompts = append(plan.Prompts, PlannedPrompt{Name: name, Template: template, Calls: []string{label}, Text: prompt})
+		}
+	} else {
+		prompt, err := opts.Prompts.Render("validate", opts.validateData(specToValidate))
+		if err != nil {
+			return nil, fmt.Errorf("failed to render prompt: %w", err)
+		}
+		plan.Prompts = []PlannedPrompt{{Name: "validate", Template: "validate", Calls: []string{"validate"}, Text: prompt}}
+	}
+	for i := range plan.Prompts {
+		plan.Prompts[i].Tokens = EstimateTokens(plan.Prompts[i].Text)
+	}
+
+	if opts.Ultra {
+		runs := opts.ultraRuns()
+		for i := range plan.Prompts {
+			label := plan.Prompts[i].Calls[0]
+			plan.Prompts[i].Calls = nil
+			for run := 1; run <= runs; run++ {
+				runLabel := fmt.Sprintf("run %d", run)
+				if opts.Chunks != nil {
+					runLabel += " / " + label
+				}
+				plan.Prompts[i].Calls = append(plan.Prompts[i].Calls, runLabel)
+			}
+		}
+
+		if opts.Synthesize {
+			reports := make([]string, runs)
+			for i := range reports {
+				reports[i] = fmt.Sprintf("<report from run %d>", i+1)
+			}
+			skeleton, err := opts.Prompts.Render("synthesize", TemplateData{Runs: reports})
+			if err != nil {
+				return nil, fmt.Errorf("failed to render synthesis prompt: %w", err)
+			}
+			plan.Prompts = append(plan.Prompts, PlannedPrompt{
+				Name:     "synthesize",
+				Template: "synthesize",
+				Calls:    []string{"synthesis"},
+				Tokens:   EstimateTokens(skeleton) + runs*estimatedOutputTokens,
+				Skeleton: true,
+				Text:     skeleton,
+			})
+		}
+	}
+
+	for _, prompt := range plan.Prompts {
+		plan.Calls += len(prompt.Calls)
+		plan.InputTokens += len(prompt.Calls) * prompt.Tokens
+	}
+	plan.OutputTokens = plan.Calls * estimatedOutputTokens
+	if price, ok := PriceFor(opts.Pricing, p); ok {
+		plan.Priced = true
+		plan.Cost = usageCost(Usage{InputTokens: plan.InputTokens, OutputTokens: plan.OutputTokens}, price)
+	}
+	return plan, nil
+}
+
+// FormatDryRunSummary lists the calls a run would make with their estimated tokens
+func FormatDryRunSummary(plan *DryRun) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "=== Dry Run (%s, nothing sent) ===\n\n", plan.Provider)
+	if plan.Note != "" {
+		sb.WriteString(plan.Note + "\n")
+		return sb.String()
+	}
+
+	for _, prompt := range plan.Prompts {
+		calls := strings.Join(prompt.Calls, ", ")
+		if len(prompt.Calls) > 3 {
+			calls = fmt.Sprintf("%s ... %s (%d calls)", prompt.Calls[0], prompt.Calls[len(prompt.Calls)-1], len(prompt.Calls))
+		}
+		note := ""
+		if prompt.Skeleton {
+			note = fmt.Sprintf(" (skeleton; includes ~%d per run report)", estimatedOutputTokens)
+		}
+		fmt.Fprintf(&sb, "  %-12s ~%7d in per call  %s%s\n", prompt.Name, prompt.Tokens, calls, note)
+	}
+	fmt.Fprintf(&sb, "\n  %d call(s), ~%d input + ~%d output tokens", plan.Calls, plan.InputTokens, plan.OutputTokens)
+	if plan.Priced {
+		fmt.Fprintf(&sb, ", ~$%.2f", plan.Cost)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatDryRun formats the summary followed by each distinct prompt
+func FormatDryRun(plan *DryRun) string {
+	var sb strings.Builder
+	sb.WriteString(FormatDryRunSummary(plan))
+	for _, prompt := range plan.Prompts {
+		fmt.Fprintf(&sb, "\n=== Prompt: %s (%s) ===\n", prompt.Name, strings.Join(prompt.Calls, ", "))
+		sb.WriteString(prompt.Text)
+		if !strings.HasSuffix(prompt.Text, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// FormatDryRunJSON formats the dry run as JSON
+func FormatDryRunJSON(plan *DryRun) string {
+	if plan.Prompts == nil {
+		plan.Prompts = []PlannedPrompt{}
+	}
+	data, _ := json.MarshalIndent(plan, "", "  ")
+	return string(data)
+}
+
+// SaveDryRun writes each distinct prompt to dir as <name>.md and returns the paths written
+func SaveDryRun(plan *DryRun, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	var written []string
+	for _, prompt := range plan.Prompts {
+		path := filepath.Join(dir, prompt.Name+".md")
+		if err := os.WriteFile(path, []byte(prompt.Text), 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func TestPlanDryRun(t *testing.T) {
+	provider := NewScriptedProvider()
+
+	plan, err := planDryRun("MANIFEST.adoc", chunkedSpec, ValidationOptions{Provider: provider})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Prompts) != 1 || plan.Calls != 1 || plan.Prompts[0].Calls[0] != "validate" {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if !strings.Contains(plan.Prompts[0].Text, "PostgreSQL stores orders.") || plan.InputTokens != plan.Prompts[0].Tokens {
+		t.Errorf("validate prompt = %d tokens, plan %d", plan.Prompts[0].Tokens, plan.InputTokens)
+	}
+	if len(provider.Prompts()) != 0 {
+		t.Error("a dry run must not call the provider")
+	}
+
+	ultra, err := planDryRun("MANIFEST.adoc", chunkedSpec, ValidationOptions{
+		Provider:   provider,
+		Ultra:      true,
+		Runs:       3,
+		Synthesize: true,
+		Pricing:    map[string]config.Price{"scripted": {Input: 3, Output: 15}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(ultra.Prompts[0].Calls, ", "); got != "run 1, run 2, run 3" {
+		t.Errorf("validate calls = %q", got)
+	}
+	synthesis := ultra.Prompts[1]
+	if !synthesis.Skeleton || !strings.Contains(synthesis.Text, "<report from run 3>") || synthesis.Tokens <= 3*estimatedOutputTokens {
+		t.Errorf("synthesis = %+v", synthesis)
+	}
+	if ultra.Calls != 4 || ultra.InputTokens != 3*ultra.Prompts[0].Tokens+synthesis.Tokens || ultra.OutputTokens != 4*estimatedOutputTokens {
+		t.Errorf("totals: %d calls, %d in, %d out", ultra.Calls, ultra.InputTokens, ultra.OutputTokens)
+	}
+	if !ultra.Priced || ultra.Cost <= 0 {
+		t.Error("configured pricing should give a cost estimate")
+	}
+	if out := FormatDryRunSummary(ultra); !strings.Contains(out, "4 call(s)") || !strings.Contains(out, "skeleton") {
+		t.Errorf("summary:\n%s", out)
+	}
+}
+
+func TestPlanDryRun_Chunked(t *testing.T) {
+	large := chunkedSpec + strings.Repeat("\n## Notes\n\n"+strings.Repeat("Orders are immutable once paid. ", 200)+"\n", 10)
+
+	plan, err := planDryRun("MANIFEST.adoc", large, ValidationOptions{Provider: NewScriptedProvider(), Ultra: true, Runs: 2, ChunkSize: 20 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, prompt := range plan.Prompts {
+		names = append(names, prompt.Name)
+	}
+	if len(names) < 3 || names[0] != "chunk-1" || names[len(names)-1] != "outline" {
+		t.Fatalf("prompts = %v", names)
+	}
+	if got := strings.Join(plan.Prompts[len(names)-1].Calls, ", "); got != "run 1 / outline, run 2 / outline" {
+		t.Errorf("outline calls = %q", got)
+	}
+	if plan.Calls != 2*len(names) {
+		t.Errorf("calls = %d, want %d", plan.Calls, 2*len(names))
+	}
+
+	dir := filepath.Join(t.TempDir(), "prompts")
+	written, err := SaveDryRun(plan, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "outline.md"))
+	if err != nil || len(written) != len(names) || string(data) != plan.Prompts[len(names)-1].Text {
+		t.Errorf("saved %v (%v)", written, err)
+	}
+}
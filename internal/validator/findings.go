@@ -0,0 +1,151 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Finding is a single issue reported by semantic validation
+type Finding struct {
+	Rule       string  `json:"rule"`
+	Location   string  `json:"location"`
+	Issue      string  `json:"issue"`
+	Suggestion string  `json:"suggestion,omitempty"`
+	Severity   string  `json:"severity,omitempty"`   // error, warning or info
+	Section    string  `json:"section,omitempty"`    // Compiled section the location resolves to
+	Confidence float64 `json:"confidence,omitempty"` // Fraction of ultra runs reporting it
+	Runs       []int   `json:"runs,omitempty"`       // Ultra runs (1-based) reporting it
+}
+
+// FindingsRecord is the persisted set of findings from the last semantic run
+type FindingsRecord struct {
+	Commit    string    `json:"commit,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Findings  []Finding `json:"findings"`
+}
+
+// Matches "- Rule: x", "- **Rule:** x", "**Rule**: x" and similar field lines
+var findingFieldPattern = regexp.MustCompile(`^[-*\s]*\**(Rule|Location|Issue|Suggestion|Severity)\**\s*:\s*\**\s*(.*)$`)
+
+// ParseFindings extracts findings from validation output in the prompt's output format
+func ParseFindings(output string) []Finding {
+	var findings []Finding
+	var current *Finding
+
+	for _, line := range strings.Split(output, "\n") {
+		matches := findingFieldPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+
+		value := strings.TrimSpace(strings.Trim(matches[2], "*`"))
+		if matches[1] == "Rule" {
+			findings = append(findings, Finding{Rule: value})
+			current = &findings[len(findings)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch matches[1] {
+		case "Location":
+			current.Location = value
+		case "Issue":
+			current.Issue = value
+		case "Suggestion":
+			current.Suggestion = value
+		case "Severity":
+			current.Severity = value
+		}
+	}
+
+	return findings
+}
+
+// AssignSections resolves each finding's location to a section title
+// The longest title mentioned in the location wins; unresolved findings keep an empty Section
+func AssignSections(findings []Finding, titles []string) {
+	for i := range findings {
+		location := strings.ToLower(findings[i].Location)
+		best := ""
+		for _, title := range titles {
+			if title != "" && strings.Contains(location, strings.ToLower(title)) && len(title) > len(best) {
+				best = title
+			}
+		}
+		findings[i].Section = best
+	}
+}
+
+// FormatFindings formats findings in the same layout the validate prompt asks for
+func FormatFindings(findings []Finding) string {
+	if len(findings) == 0 {
+		return "No findings.\n"
+	}
+
+	var sb strings.Builder
+	for _, f := range findings {
+		sb.WriteString(fmt.Sprintf("- Rule: %s\n", f.Rule))
+		if f.Severity != "" {
+			sb.WriteString(fmt.Sprintf("- Severity: %s\n", f.Severity))
+		}
+		sb.WriteString(fmt.Sprintf("- Location: %s\n", f.Location))
+		sb.WriteString(fmt.Sprintf("- Issue: %s\n", f.Issue))
+		if f.Suggestion != "" {
+			sb.WriteString(fmt.Sprintf("- Suggestion: %s\n", f.Suggestion))
+		}
+		if f.Confidence > 0 {
+			sb.WriteString(fmt.Sprintf("- Confidence: %.2f (%s)\n", f.Confidence, formatRuns(f.Runs)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// findingsPath returns the location of the persisted findings for a spec
+func findingsPath(manifestPath string) string {
+	return filepath.Join(StateDir(manifestPath), "findings.json")
+}
+
+// StateDir returns the .cca directory that holds cca state for a spec
+func StateDir(manifestPath string) string {
+	return filepath.Join(filepath.Dir(manifestPath), ".cca")
+}
+
+// LoadFindings loads the findings recorded by the last semantic run
+// Returns nil without error if nothing has been recorded yet
+func LoadFindings(manifestPath string) (*FindingsRecord, error) {
+	data, err := os.ReadFile(findingsPath(manifestPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record FindingsRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", findingsPath(manifestPath), err)
+	}
+	return &record, nil
+}
+
+// SaveFindings records findings so later incremental runs can reuse them
+func SaveFindings(manifestPath string, record *FindingsRecord) error {
+	path := findingsPath(manifestPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
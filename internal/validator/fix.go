@@ -0,0 +1,340 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+// Lines of unchanged context shown around each fix diff
+const fixDiffContext = 2
+
+// FixEdit is a machine-applicable change to one AsciiDoc source file
+type FixEdit struct {
+	File        string `json:"file"`        // Path relative to the manifest directory
+	Line        int    `json:"line"`        // First replaced line (1-based)
+	EndLine     int    `json:"end_line"`    // Last replaced line (inclusive)
+	Original    string `json:"original"`    // Current text of the replaced lines
+	Replacement string `json:"replacement"` // New text for those lines ("" deletes them)
+	Rule        string `json:"rule"`
+	Reason      string `json:"reason"`
+}
+
+// SkippedFix is a finding the provider could not fix without the author's input
+type SkippedFix struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// FixProposal is the provider's answer to a fix request, after checking every edit
+type FixProposal struct {
+	Edits    []FixEdit
+	Skipped  []SkippedFix
+	Rejected []string     // Edits dropped because they do not match the sources
+	Usage    *UsageReport // Tokens and cost of the fix call
+}
+
+// SourceFile is an AsciiDoc source passed to the fix prompt
+type SourceFile struct {
+	Path    string // Relative to the manifest directory
+	Content string // Lines prefixed with "N | "
+}
+
+// ProposeFixes asks the provider for edits to the AsciiDoc sources that resolve findings
+// Only the files holding the findings' sections are sent, or every file when a finding
+// cannot be placed. Edits that do not match the current sources are rejected.
+// A prompt estimated over the configured budget is refused with a *BudgetError.
+func ProposeFixes(ctx context.Context, manifestPath string, findings []Finding, opts ValidationOptions) (*FixProposal, error) {
+	baseDir := filepath.Dir(manifestPath)
+
+	files, err := fixSourceFiles(manifestPath, findings)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []SourceFile
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(baseDir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		sources = append(sources, SourceFile{Path: file, Content: numberLines(string(data))})
+	}
+
+	prompt, err := opts.Prompts.Render("fix", TemplateData{
+		Findings: FormatFindings(findings),
+		Sources:  sources,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render fix prompt: %w", err)
+	}
+
+	resp, usage, err := completeWithinBudget(ctx, "fix", prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply struct {
+		Edits   []FixEdit    `json:"edits"`
+		Skipped []SkippedFix `json:"skipped"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(resp.Text)), &reply); err != nil {
+		return nil, fmt.Errorf("provider did not return valid fix JSON: %w", err)
+	}
+
+	allowed := make(map[string]bool)
+	for _, file := range files {
+		allowed[file] = true
+	}
+
+	proposal := &FixProposal{Skipped: reply.Skipped, Usage: usage}
+	for _, edit := range reply.Edits {
+		edit.File = filepath.ToSlash(filepath.Clean(edit.File))
+		if !allowed[edit.File] {
+			proposal.Rejected = append(proposal.Rejected, fmt.Sprintf("%s: not one of the spec's source files", edit.File))
+			continue
+		}
+		if err := locateEdit(baseDir, &edit); err != nil {
+			proposal.Rejected = append(proposal.Rejected, fmt.Sprintf("%s:%d: %v", edit.File, edit.Line, err))
+			continue
+		}
+		if other := overlappingEdit(proposal.Edits, edit); other != nil {
+			proposal.Rejected = append(proposal.Rejected, fmt.Sprintf("%s:%d: overlaps the edit at line %d", edit.File, edit.Line, other.Line))
+			continue
+		}
+		proposal.Edits = append(proposal.Edits, edit)
+	}
+
+	return proposal, nil
+}
+
+// FormatFixDiff renders an edit as a unified diff with a little surrounding context
+func FormatFixDiff(baseDir string, edit FixEdit) (string, error) {
+	lines, err := readLines(filepath.Join(baseDir, edit.File))
+	if err != nil {
+		return "", err
+	}
+
+	start := max(edit.Line-1-fixDiffContext, 0)
+	end := min(edit.EndLine+fixDiffContext, len(lines))
+	replacement := splitReplacement(edit.Replacement)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", edit.File, edit.File))
+	oldCount := end - start
+	newCount := oldCount - (edit.EndLine - edit.Line + 1) + len(replacement)
+	sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", start+1, oldCount, start+1, newCount))
+
+	for i := start; i < edit.Line-1; i++ {
+		sb.WriteString(" " + lines[i] + "\n")
+	}
+	for i := edit.Line - 1; i < edit.EndLine; i++ {
+		sb.WriteString("-" + lines[i] + "\n")
+	}
+	for _, line := range replacement {
+		sb.WriteString("+" + line + "\n")
+	}
+	for i := edit.EndLine; i < end; i++ {
+		sb.WriteString(" " + lines[i] + "\n")
+	}
+	return sb.String(), nil
+}
+
+// ApplyFixEdits writes accepted edits to the source files
+// Edits are applied bottom-up per file so earlier line numbers stay valid
+func ApplyFixEdits(baseDir string, edits []FixEdit) ([]string, error) {
+	byFile := make(map[string][]FixEdit)
+	var files []string
+	for _, edit := range edits {
+		if _, ok := byFile[edit.File]; !ok {
+			files = append(files, edit.File)
+		}
+		byFile[edit.File] = append(byFile[edit.File], edit)
+	}
+
+	for _, file := range files {
+		path := filepath.Join(baseDir, file)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		trailingNewline := strings.HasSuffix(string(data), "\n")
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+
+		fileEdits := byFile[file]
+		sort.Slice(fileEdits, func(i, j int) bool { return fileEdits[i].Line > fileEdits[j].Line })
+		for _, edit := range fileEdits {
+			var updated []string
+			updated = append(updated, lines[:edit.Line-1]...)
+			updated = append(updated, splitReplacement(edit.Replacement)...)
+			updated = append(updated, lines[edit.EndLine:]...)
+			lines = updated
+		}
+
+		content := strings.Join(lines, "\n")
+		if trailingNewline {
+			content += "\n"
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(content), info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+
+	return files, nil
+}
+
+// fixSourceFiles returns the source files (relative to the manifest) holding the findings
+func fixSourceFiles(manifestPath string, findings []Finding) ([]string, error) {
+	structure, err := parser.BuildStructure(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec structure: %w", err)
+	}
+	baseDir := filepath.Dir(manifestPath)
+
+	all := append([]string{manifestPath}, structure.Files...)
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(baseDir, path); err == nil {
+			return filepath.ToSlash(rel)
+		}
+		return filepath.ToSlash(path)
+	}
+
+	selected := make(map[string]bool)
+	unplaced := false
+	for _, f := range findings {
+		file := ""
+		for _, section := range structure.Sections {
+			if f.Section != "" && strings.EqualFold(section.Title, f.Section) {
+				file = section.FilePath
+				break
+			}
+		}
+		if file == "" {
+			unplaced = true
+			break
+		}
+		selected[relative(file)] = true
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, path := range all {
+		rel := relative(path)
+		if seen[rel] || (!unplaced && !selected[rel]) {
+			continue
+		}
+		seen[rel] = true
+		files = append(files, rel)
+	}
+	return files, nil
+}
+
+// locateEdit checks an edit against the file, relocating it when the provider
+// got the line numbers wrong but the original text appears exactly once
+func locateEdit(baseDir string, edit *FixEdit) error {
+	lines, err := readLines(filepath.Join(baseDir, edit.File))
+	if err != nil {
+		return err
+	}
+
+	original := splitReplacement(edit.Original)
+	if len(original) == 0 {
+		return fmt.Errorf("edit has no original text")
+	}
+	if edit.EndLine < edit.Line {
+		edit.EndLine = edit.Line + len(original) - 1
+	}
+
+	matchesAt := func(start int) bool {
+		if start < 0 || start+len(original) > len(lines) {
+			return false
+		}
+		for i, line := range original {
+			if strings.TrimRight(lines[start+i], " \t") != strings.TrimRight(line, " \t") {
+				return false
+			}
+		}
+		return true
+	}
+
+	if edit.EndLine-edit.Line+1 == len(original) && matchesAt(edit.Line-1) {
+		return nil
+	}
+
+	found := -1
+	for start := range lines {
+		if matchesAt(start) {
+			if found >= 0 {
+				return fmt.Errorf("original text does not match and appears more than once")
+			}
+			found = start
+		}
+	}
+	if found < 0 {
+		return fmt.Errorf("original text not found (file changed since validation?)")
+	}
+
+	edit.Line = found + 1
+	edit.EndLine = found + len(original)
+	return nil
+}
+
+// overlappingEdit returns an edit in edits touching the same lines of the same file
+func overlappingEdit(edits []FixEdit, edit FixEdit) *FixEdit {
+	for i := range edits {
+		other := &edits[i]
+		if other.File == edit.File && edit.Line <= other.EndLine && other.Line <= edit.EndLine {
+			return other
+		}
+	}
+	return nil
+}
+
+// extractJSONObject returns the outermost {...} in text, dropping code fences or prose around it
+func extractJSONObject(text string) string {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return text
+	}
+	return text[start : end+1]
+}
+
+// numberLines prefixes each line with its 1-based line number
+func numberLines(content string) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	width := len(fmt.Sprint(len(lines)))
+
+	var sb strings.Builder
+	for i, line := range lines {
+		sb.WriteString(fmt.Sprintf("%*d | %s\n", width, i+1, line))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// splitReplacement splits edit text into lines; empty text is zero lines
+func splitReplacement(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
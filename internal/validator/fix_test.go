@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func writeFixSpec(t *testing.T) string {
+	t.Helper()
+	return writeSpecFiles(t, map[string]string{
+		"MANIFEST.adoc": "= Orders\n\ninclude::stack.adoc[]\n",
+		"stack.adoc":    "== Stack\n\n* PostgreSQL\n* Redis 7.2\n",
+	})
+}
+
+func TestProposeAndApplyFixes(t *testing.T) {
+	manifest := writeFixSpec(t)
+	dir := filepath.Dir(manifest)
+
+	// Line numbers are off by one: the edit is relocated by its original text
+	reply := "```json\n" + `{"edits": [
+		{"file": "stack.adoc", "line": 4, "end_line": 4, "original": "* PostgreSQL", "replacement": "* PostgreSQL 16.2", "rule": "exact-versions", "reason": "Pin the database"},
+		{"file": "../outside.adoc", "line": 1, "end_line": 1, "original": "x", "replacement": "y", "rule": "exact-versions", "reason": "bad path"}
+	], "skipped": [{"rule": "deployment", "reason": "Pick a platform"}]}` + "\n```"
+	p := NewScriptedProvider(reply)
+
+	findings := []Finding{{Rule: "exact-versions", Location: "Stack", Issue: "PostgreSQL has no version", Section: "Stack"}}
+	proposal, err := ProposeFixes(context.Background(), manifest, findings, ValidationOptions{Provider: p})
+	if err != nil {
+		t.Fatalf("ProposeFixes failed: %v", err)
+	}
+
+	if !strings.Contains(p.Prompts()[0], "3 | * PostgreSQL") {
+		t.Errorf("prompt lacks numbered source lines:\n%s", p.Prompts()[0])
+	}
+	if len(proposal.Edits) != 1 || proposal.Edits[0].Line != 3 {
+		t.Fatalf("expected one edit relocated to line 3, got %+v", proposal.Edits)
+	}
+	if proposal.Usage == nil || len(proposal.Usage.Calls) != 1 || proposal.Usage.Calls[0].Label != "fix" {
+		t.Errorf("expected usage for one fix call, got %+v", proposal.Usage)
+	}
+	if len(proposal.Rejected) != 1 || len(proposal.Skipped) != 1 {
+		t.Errorf("expected one rejected and one skipped, got %v / %v", proposal.Rejected, proposal.Skipped)
+	}
+
+	diff, err := FormatFixDiff(dir, proposal.Edits[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "@@ -1,4 +1,4 @@\n") || !strings.Contains(diff, "-* PostgreSQL\n+* PostgreSQL 16.2\n") {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+
+	if _, err := ApplyFixEdits(dir, proposal.Edits); err != nil {
+		t.Fatalf("ApplyFixEdits failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "stack.adoc"))
+	if string(data) != "== Stack\n\n* PostgreSQL 16.2\n* Redis 7.2\n" {
+		t.Errorf("unexpected file after fix:\n%s", data)
+	}
+}
+
+func TestProposeFixes_OverBudget(t *testing.T) {
+	manifest := writeFixSpec(t)
+	p := NewScriptedProvider(`{"edits": []}`)
+
+	findings := []Finding{{Rule: "exact-versions", Location: "Stack", Issue: "PostgreSQL has no version", Section: "Stack"}}
+	_, err := ProposeFixes(context.Background(), manifest, findings, ValidationOptions{Provider: p, Budget: config.BudgetConfig{Tokens: 100}})
+
+	var overBudget *BudgetError
+	if !errors.As(err, &overBudget) {
+		t.Fatalf("err = %v, want a BudgetError", err)
+	}
+	if len(p.Prompts()) != 0 {
+		t.Error("over-budget fix should not call the provider")
+	}
+}
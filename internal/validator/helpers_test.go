@@ -0,0 +1,34 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSpecFiles writes spec files, keyed by slash-separated path, to a temporary directory
+// and returns the path of its MANIFEST.adoc
+func writeSpecFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "MANIFEST.adoc")
+}
+
+// loadSpecFiles writes spec files with writeSpecFiles and loads their sources
+func loadSpecFiles(t *testing.T, files map[string]string) *SpecSources {
+	t.Helper()
+	src, err := LoadSpecSources(writeSpecFiles(t, files))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
@@ -0,0 +1,307 @@
+package validator
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// History modes: quick runs count structural issues only, semantic runs add findings
+const (
+	HistoryQuick    = "quick"
+	HistorySemantic = "semantic"
+)
+
+// HistoryEntry is one recorded validation run
+type HistoryEntry struct {
+	Commit     string         `json:"commit,omitempty"`
+	CommitTime time.Time      `json:"commit_time,omitzero"` // Orders entries, so back-filled runs sort by history
+	Timestamp  time.Time      `json:"timestamp"`            // When the run was recorded
+	SpecHash   string         `json:"spec_hash"`            // Hash of the spec source files
+	Mode       string         `json:"mode"`
+	Passed     bool           `json:"passed"` // Structural checks passed
+	Rules      map[string]int `json:"rules"`  // Issues and findings per rule
+}
+
+// HistoryRegression is a rule whose count rose between the last two recorded commits
+type HistoryRegression struct {
+	Rule     string
+	Previous int
+	Current  int
+}
+
+// HistoryTrend is the per-rule history of one mode, one column per commit
+type HistoryTrend struct {
+	Mode        string
+	Entries     []HistoryEntry // Latest entry per commit, oldest first
+	Rules       []string
+	Regressions []HistoryRegression
+	Runs        int // Entries in the file for this mode, including superseded ones
+}
+
+// historyPath returns the location of the run history for a spec
+func historyPath(manifestPath string) string {
+	return filepath.Join(StateDir(manifestPath), "history.jsonl")
+}
+
+// NewHistoryEntry summarizes a result for the history file
+// Cancelled runs are not recorded, so callers should check result.Cancelled first
+func NewHistoryEntry(manifestPath string, result *ValidationResult) (*HistoryEntry, error) {
+	hash, err := SpecHash(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := HistoryQuick
+	if result.SemanticRun {
+		mode = HistorySemantic
+	}
+	return &HistoryEntry{
+		Timestamp: time.Now().UTC(),
+		SpecHash:  hash,
+		Mode:      mode,
+		Passed:    result.StructuralPassed,
+		Rules:     RuleCounts(result),
+	}, nil
+}
+
+// RuleCounts counts analyzer issues and findings per rule
+// A failed structural check without located issues counts once under its check ID
+func RuleCounts(result *ValidationResult) map[string]int {
+	counts := make(map[string]int)
+	for _, check := range result.StructuralChecks {
+		for _, issue := range check.Issues {
+			counts[issue.Rule]++
+		}
+		if !check.Passed && len(check.Issues) == 0 {
+			counts[check.ID]++
+		}
+	}
+	for _, f := range result.Findings {
+		counts[strings.ToLower(f.Rule)]++
+	}
+	return counts
+}
+
+// SpecHash hashes the manifest and every included file, so edits anywhere in the spec change it
+func SpecHash(manifestPath string) (string, error) {
+	src, err := LoadSpecSources(manifestPath)
+	if err != nil {
+		return "", err
+	}
+
+	paths := make([]string, 0, len(src.Content))
+	for path := range src.Content {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(h, "%s\x00%s\x00", src.RelPath(path), src.Content[path])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// AppendHistory adds an entry to the spec's .cca/history.jsonl
+func AppendHistory(manifestPath string, entry *HistoryEntry) error {
+	path := historyPath(manifestPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// LoadHistory reads every recorded run, in file order
+// Returns nil without error if nothing has been recorded yet
+func LoadHistory(manifestPath string) ([]HistoryEntry, error) {
+	f, err := os.Open(historyPath(manifestPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %w", historyPath(manifestPath), line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// BuildTrend keeps the latest run per commit for one mode, oldest commit first, limited to
+// the last maxCommits. Regressions compare the last two commits
+// An empty mode uses the mode of the most recent run
+func BuildTrend(entries []HistoryEntry, mode string, maxCommits int) *HistoryTrend {
+	if mode == "" && len(entries) > 0 {
+		mode = entries[len(entries)-1].Mode
+	}
+	trend := &HistoryTrend{Mode: mode}
+
+	latest := make(map[string]HistoryEntry)
+	var order []string
+	for _, entry := range entries {
+		if entry.Mode != mode {
+			continue
+		}
+		trend.Runs++
+		key := entry.Commit
+		if key == "" {
+			key = entry.SpecHash // Runs outside git are keyed by content
+		}
+		if _, ok := latest[key]; !ok {
+			order = append(order, key)
+		}
+		latest[key] = entry
+	}
+
+	for _, key := range order {
+		trend.Entries = append(trend.Entries, latest[key])
+	}
+	sort.SliceStable(trend.Entries, func(i, j int) bool {
+		return entryTime(trend.Entries[i]).Before(entryTime(trend.Entries[j]))
+	})
+	if maxCommits > 0 && len(trend.Entries) > maxCommits {
+		trend.Entries = trend.Entries[len(trend.Entries)-maxCommits:]
+	}
+
+	rules := make(map[string]bool)
+	for _, entry := range trend.Entries {
+		for rule := range entry.Rules {
+			rules[rule] = true
+		}
+	}
+	for rule := range rules {
+		trend.Rules = append(trend.Rules, rule)
+	}
+	sort.Strings(trend.Rules)
+
+	if n := len(trend.Entries); n >= 2 {
+		prev, cur := trend.Entries[n-2], trend.Entries[n-1]
+		for _, rule := range trend.Rules {
+			if cur.Rules[rule] > prev.Rules[rule] {
+				trend.Regressions = append(trend.Regressions, HistoryRegression{Rule: rule, Previous: prev.Rules[rule], Current: cur.Rules[rule]})
+			}
+		}
+	}
+	return trend
+}
+
+// entryTime orders entries by commit date, falling back to when they were recorded
+func entryTime(entry HistoryEntry) time.Time {
+	if !entry.CommitTime.IsZero() {
+		return entry.CommitTime
+	}
+	return entry.Timestamp
+}
+
+// shortCommit abbreviates a commit hash for column headers
+func shortCommit(entry HistoryEntry) string {
+	switch {
+	case entry.Commit == "":
+		return entry.SpecHash[:min(7, len(entry.SpecHash))]
+	case len(entry.Commit) > 7:
+		return entry.Commit[:7]
+	}
+	return entry.Commit
+}
+
+// FormatTrend renders the per-rule counts with one column per commit and the change since the previous one
+func FormatTrend(trend *HistoryTrend) string {
+	if len(trend.Entries) == 0 {
+		return "No validation history recorded - run 'cca validate --record' to start one.\n"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Validation history (%s runs): %d commit(s), %d run(s)\n\n", trend.Mode, len(trend.Entries), trend.Runs)
+
+	header := []string{"RULE"}
+	for _, entry := range trend.Entries {
+		header = append(header, shortCommit(entry))
+	}
+	header = append(header, "CHANGE")
+	rows := [][]string{header}
+
+	totals := make([]int, len(trend.Entries))
+	for _, rule := range trend.Rules {
+		row := []string{rule}
+		for i, entry := range trend.Entries {
+			row = append(row, fmt.Sprint(entry.Rules[rule]))
+			totals[i] += entry.Rules[rule]
+		}
+		row = append(row, formatChange(trend.Entries, func(e HistoryEntry) int { return e.Rules[rule] }))
+		rows = append(rows, row)
+	}
+
+	total := []string{"total"}
+	for _, n := range totals {
+		total = append(total, fmt.Sprint(n))
+	}
+	total = append(total, formatChange(trend.Entries, func(e HistoryEntry) int {
+		sum := 0
+		for _, n := range e.Rules {
+			sum += n
+		}
+		return sum
+	}))
+	rows = append(rows, total)
+	sb.WriteString(formatColumns(rows))
+
+	if len(trend.Entries) < 2 {
+		return sb.String()
+	}
+	prev := shortCommit(trend.Entries[len(trend.Entries)-2])
+	if len(trend.Regressions) == 0 {
+		fmt.Fprintf(&sb, "\n\033[32m✓\033[0m No regressions since %s\n", prev)
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "\n\033[31m✗\033[0m %d rule(s) regressed since %s:\n", len(trend.Regressions), prev)
+	for _, r := range trend.Regressions {
+		fmt.Fprintf(&sb, "  %s: %d -> %d\n", r.Rule, r.Previous, r.Current)
+	}
+	return sb.String()
+}
+
+// formatChange shows the difference between the last two entries, "" when there is none
+func formatChange(entries []HistoryEntry, count func(HistoryEntry) int) string {
+	if len(entries) < 2 {
+		return ""
+	}
+	diff := count(entries[len(entries)-1]) - count(entries[len(entries)-2])
+	switch {
+	case diff > 0:
+		return fmt.Sprintf("+%d regressed", diff)
+	case diff < 0:
+		return fmt.Sprintf("%d", diff)
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRuleCounts(t *testing.T) {
+	result := &ValidationResult{
+		StructuralChecks: []StructuralCheck{
+			{ID: "attributes", Passed: false, Issues: []AnalyzerIssue{{Rule: "undefined-attribute"}, {Rule: "undefined-attribute"}}},
+			{ID: "compile", Passed: false},
+			{ID: "sections", Passed: true},
+		},
+		Findings: []Finding{{Rule: "NO-WEAK-LANGUAGE"}, {Rule: "no-weak-language"}, {Rule: "dlq"}},
+	}
+	want := map[string]int{"undefined-attribute": 2, "compile": 1, "no-weak-language": 2, "dlq": 1}
+	if got := RuleCounts(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("RuleCounts = %v, want %v", got, want)
+	}
+}
+
+func TestBuildTrend(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	entries := []HistoryEntry{
+		// Recorded first but committed last
+		{Commit: "cccccccccc", CommitTime: day(3), Timestamp: day(10), Mode: HistoryQuick, Rules: map[string]int{"undefined-type": 3, "unused-attribute": 1}},
+		{Commit: "aaaaaaaaaa", CommitTime: day(1), Timestamp: day(11), Mode: HistoryQuick, Rules: map[string]int{"undefined-type": 1}},
+		{Commit: "bbbbbbbbbb", CommitTime: day(2), Timestamp: day(12), Mode: HistoryQuick, Rules: map[string]int{"undefined-type": 5}},
+		// Re-run of b supersedes the earlier entry
+		{Commit: "bbbbbbbbbb", CommitTime: day(2), Timestamp: day(13), Mode: HistoryQuick, Rules: map[string]int{"undefined-type": 2}},
+		{Commit: "cccccccccc", CommitTime: day(3), Timestamp: day(14), Mode: HistorySemantic, Rules: map[string]int{"dlq": 1}},
+		{Commit: "cccccccccc", CommitTime: day(3), Timestamp: day(15), Mode: HistoryQuick, Rules: map[string]int{"undefined-type": 3, "unused-attribute": 1}},
+	}
+
+	trend := BuildTrend(entries, "", 10)
+	if trend.Mode != HistoryQuick || trend.Runs != 5 {
+		t.Fatalf("mode %q runs %d, want quick and 5", trend.Mode, trend.Runs)
+	}
+	var commits []string
+	for _, e := range trend.Entries {
+		commits = append(commits, shortCommit(e))
+	}
+	if strings.Join(commits, " ") != "aaaaaaa bbbbbbb ccccccc" {
+		t.Errorf("commits = %v", commits)
+	}
+	want := []HistoryRegression{{Rule: "undefined-type", Previous: 2, Current: 3}, {Rule: "unused-attribute", Previous: 0, Current: 1}}
+	if !reflect.DeepEqual(trend.Regressions, want) {
+		t.Errorf("regressions = %+v, want %+v", trend.Regressions, want)
+	}
+
+	if got := BuildTrend(entries, HistoryQuick, 2); len(got.Entries) != 2 || got.Entries[0].Commit != "bbbbbbbbbb" {
+		t.Errorf("maxCommits 2 kept %+v", got.Entries)
+	}
+
+	out := FormatTrend(trend)
+	for _, line := range []string{
+		"undefined-type    1        2        3        +1 regressed",
+		"total             1        2        4        +2 regressed",
+		"2 rule(s) regressed since bbbbbbb:",
+		"unused-attribute: 0 -> 1",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("trend output missing %q:\n%s", line, out)
+		}
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	manifest := writeSpecFiles(t, map[string]string{"MANIFEST.adoc": "= Spec\n\ninclude::api.adoc[]\n", "api.adoc": "== API\n"})
+	dir := filepath.Dir(manifest)
+
+	if entries, err := LoadHistory(manifest); err != nil || entries != nil {
+		t.Fatalf("empty history: %v, %v", entries, err)
+	}
+
+	result := &ValidationResult{StructuralPassed: true, SemanticRun: true, Findings: []Finding{{Rule: "dlq"}}}
+	entry, err := NewHistoryEntry(manifest, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.Commit = "abc1234"
+	for range 2 {
+		if err := AppendHistory(manifest, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := LoadHistory(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Commit != "abc1234" || entries[1].Mode != HistorySemantic || entries[1].Rules["dlq"] != 1 {
+		t.Fatalf("loaded %+v", entries)
+	}
+
+	// Editing an included file changes the hash
+	if err := os.WriteFile(filepath.Join(dir, "api.adoc"), []byte("== API\n\nChanged.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := SpecHash(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == entry.SpecHash {
+		t.Error("spec hash unchanged after editing an included file")
+	}
+}
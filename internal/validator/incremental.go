@@ -0,0 +1,182 @@
+package validator
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/compiler"
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/differ"
+)
+
+// IncrementalSpec is the subset of a compiled spec sent for incremental validation
+type IncrementalSpec struct {
+	Content  string   // Markdown sent to Claude
+	Since    string   // Git ref the spec was diffed against
+	Changed  []string // Changed section titles
+	Included []string // Every section title sent (changed + context + xref targets)
+	Omitted  []string // Unchanged section titles left out of the prompt
+}
+
+// References returns the included sections that are only sent for reference
+func (s *IncrementalSpec) References() []string {
+	var refs []string
+	for _, title := range s.Included {
+		if !slices.Contains(s.Changed, title) {
+			refs = append(refs, title)
+		}
+	}
+	return refs
+}
+
+// Section titles treated as the spec's context section
+var contextTitles = []string{"context", "system context", "overview"}
+
+// PrepareIncremental diffs the spec against a git ref and builds the subset to validate
+func PrepareIncremental(manifestPath, compiledSpec, since string) (*IncrementalSpec, error) {
+	diff, err := differ.DiffCompiled(manifestPath, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff against %s: %w", since, err)
+	}
+
+	var changed []string
+	for _, change := range diff.SectionChanges {
+		if change.ChangeType != "removed" {
+			changed = append(changed, change.SectionTitle)
+		}
+	}
+
+	inc := BuildIncrementalSpec(compiledSpec, changed)
+	inc.Since = since
+	return inc, nil
+}
+
+// BuildIncrementalSpec selects the changed sections, the Context section and any
+// sections the changed ones link to, preserving document order
+func BuildIncrementalSpec(compiledSpec string, changed []string) *IncrementalSpec {
+	sections := compiler.SplitMarkdownSections(compiledSpec)
+	result := &IncrementalSpec{}
+
+	changedSet := make(map[string]bool)
+	for _, title := range changed {
+		changedSet[title] = true
+	}
+
+	include := make(map[int]bool)
+	var changedContent strings.Builder
+	for i, section := range sections {
+		if !changedSet[section.Title] {
+			continue
+		}
+		include[i] = true
+		result.Changed = append(result.Changed, section.Title)
+		changedContent.WriteString(section.Content)
+	}
+
+	if len(result.Changed) == 0 {
+		return result
+	}
+
+	for _, i := range compiler.LinkedSections(sections, changedContent.String()) {
+		include[i] = true
+	}
+	for _, i := range contextSectionIndexes(sections) {
+		include[i] = true
+	}
+
+	var sb strings.Builder
+	for i, section := range sections {
+		if include[i] {
+			sb.WriteString(section.Content)
+			result.Included = append(result.Included, section.Title)
+		} else {
+			result.Omitted = append(result.Omitted, section.Title)
+		}
+	}
+	result.Content = sb.String()
+
+	return result
+}
+
+// contextSectionIndexes returns the context section and all of its subsections
+func contextSectionIndexes(sections []compiler.MarkdownSection) []int {
+	var indexes []int
+	for i, section := range sections {
+		if !isContextTitle(section.Title) {
+			continue
+		}
+		indexes = append(indexes, i)
+		for j := i + 1; j < len(sections) && sections[j].Level > section.Level; j++ {
+			indexes = append(indexes, j)
+		}
+		return indexes
+	}
+	return nil
+}
+
+func isContextTitle(title string) bool {
+	title = strings.ToLower(strings.TrimSpace(title))
+	for _, candidate := range contextTitles {
+		if title == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// CarryOverFindings returns cached findings for sections that were not re-validated
+// Findings for sections that no longer exist are dropped. Findings without a section
+// are resolved again against the existing titles; those still without one may belong
+// to a re-validated section, so they are dropped whenever anything was re-validated.
+func CarryOverFindings(cached []Finding, revalidated, existing []string) []Finding {
+	skip := make(map[string]bool)
+	for _, title := range revalidated {
+		skip[title] = true
+	}
+	exists := make(map[string]bool)
+	for _, title := range existing {
+		exists[title] = true
+	}
+
+	var carried []Finding
+	for _, f := range cached {
+		if f.Section == "" {
+			resolved := []Finding{f}
+			AssignSections(resolved, existing)
+			f = resolved[0]
+		}
+		if f.Section == "" && len(revalidated) > 0 {
+			continue
+		}
+		if skip[f.Section] {
+			continue
+		}
+		if f.Section != "" && !exists[f.Section] {
+			continue
+		}
+		carried = append(carried, f)
+	}
+	return carried
+}
+
+// sectionTitles returns the titles of all sections in compiled Markdown
+func sectionTitles(compiledSpec string) []string {
+	var titles []string
+	for _, section := range compiler.SplitMarkdownSections(compiledSpec) {
+		titles = append(titles, section.Title)
+	}
+	return titles
+}
+
+// formatIncrementalHeader describes which sections an incremental run covers
+func formatIncrementalHeader(output io.Writer, inc *IncrementalSpec, since string) {
+	fmt.Fprintf(output, "Incremental validation since %s: %d changed section(s)\n", since, len(inc.Changed))
+	for _, title := range inc.Changed {
+		fmt.Fprintf(output, "  ~ %s\n", title)
+	}
+	if extra := len(inc.Included) - len(inc.Changed); extra > 0 {
+		fmt.Fprintf(output, "  (+%d context/referenced section(s) for reference)\n", extra)
+	}
+	fmt.Fprintln(output)
+}
@@ -0,0 +1,154 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+const incrementalSpec = `# Service Spec
+
+## Context
+
+### Stack
+
+Go 1.21
+
+## Core Types
+
+### User
+
+Fields listed here.
+
+## API
+
+### POST /users
+
+Returns a [User](#_user).
+
+` + "```bash\n# not a heading\n```" + `
+
+## Deployment
+
+Docker.
+`
+
+func TestBuildIncrementalSpec(t *testing.T) {
+	inc := BuildIncrementalSpec(incrementalSpec, []string{"POST /users"})
+
+	if len(inc.Changed) != 1 || inc.Changed[0] != "POST /users" {
+		t.Fatalf("expected POST /users as only changed section, got %v", inc.Changed)
+	}
+
+	expected := []string{"Context", "Stack", "User", "POST /users"}
+	if strings.Join(inc.Included, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected included %v, got %v", expected, inc.Included)
+	}
+
+	if strings.Contains(inc.Content, "Deployment") {
+		t.Error("unchanged, unreferenced section should not be sent")
+	}
+	if !strings.Contains(inc.Content, "# not a heading") {
+		t.Error("fenced code inside a changed section should be kept")
+	}
+}
+
+func TestRender_Incremental(t *testing.T) {
+	inc := BuildIncrementalSpec(incrementalSpec, []string{"POST /users"})
+	inc.Since = "main"
+	if strings.Join(inc.Omitted, "|") != "Service Spec|Core Types|API|Deployment" {
+		t.Errorf("omitted = %v", inc.Omitted)
+	}
+
+	opts := ValidationOptions{Incremental: inc}
+	prompt, err := opts.Prompts.Render("validate", opts.validateData(inc.Content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{
+		"You are reviewing only the sections changed since main: POST /users.",
+		"These sections are included for reference only: Context, Stack, User.",
+		"it is present but not shown, and contains these sections: Service Spec, Core Types, API, Deployment.",
+		"Do not report sections, types, routes, dependencies or examples as missing",
+	} {
+		if !strings.Contains(prompt, expect) {
+			t.Errorf("expected prompt to contain %q", expect)
+		}
+	}
+}
+
+func TestBuildIncrementalSpec_NoChanges(t *testing.T) {
+	inc := BuildIncrementalSpec(incrementalSpec, []string{"Removed Section"})
+
+	if len(inc.Changed) != 0 || inc.Content != "" {
+		t.Errorf("expected empty incremental spec, got %+v", inc)
+	}
+}
+
+func TestParseFindings(t *testing.T) {
+	output := `Found the following issues:
+
+### exact-versions
+
+- **Rule:** exact-versions
+- **Location:** Context > Stack
+- **Issue:** Redis has no version
+- **Suggestion:** Pin Redis 7.2
+
+- Rule: perf-quantified
+- Location: Performance
+- Issue: "fast" is not a number
+`
+
+	findings := ParseFindings(output)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+
+	if findings[0].Rule != "exact-versions" || findings[0].Location != "Context > Stack" || findings[0].Suggestion != "Pin Redis 7.2" {
+		t.Errorf("unexpected first finding: %+v", findings[0])
+	}
+	if findings[1].Rule != "perf-quantified" || findings[1].Suggestion != "" {
+		t.Errorf("unexpected second finding: %+v", findings[1])
+	}
+}
+
+func TestCarryOverFindings(t *testing.T) {
+	cached := []Finding{
+		{Rule: "a", Section: "Stack"},
+		{Rule: "b", Section: "POST /users"},
+		{Rule: "c", Section: "Gone"},
+		{Rule: "d", Location: "Tech Stack table"},
+		{Rule: "e", Location: "POST /users request body"},
+		{Rule: "f", Location: "somewhere"},
+	}
+
+	carried := CarryOverFindings(cached, []string{"POST /users"}, []string{"Stack", "POST /users"})
+
+	var rules []string
+	for _, f := range carried {
+		rules = append(rules, f.Rule)
+	}
+	if strings.Join(rules, ",") != "a,d" {
+		t.Errorf("expected findings a,d carried over, got %v", rules)
+	}
+
+	// With nothing re-validated, unresolved findings are kept
+	if carried := CarryOverFindings(cached[5:], nil, []string{"Stack"}); len(carried) != 1 {
+		t.Errorf("expected the unresolved finding kept, got %+v", carried)
+	}
+}
+
+func TestAssignSections(t *testing.T) {
+	findings := []Finding{
+		{Location: "Core Types > User, line 12"},
+		{Location: "somewhere else"},
+	}
+	AssignSections(findings, []string{"Core Types", "User", "Core Types > User"})
+
+	if findings[0].Section != "Core Types > User" {
+		t.Errorf("expected longest matching title, got %q", findings[0].Section)
+	}
+	if findings[1].Section != "" {
+		t.Errorf("expected no section, got %q", findings[1].Section)
+	}
+}
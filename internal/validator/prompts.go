@@ -0,0 +1,193 @@
+package validator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+//go:embed prompts/*.tmpl
+var promptTemplates embed.FS
+
+// TemplateData holds data passed to prompt templates
+type TemplateData struct {
+	CompiledSpec string
+	Runs         []string            // Ultra run outputs for synthesis
+	Preamble     string              // Shared context and attributes for chunked validation
+	Scope        *ChunkScope         // Set when validating one chunk of a larger spec
+	Slice        *SpecSlice          // Set when validating one section or file
+	Incremental  *IncrementalSpec    // Set when validating the sections changed since a git ref
+	Findings     string              // Formatted findings for the fix prompt
+	Sources      []SourceFile        // Numbered AsciiDoc sources for the fix prompt
+	Question     string              // Question for the ask prompt
+	Excerpts     []SpecExcerpt       // Retrieved sections for the ask prompt
+	Rules        []Rule              // Enabled checklist rules, filled in by PromptConfig.Render
+	OutlineRules []Rule              // Enabled cross-chunk rules, filled in by PromptConfig.Render
+	CustomRules  []config.CustomRule // Filled in by PromptConfig.Render
+}
+
+// RuleEnabled reports whether a checklist rule is in the prompt
+func (d TemplateData) RuleEnabled(id string) bool {
+	return slices.ContainsFunc(d.Rules, func(r Rule) bool { return r.ID == id })
+}
+
+// PromptConfig customizes prompt rendering from .spec.yaml
+type PromptConfig struct {
+	Overrides   map[string]string   // Template name -> file replacing the embedded template
+	CustomRules []config.CustomRule // Enabled project rules appended to the checklist
+	Disabled    map[string]bool     // Rules left out of the checklist
+}
+
+// Functions available to prompt templates; Render rebinds ruleNumber to the enabled checklist
+var promptFuncs = template.FuncMap{
+	"ruleNumber": func(i int) int { return len(checklistRules(nil)) + i + 1 },
+	"inc":        func(i int) int { return i + 1 },
+	"join":       strings.Join,
+}
+
+// LoadPromptTemplate loads and parses an embedded prompt template
+func LoadPromptTemplate(name string) (*template.Template, error) {
+	return PromptConfig{}.Load(name)
+}
+
+// RenderPrompt renders an embedded prompt template with data
+func RenderPrompt(templateName string, data TemplateData) (string, error) {
+	return PromptConfig{}.Render(templateName, data)
+}
+
+// Load parses a prompt template, preferring a configured override file
+func (c PromptConfig) Load(name string) (*template.Template, error) {
+	source, err := c.Source(name)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(name).Funcs(promptFuncs).Parse(source)
+}
+
+// Render renders a prompt template with data and the configured custom rules
+func (c PromptConfig) Render(name string, data TemplateData) (string, error) {
+	tmpl, err := c.Load(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to load template: %w", err)
+	}
+
+	data.Rules = checklistRules(c.Disabled)
+	data.OutlineRules = outlineRules(c.Disabled)
+	data.CustomRules = c.CustomRules
+	// Custom rules are numbered after the enabled built-in ones
+	tmpl.Funcs(template.FuncMap{"ruleNumber": func(i int) int { return len(data.Rules) + i + 1 }})
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// Source returns the raw text of a prompt template
+func (c PromptConfig) Source(name string) (string, error) {
+	if path, ok := c.Overrides[name]; ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s template override: %w", name, err)
+		}
+		return string(data), nil
+	}
+
+	data, err := promptTemplates.ReadFile("prompts/" + name + ".tmpl")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// PromptTemplateNames returns the names of all embedded prompt templates
+func PromptTemplateNames() []string {
+	entries, _ := fs.ReadDir(promptTemplates, "prompts")
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".tmpl"))
+	}
+	return names
+}
+
+// ExportPromptTemplates writes the embedded templates to dir as a starting point for overrides
+// Existing files are left untouched unless force is set
+func ExportPromptTemplates(dir string, force bool) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	var written []string
+	for _, name := range PromptTemplateNames() {
+		path := filepath.Join(dir, name+".tmpl")
+		if _, err := os.Stat(path); err == nil && !force {
+			return written, fmt.Errorf("%s already exists (use --force to overwrite)", path)
+		}
+
+		data, err := promptTemplates.ReadFile("prompts/" + name + ".tmpl")
+		if err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// NewPromptConfig builds the prompt configuration from .spec.yaml
+// Override paths are resolved relative to baseDir (the directory holding .spec.yaml)
+func NewPromptConfig(cfg *config.SpecConfig, baseDir string) (PromptConfig, error) {
+	seen := make(map[string]bool)
+	for _, rule := range cfg.Rules.Custom {
+		id := normalizeRuleID(rule.ID)
+		_, builtin := LookupRule(id)
+		switch {
+		case rule.ID == "" || rule.Description == "":
+			return PromptConfig{}, fmt.Errorf("custom rule %q needs both id and description", rule.ID)
+		case builtin:
+			return PromptConfig{}, fmt.Errorf("custom rule %q clashes with a built-in rule", rule.ID)
+		case seen[id]:
+			return PromptConfig{}, fmt.Errorf("custom rule %q is defined twice", rule.ID)
+		}
+		seen[id] = true
+	}
+
+	disabled, err := DisabledRules(cfg)
+	if err != nil {
+		return PromptConfig{}, err
+	}
+	prompts := PromptConfig{Disabled: disabled}
+	for _, rule := range cfg.Rules.Custom {
+		if !disabled[normalizeRuleID(rule.ID)] {
+			prompts.CustomRules = append(prompts.CustomRules, rule)
+		}
+	}
+	for name, path := range cfg.Prompts {
+		if _, err := promptTemplates.ReadFile("prompts/" + name + ".tmpl"); err != nil {
+			return PromptConfig{}, fmt.Errorf("unknown prompt template %q (available: %s)", name, strings.Join(PromptTemplateNames(), ", "))
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		if prompts.Overrides == nil {
+			prompts.Overrides = make(map[string]string)
+		}
+		prompts.Overrides[name] = path
+	}
+
+	return prompts, nil
+}
@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func TestRender_CustomRules(t *testing.T) {
+	prompts, err := NewPromptConfig(&config.SpecConfig{
+		Rules: config.RulesConfig{Custom: []config.CustomRule{
+			{ID: "queue-dlq", Description: "Every queue has a DLQ", Examples: []string{"orders -> orders-dlq"}},
+			{ID: "endpoint-authz", Description: "Every endpoint states an authz rule"},
+		}},
+	}, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	prompt, err := prompts.Render("validate", TemplateData{CompiledSpec: "# Spec"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expect := range []string{
+		"20. **queue-dlq**: Every queue has a DLQ",
+		"- Example: orders -> orders-dlq",
+		"21. **endpoint-authz**",
+	} {
+		if !strings.Contains(prompt, expect) {
+			t.Errorf("expected prompt to contain %q", expect)
+		}
+	}
+
+	plain, _ := RenderPrompt("validate", TemplateData{CompiledSpec: "# Spec"})
+	if strings.Contains(plain, "Project-Specific Rules") {
+		t.Error("project rules heading should only appear when custom rules exist")
+	}
+}
+
+func TestRender_Override(t *testing.T) {
+	dir := filepath.Dir(writeSpecFiles(t, map[string]string{
+		"validate.tmpl":   `{{define "validate"}}CUSTOM {{.CompiledSpec}}{{end}}`,
+		"synthesize.tmpl": `no define block: {{index .Runs 0}}`,
+	}))
+
+	prompts, err := NewPromptConfig(&config.SpecConfig{
+		Prompts: map[string]string{"validate": "validate.tmpl", "synthesize": "synthesize.tmpl"},
+	}, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	prompt, err := prompts.Render("validate", TemplateData{CompiledSpec: "spec"})
+	if err != nil || prompt != "CUSTOM spec" {
+		t.Errorf("expected override to render, got %q (%v)", prompt, err)
+	}
+
+	prompt, err = prompts.Render("synthesize", TemplateData{Runs: []string{"r1"}})
+	if err != nil || prompt != "no define block: r1" {
+		t.Errorf("expected plain override to render, got %q (%v)", prompt, err)
+	}
+}
+
+func TestNewPromptConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.SpecConfig
+	}{
+		{"missing description", config.SpecConfig{Rules: config.RulesConfig{Custom: []config.CustomRule{{ID: "x"}}}}},
+		{"clashes with built-in", config.SpecConfig{Rules: config.RulesConfig{Custom: []config.CustomRule{{ID: "exact-versions", Description: "d"}}}}},
+		{"duplicate", config.SpecConfig{Rules: config.RulesConfig{Custom: []config.CustomRule{{ID: "a", Description: "d"}, {ID: "a", Description: "d"}}}}},
+		{"unknown template", config.SpecConfig{Prompts: map[string]string{"nope": "x.tmpl"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPromptConfig(&tt.cfg, "."); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestExportPromptTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	written, err := ExportPromptTemplates(dir, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(written) != len(PromptTemplateNames()) {
+		t.Errorf("expected %d files, got %d", len(PromptTemplateNames()), len(written))
+	}
+
+	if _, err := ExportPromptTemplates(dir, false); err == nil {
+		t.Error("expected error when files exist without --force")
+	}
+	if _, err := ExportPromptTemplates(dir, true); err != nil {
+		t.Errorf("unexpected error with force: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+// Provider sends a rendered prompt to an LLM and returns its reply
+// Every Claude-facing feature goes through this interface
+type Provider interface {
+	Name() string     // Provider kind, e.g. "claude-cli"
+	Model() string    // Configured model ("" means provider default)
+	Available() error // Reports why the provider cannot be used, nil if ready
+	Complete(ctx context.Context, prompt string) (*Response, error)
+}
+
+// Response is a provider's reply to a single prompt
+type Response struct {
+	Text  string
+	Usage *Usage // Token usage reported by the provider, nil if unknown
+}
+
+// Usage is the token count of one provider call
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// Provider names accepted in .spec.yaml and --provider
+const (
+	ProviderClaudeCLI = "claude-cli"
+	ProviderAnthropic = "anthropic"
+	ProviderOpenAI    = "openai"
+)
+
+// Default token limit for HTTP providers that require one
+const defaultMaxTokens = 8192
+
+// NewProvider builds the provider described by cfg
+// Calls time out after cfg.Timeout (default 10m) and transient failures are retried.
+// With CCA_RECORD or CCA_REPLAY set, calls are recorded to or replayed from a cassette directory
+func NewProvider(cfg config.ProviderConfig) (Provider, error) {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultProviderTimeout
+	}
+	retries := DefaultRetries
+	if cfg.Retries != nil {
+		retries = *cfg.Retries
+	}
+
+	p, err := newBaseProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return withCassette(WithRetry(p, retries, cfg.RetryDelay)), nil
+}
+
+// newBaseProvider builds the unwrapped provider implementation
+func newBaseProvider(cfg config.ProviderConfig) (Provider, error) {
+	switch cfg.Name {
+	case "", ProviderClaudeCLI, "claude":
+		return &ClaudeCLIProvider{
+			Command: "claude",
+			model:   cfg.Model,
+			Args:    cfg.Args,
+			Timeout: cfg.Timeout,
+		}, nil
+
+	case ProviderAnthropic:
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("provider %s requires a model (set provider.model in .spec.yaml or use --model)", cfg.Name)
+		}
+		return &AnthropicProvider{
+			BaseURL:   withDefault(cfg.BaseURL, "https://api.anthropic.com"),
+			APIKeyEnv: withDefault(cfg.APIKeyEnv, "ANTHROPIC_API_KEY"),
+			model:     cfg.Model,
+			MaxTokens: maxTokensOrDefault(cfg.MaxTokens),
+			Timeout:   cfg.Timeout,
+		}, nil
+
+	case ProviderOpenAI:
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("provider %s requires a model (set provider.model in .spec.yaml or use --model)", cfg.Name)
+		}
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("provider %s requires base_url (e.g. http://localhost:8000/v1)", cfg.Name)
+		}
+		return &OpenAIProvider{
+			BaseURL:   cfg.BaseURL,
+			APIKeyEnv: withDefault(cfg.APIKeyEnv, "OPENAI_API_KEY"),
+			model:     cfg.Model,
+			MaxTokens: cfg.MaxTokens,
+			Timeout:   cfg.Timeout,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown provider: %s (supported: %s, %s, %s)", cfg.Name, ProviderClaudeCLI, ProviderAnthropic, ProviderOpenAI)
+	}
+}
+
+// DefaultProvider returns the claude CLI provider with no extra configuration
+func DefaultProvider() Provider {
+	p, _ := NewProvider(config.ProviderConfig{})
+	return p
+}
+
+// ProviderLabel formats a provider for display, e.g. "anthropic (claude-x)"
+func ProviderLabel(p Provider) string {
+	if p.Model() == "" {
+		return p.Name()
+	}
+	return fmt.Sprintf("%s (%s)", p.Name(), p.Model())
+}
+
+// withTimeout applies a per-call timeout to ctx when one is configured
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func withDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func maxTokensOrDefault(n int) int {
+	if n <= 0 {
+		return defaultMaxTokens
+	}
+	return n
+}
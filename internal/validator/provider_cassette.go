@@ -0,0 +1,174 @@
+package validator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Environment variables naming a cassette directory for provider calls
+// CCA_REPLAY takes precedence, so a replayed run never reaches a real provider
+const (
+	EnvRecord = "CCA_RECORD" // Save every reply, keyed by prompt hash
+	EnvReplay = "CCA_REPLAY" // Answer from saved replies, failing on unknown prompts
+)
+
+// Cassette is the recorded replies to one prompt, stored as <dir>/<prompt hash>.json
+type Cassette struct {
+	Provider string          `json:"provider"`
+	Model    string          `json:"model,omitempty"`
+	Prompt   string          `json:"prompt"`
+	Replies  []RecordedReply `json:"replies"`
+}
+
+// RecordedReply is the reply to one call of a prompt
+// Ultra runs send the same prompt once per run; each run's reply is kept under its call label
+type RecordedReply struct {
+	Call       string    `json:"call"` // Usage report label, e.g. "validate", "run 2", "synthesis"
+	Text       string    `json:"text"`
+	Usage      *Usage    `json:"usage,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// reply returns the reply recorded for a call label, falling back to the first one recorded
+func (c *Cassette) reply(call string) RecordedReply {
+	for _, r := range c.Replies {
+		if r.Call == call {
+			return r
+		}
+	}
+	return c.Replies[0]
+}
+
+// PromptHash is the cassette key of a prompt: the hex SHA-256 of its text
+func PromptHash(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// withCassette wraps p to record or replay when CCA_RECORD or CCA_REPLAY is set
+func withCassette(p Provider) Provider {
+	if dir := os.Getenv(EnvReplay); dir != "" {
+		return &ReplayProvider{Dir: dir, name: p.Name(), model: p.Model()}
+	}
+	if dir := os.Getenv(EnvRecord); dir != "" {
+		return &RecordingProvider{Provider: p, Dir: dir}
+	}
+	return p
+}
+
+// RecordingProvider forwards calls and saves each successful reply to a cassette directory
+// Recording a call label again replaces its reply
+type RecordingProvider struct {
+	Provider
+	Dir string
+
+	mu sync.Mutex
+}
+
+// Complete forwards to the wrapped provider and records the reply
+func (r *RecordingProvider) Complete(ctx context.Context, prompt string) (*Response, error) {
+	resp, err := r.Provider.Complete(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := filepath.Join(r.Dir, PromptHash(prompt)+".json")
+	cassette, err := loadCassette(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if cassette == nil {
+		cassette = &Cassette{Provider: r.Name(), Model: r.Model(), Prompt: prompt}
+	}
+	reply := RecordedReply{Call: callLabel(ctx), Text: resp.Text, Usage: resp.Usage, RecordedAt: time.Now().UTC()}
+	if i := slices.IndexFunc(cassette.Replies, func(c RecordedReply) bool { return c.Call == reply.Call }); i >= 0 {
+		cassette.Replies[i] = reply
+	} else {
+		cassette.Replies = append(cassette.Replies, reply)
+	}
+
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s directory: %w", EnvRecord, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to record reply: %w", err)
+	}
+	return resp, nil
+}
+
+// ReplayProvider answers from a cassette directory without calling any provider
+// It reports the configured provider's name and model, so cache keys and usage labels
+// match the recorded run
+type ReplayProvider struct {
+	Dir string
+
+	name, model string
+}
+
+// Name returns the configured provider kind
+func (r *ReplayProvider) Name() string { return r.name }
+
+// Model returns the configured model
+func (r *ReplayProvider) Model() string { return r.model }
+
+// Available reports a missing cassette directory; no provider binary or key is needed
+func (r *ReplayProvider) Available() error {
+	if info, err := os.Stat(r.Dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s directory %s not found", EnvReplay, r.Dir)
+	}
+	return nil
+}
+
+// Complete returns the recorded reply for prompt, failing if it was never recorded
+func (r *ReplayProvider) Complete(ctx context.Context, prompt string) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	hash := PromptHash(prompt)
+	cassette, err := loadCassette(filepath.Join(r.Dir, hash+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no recorded reply for prompt %s in %s (re-record with %s=%s)", hash, r.Dir, EnvRecord, r.Dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reply := cassette.reply(callLabel(ctx))
+	if w := streamOf(ctx); w != nil {
+		fmt.Fprint(w, reply.Text)
+	}
+	return &Response{Text: reply.Text, Usage: reply.Usage}, nil
+}
+
+// loadCassette reads a recorded prompt; a missing file is returned as an os.IsNotExist error
+func loadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("invalid recording %s: %w", filepath.Base(path), err)
+	}
+	if len(cassette.Replies) == 0 {
+		return nil, fmt.Errorf("recording %s has no replies", filepath.Base(path))
+	}
+	return &cassette, nil
+}
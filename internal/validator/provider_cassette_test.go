@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	recorder := &RecordingProvider{Provider: NewScriptedProvider("first", "second"), Dir: dir}
+	if _, err := recorder.Complete(context.Background(), "prompt one"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.Complete(context.Background(), "prompt two"); err != nil {
+		t.Fatal(err)
+	}
+
+	replay := &ReplayProvider{Dir: dir, name: "scripted"}
+	if err := replay.Available(); err != nil {
+		t.Fatal(err)
+	}
+	for prompt, want := range map[string]string{"prompt two": "second", "prompt one": "first"} {
+		resp, err := replay.Complete(context.Background(), prompt)
+		if err != nil || resp.Text != want {
+			t.Errorf("replay %q = %v, %v; want %q", prompt, resp, err, want)
+		}
+	}
+
+	_, err := replay.Complete(context.Background(), "never recorded")
+	if err == nil || !strings.Contains(err.Error(), PromptHash("never recorded")) || !strings.Contains(err.Error(), "CCA_RECORD="+dir) {
+		t.Errorf("err = %v, want unknown prompt error", err)
+	}
+	if err := (&ReplayProvider{Dir: dir + "/missing"}).Available(); err == nil {
+		t.Error("expected a missing replay directory to be unavailable")
+	}
+}
+
+func TestCassette_FromEnvironment(t *testing.T) {
+	cfg := config.ProviderConfig{Name: "anthropic", Model: "m"}
+
+	t.Setenv(EnvRecord, t.TempDir())
+	p, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.(*RecordingProvider); !ok || p.Name() != ProviderAnthropic {
+		t.Errorf("%s: got %T (%s)", EnvRecord, p, p.Name())
+	}
+
+	t.Setenv(EnvReplay, t.TempDir())
+	p, err = NewProvider(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.(*ReplayProvider); !ok || p.Name() != ProviderAnthropic || p.Model() != "m" {
+		t.Errorf("%s should take precedence and keep the configured provider, got %T (%s/%s)", EnvReplay, p, p.Name(), p.Model())
+	}
+}
+
+func TestCassette_ReplaysUltraSynthesis(t *testing.T) {
+	dir := t.TempDir()
+	reports := []string{
+		"- Rule: exact-versions\n- Location: Dependencies\n- Issue: No version for redis\n",
+		"- Rule: exact-versions\n- Location: Dependencies\n- Issue: No version for redis\n\n- Rule: no-weak-language\n- Location: Caching\n- Issue: Says should\n",
+		"Specification passes all checks.",
+	}
+	opts := ValidationOptions{Synthesize: true}
+
+	var recorded bytes.Buffer
+	opts.Provider = &RecordingProvider{Provider: NewScriptedProvider(append(reports, "Synthesized: pin redis")...), Dir: dir}
+	if _, err := RunUltraValidation(context.Background(), "= Spec\n", &recorded, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every run sends the same prompt; replay must give each run its own reply,
+	// or the synthesis prompt would differ from the one recorded
+	for i := 0; i < 2; i++ {
+		var replayed bytes.Buffer
+		opts.Provider = &ReplayProvider{Dir: dir, name: "scripted"}
+		result, err := RunUltraValidation(context.Background(), "= Spec\n", &replayed, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.SynthesisFailed {
+			t.Fatalf("synthesis was not replayed:\n%s", replayed.String())
+		}
+		if replayed.String() != recorded.String() {
+			t.Errorf("replay differs from recording:\n%s\n---\n%s", replayed.String(), recorded.String())
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ClaudeCLIProvider runs prompts through the claude CLI in non-interactive mode
+type ClaudeCLIProvider struct {
+	Command string        // Executable name or path
+	Args    []string      // Extra arguments appended to the command line
+	Timeout time.Duration // Per-call timeout, 0 for none
+	model   string
+}
+
+// Name returns the provider kind
+func (p *ClaudeCLIProvider) Name() string { return ProviderClaudeCLI }
+
+// Model returns the --model passed to the CLI ("" uses the CLI default)
+func (p *ClaudeCLIProvider) Model() string { return p.model }
+
+// Available checks that the CLI is installed
+func (p *ClaudeCLIProvider) Available() error {
+	if _, err := exec.LookPath(p.Command); err != nil {
+		return fmt.Errorf("claude CLI not found in PATH - install from https://claude.ai/code")
+	}
+	return nil
+}
+
+// Complete sends the prompt via stdin (avoids command line length limits)
+// A streaming caller gets the reply as it is generated, via the CLI's stream-json output
+func (p *ClaudeCLIProvider) Complete(ctx context.Context, prompt string) (*Response, error) {
+	ctx, cancel := withTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	stream := streamOf(ctx)
+
+	// Using --print for non-interactive mode
+	args := []string{"--print", "--no-session-persistence"}
+	if stream != nil {
+		args = append(args, "--output-format", "stream-json", "--verbose", "--include-partial-messages")
+	}
+	if p.model != "" {
+		args = append(args, "--model", p.model)
+	}
+	args = append(args, p.Args...)
+
+	cmd := exec.CommandContext(ctx, p.Command, args...)
+	cmd.Stdin = strings.NewReader(prompt)
+	var stderr bytes.Buffer
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+
+	var stdout bytes.Buffer
+	var events *claudeStream
+	if stream != nil {
+		events = &claudeStream{out: stream}
+		cmd.Stdout = events
+	} else {
+		cmd.Stdout = &stdout
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, claudeCLIError(err, stderr.String())
+	}
+
+	if events != nil {
+		return events.response()
+	}
+	return &Response{Text: stdout.String()}, nil
+}
+
+// claudeTransientPattern matches CLI error output worth retrying: overload, rate limits and timeouts
+var claudeTransientPattern = regexp.MustCompile(`(?i)overloaded|rate[ _-]?limit|too many requests|timed out|timeout|\b(429|502|503|504|529)\b`)
+
+// claudeCLIError wraps a failed CLI run; it is transient only when the process was killed
+// by a signal or its error output points to overload, rate limiting or a timeout
+func claudeCLIError(err error, stderr string) error {
+	err = fmt.Errorf("claude CLI failed: %w", err)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == -1 {
+		return &TransientError{Err: err}
+	}
+	if claudeTransientPattern.MatchString(stderr) {
+		return &TransientError{Err: err}
+	}
+	return err
+}
+
+// claudeStreamEvent is one line of the CLI's stream-json output
+// Only the fields used for streaming text and reading the final result are decoded.
+type claudeStreamEvent struct {
+	Type  string `json:"type"`
+	Event struct {
+		Type  string `json:"type"`
+		Delta struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"delta"`
+	} `json:"event"`
+	Message struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"message"`
+	IsError bool    `json:"is_error"`
+	Result  *string `json:"result"`
+	Usage   *struct {
+		InputTokens              int `json:"input_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// claudeStream decodes stream-json lines written by the CLI, forwarding text deltas to out
+type claudeStream struct {
+	out     io.Writer
+	pending []byte
+	text    strings.Builder
+	deltas  bool // Text of the current message already arrived as deltas
+	result  *claudeStreamEvent
+}
+
+// Write handles every complete line; a partial line waits for the rest
+func (s *claudeStream) Write(p []byte) (int, error) {
+	s.pending = append(s.pending, p...)
+	for {
+		i := bytes.IndexByte(s.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		s.handle(s.pending[:i])
+		s.pending = s.pending[i+1:]
+	}
+}
+
+// handle forwards the text of one event; lines that are not JSON events are ignored
+func (s *claudeStream) handle(line []byte) {
+	var event claudeStreamEvent
+	if json.Unmarshal(line, &event) != nil {
+		return
+	}
+
+	switch event.Type {
+	case "stream_event":
+		if event.Event.Type == "content_block_delta" && event.Event.Delta.Type == "text_delta" {
+			s.write(event.Event.Delta.Text)
+			s.deltas = true
+		}
+	case "assistant":
+		// CLI versions without partial messages only send whole messages
+		if !s.deltas {
+			for _, block := range event.Message.Content {
+				if block.Type == "text" {
+					s.write(block.Text)
+				}
+			}
+		}
+		s.deltas = false
+	case "result":
+		s.result = &event
+	}
+}
+
+func (s *claudeStream) write(text string) {
+	s.text.WriteString(text)
+	fmt.Fprint(s.out, text)
+}
+
+// response returns the final result, falling back to the streamed text
+func (s *claudeStream) response() (*Response, error) {
+	if len(bytes.TrimSpace(s.pending)) > 0 {
+		s.handle(s.pending)
+		s.pending = nil
+	}
+	if s.result == nil {
+		return &Response{Text: s.text.String()}, nil
+	}
+
+	resp := &Response{Text: s.text.String()}
+	if s.result.Result != nil {
+		resp.Text = *s.result.Result
+	}
+	if s.result.IsError {
+		err := fmt.Errorf("claude CLI failed: %s", truncateBody([]byte(resp.Text)))
+		if claudeTransientPattern.MatchString(resp.Text) {
+			return nil, &TransientError{Err: err}
+		}
+		return nil, err
+	}
+	if u := s.result.Usage; u != nil {
+		resp.Usage = &Usage{InputTokens: u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens, OutputTokens: u.OutputTokens}
+	}
+	return resp, nil
+}
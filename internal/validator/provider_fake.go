@@ -0,0 +1,72 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ScriptedReply is one canned provider reply
+type ScriptedReply struct {
+	Text string
+	Err  error
+}
+
+// ScriptedProvider replays canned replies in order and records every prompt (for tests)
+type ScriptedProvider struct {
+	Replies []ScriptedReply
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+// NewScriptedProvider returns a provider that answers with texts in order
+func NewScriptedProvider(texts ...string) *ScriptedProvider {
+	p := &ScriptedProvider{}
+	for _, text := range texts {
+		p.Replies = append(p.Replies, ScriptedReply{Text: text})
+	}
+	return p
+}
+
+// Name returns the provider kind
+func (p *ScriptedProvider) Name() string { return "scripted" }
+
+// Model returns an empty model
+func (p *ScriptedProvider) Model() string { return "" }
+
+// Available always succeeds
+func (p *ScriptedProvider) Available() error { return nil }
+
+// Complete returns the next scripted reply, failing once the script is exhausted
+// A streaming caller receives the reply in one write
+func (p *ScriptedProvider) Complete(ctx context.Context, prompt string) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	call := len(p.prompts)
+	p.prompts = append(p.prompts, prompt)
+	if call >= len(p.Replies) {
+		return nil, fmt.Errorf("scripted provider: no reply for call %d", call+1)
+	}
+
+	reply := p.Replies[call]
+	if reply.Err != nil {
+		return nil, reply.Err
+	}
+	if w := streamOf(ctx); w != nil {
+		fmt.Fprint(w, reply.Text)
+	}
+	return &Response{Text: reply.Text}, nil
+}
+
+// Prompts returns every prompt received so far
+func (p *ScriptedProvider) Prompts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.prompts...)
+}
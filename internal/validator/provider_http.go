@@ -0,0 +1,376 @@
+package validator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// AnthropicProvider calls the Anthropic Messages API directly
+type AnthropicProvider struct {
+	BaseURL   string
+	APIKeyEnv string
+	MaxTokens int
+	Timeout   time.Duration
+	Client    *http.Client // nil uses http.DefaultClient
+	model     string
+}
+
+// Name returns the provider kind
+func (p *AnthropicProvider) Name() string { return ProviderAnthropic }
+
+// Model returns the configured model
+func (p *AnthropicProvider) Model() string { return p.model }
+
+// Available checks that an API key is configured
+func (p *AnthropicProvider) Available() error {
+	if os.Getenv(p.APIKeyEnv) == "" {
+		return fmt.Errorf("%s is not set - required for the %s provider", p.APIKeyEnv, ProviderAnthropic)
+	}
+	return nil
+}
+
+type anthropicRequest struct {
+	Model     string        `json:"model"`
+	MaxTokens int           `json:"max_tokens"`
+	Messages  []chatMessage `json:"messages"`
+	Stream    bool          `json:"stream,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage *Usage `json:"usage"`
+}
+
+// anthropicEvent is one server-sent event of a streamed message
+type anthropicEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage *Usage `json:"usage"`
+	} `json:"message"` // message_start
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"` // content_block_delta
+	Usage *Usage `json:"usage"` // message_delta
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Complete sends the prompt as a single user message
+func (p *AnthropicProvider) Complete(ctx context.Context, prompt string) (*Response, error) {
+	ctx, cancel := withTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	body := anthropicRequest{
+		Model:     p.model,
+		MaxTokens: p.MaxTokens,
+		Messages:  []chatMessage{{Role: "user", Content: prompt}},
+	}
+	headers := map[string]string{
+		"x-api-key":         os.Getenv(p.APIKeyEnv),
+		"anthropic-version": "2023-06-01",
+	}
+
+	url := strings.TrimRight(p.BaseURL, "/") + "/v1/messages"
+
+	if stream := streamOf(ctx); stream != nil {
+		body.Stream = true
+		resp, err := p.stream(ctx, url, headers, body, stream)
+		if err != nil {
+			return nil, fmt.Errorf("%s request failed: %w", ProviderAnthropic, err)
+		}
+		return resp, nil
+	}
+
+	var resp anthropicResponse
+	if err := postJSON(ctx, p.Client, url, headers, body, &resp); err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", ProviderAnthropic, err)
+	}
+
+	var sb strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			sb.WriteString(block.Text)
+		}
+	}
+	return &Response{Text: sb.String(), Usage: resp.Usage}, nil
+}
+
+// stream reads a streamed message, writing text deltas to w as they arrive
+func (p *AnthropicProvider) stream(ctx context.Context, url string, headers map[string]string, body anthropicRequest, w io.Writer) (*Response, error) {
+	httpResp, err := post(ctx, p.Client, url, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	var sb strings.Builder
+	usage := &Usage{}
+	err = readServerEvents(httpResp.Body, func(data []byte) error {
+		var event anthropicEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("invalid event: %w", err)
+		}
+		switch event.Type {
+		case "message_start":
+			if event.Message.Usage != nil {
+				usage.InputTokens = event.Message.Usage.InputTokens
+			}
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" {
+				sb.WriteString(event.Delta.Text)
+				fmt.Fprint(w, event.Delta.Text)
+			}
+		case "message_delta":
+			if event.Usage != nil {
+				usage.OutputTokens = event.Usage.OutputTokens
+			}
+		case "error":
+			// Errors after the 200 status arrive as events, e.g. overloaded_error
+			if event.Error == nil {
+				return fmt.Errorf("error event: %s", truncateBody(data))
+			}
+			err := fmt.Errorf("%s: %s", event.Error.Type, event.Error.Message)
+			if event.Error.Type == "overloaded_error" || event.Error.Type == "api_error" {
+				return &TransientError{Err: err}
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Text: sb.String(), Usage: usage}, nil
+}
+
+// OpenAIProvider calls an OpenAI-compatible chat completions endpoint
+// (vLLM, llama.cpp server, Ollama and similar self-hosted servers)
+type OpenAIProvider struct {
+	BaseURL   string
+	APIKeyEnv string
+	MaxTokens int // 0 leaves the limit to the server
+	Timeout   time.Duration
+	Client    *http.Client // nil uses http.DefaultClient
+	model     string
+}
+
+// Name returns the provider kind
+func (p *OpenAIProvider) Name() string { return ProviderOpenAI }
+
+// Model returns the configured model
+func (p *OpenAIProvider) Model() string { return p.model }
+
+// Available always succeeds: self-hosted servers commonly run without an API key
+func (p *OpenAIProvider) Available() error { return nil }
+
+type openAIRequest struct {
+	Model     string        `json:"model"`
+	Messages  []chatMessage `json:"messages"`
+	MaxTokens int           `json:"max_tokens,omitempty"`
+	Stream    bool          `json:"stream,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+		Delta   chatMessage `json:"delta"` // Streamed chunks
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// usage converts the reported token counts, nil if the server sent none
+func (r *openAIResponse) usage() *Usage {
+	if r.Usage == nil {
+		return nil
+	}
+	return &Usage{InputTokens: r.Usage.PromptTokens, OutputTokens: r.Usage.CompletionTokens}
+}
+
+// Complete sends the prompt as a single user message
+func (p *OpenAIProvider) Complete(ctx context.Context, prompt string) (*Response, error) {
+	ctx, cancel := withTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	body := openAIRequest{
+		Model:     p.model,
+		Messages:  []chatMessage{{Role: "user", Content: prompt}},
+		MaxTokens: p.MaxTokens,
+	}
+	headers := map[string]string{}
+	if key := os.Getenv(p.APIKeyEnv); key != "" {
+		headers["Authorization"] = "Bearer " + key
+	}
+
+	url := strings.TrimRight(p.BaseURL, "/") + "/chat/completions"
+
+	if stream := streamOf(ctx); stream != nil {
+		body.Stream = true
+		resp, err := p.stream(ctx, url, headers, body, stream)
+		if err != nil {
+			return nil, fmt.Errorf("%s request failed: %w", ProviderOpenAI, err)
+		}
+		return resp, nil
+	}
+
+	var resp openAIResponse
+	if err := postJSON(ctx, p.Client, url, headers, body, &resp); err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", ProviderOpenAI, err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("%s returned no choices", ProviderOpenAI)
+	}
+	return &Response{Text: resp.Choices[0].Message.Content, Usage: resp.usage()}, nil
+}
+
+// stream reads streamed chunks, writing content deltas to w as they arrive
+// Usage is only known if the server reports it in a chunk
+func (p *OpenAIProvider) stream(ctx context.Context, url string, headers map[string]string, body openAIRequest, w io.Writer) (*Response, error) {
+	httpResp, err := post(ctx, p.Client, url, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	result := &Response{}
+	var sb strings.Builder
+	err = readServerEvents(httpResp.Body, func(data []byte) error {
+		var chunk openAIResponse
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return fmt.Errorf("invalid chunk: %w", err)
+		}
+		for _, choice := range chunk.Choices {
+			sb.WriteString(choice.Delta.Content)
+			fmt.Fprint(w, choice.Delta.Content)
+		}
+		if usage := chunk.usage(); usage != nil {
+			result.Usage = usage
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Text = sb.String()
+	return result, nil
+}
+
+// chatMessage is the message shape shared by both HTTP APIs
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// postJSON posts body as JSON and decodes a successful response into out
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out any) error {
+	resp, err := post(ctx, client, url, headers, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &TransientError{Err: err}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// post posts body as JSON and returns the response if its status is 200
+// The caller closes the body.
+func post(ctx context.Context, client *http.Client, url string, headers map[string]string, body any) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &TransientError{Err: err}
+	}
+	err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncateBody(data))
+	if isTransientStatus(resp.StatusCode) {
+		return nil, &TransientError{Err: err}
+	}
+	return nil, err
+}
+
+// readServerEvents calls fn with the data of each server-sent event until the stream ends
+// or sends [DONE]; a connection dropped mid-stream is transient
+func readServerEvents(body io.Reader, fn func(data []byte) error) error {
+	reader := bufio.NewReader(body)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if data, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("data:")); ok {
+			data = bytes.TrimSpace(data)
+			if string(data) == "[DONE]" {
+				return nil
+			}
+			if err := fn(data); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return &TransientError{Err: err}
+		}
+	}
+}
+
+// isTransientStatus reports rate limiting, overload and server errors
+func isTransientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// truncateBody shortens an error body for display
+func truncateBody(data []byte) string {
+	const maxLen = 300
+	s := strings.TrimSpace(string(data))
+	if len(s) > maxLen {
+		return s[:maxLen] + "..."
+	}
+	return s
+}
@@ -0,0 +1,310 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.ProviderConfig
+		expectErr bool
+		expect    string
+	}{
+		{name: "default is claude cli", cfg: config.ProviderConfig{}, expect: ProviderClaudeCLI},
+		{name: "claude alias", cfg: config.ProviderConfig{Name: "claude"}, expect: ProviderClaudeCLI},
+		{name: "anthropic", cfg: config.ProviderConfig{Name: "anthropic", Model: "m"}, expect: ProviderAnthropic},
+		{name: "anthropic without model", cfg: config.ProviderConfig{Name: "anthropic"}, expectErr: true},
+		{name: "openai", cfg: config.ProviderConfig{Name: "openai", Model: "m", BaseURL: "http://x"}, expect: ProviderOpenAI},
+		{name: "openai without base url", cfg: config.ProviderConfig{Name: "openai", Model: "m"}, expectErr: true},
+		{name: "unknown", cfg: config.ProviderConfig{Name: "gpt"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewProvider(tt.cfg)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got provider %s", p.Name())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Name() != tt.expect {
+				t.Errorf("expected %s, got %s", tt.expect, p.Name())
+			}
+		})
+	}
+}
+
+func TestAnthropicProvider_Complete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "secret" {
+			t.Errorf("expected api key header, got %q", r.Header.Get("x-api-key"))
+		}
+
+		var req anthropicRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "test-model" || req.Messages[0].Content != "prompt" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		w.Write([]byte(`{"content":[{"type":"text","text":"Specification "},{"type":"text","text":"passes all checks."}],"usage":{"input_tokens":120,"output_tokens":7}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_ANTHROPIC_KEY", "secret")
+	p, _ := NewProvider(config.ProviderConfig{
+		Name:      "anthropic",
+		Model:     "test-model",
+		BaseURL:   server.URL,
+		APIKeyEnv: "TEST_ANTHROPIC_KEY",
+	})
+
+	resp, err := p.Complete(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "Specification passes all checks." {
+		t.Errorf("unexpected text: %q", resp.Text)
+	}
+	if resp.Usage == nil || resp.Usage.InputTokens != 120 || resp.Usage.OutputTokens != 7 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestOpenAIProvider_Complete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	p, _ := NewProvider(config.ProviderConfig{Name: "openai", Model: "local", BaseURL: server.URL + "/v1/"})
+
+	resp, err := p.Complete(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "ok" {
+		t.Errorf("unexpected text: %q", resp.Text)
+	}
+}
+
+func TestOpenAIProvider_HTTPError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	retries := 1
+	p, _ := NewProvider(config.ProviderConfig{
+		Name:       "openai",
+		Model:      "local",
+		BaseURL:    server.URL,
+		Retries:    &retries,
+		RetryDelay: time.Millisecond,
+	})
+
+	_, err := p.Complete(context.Background(), "prompt")
+	if err == nil {
+		t.Fatal("expected error for HTTP 503")
+	}
+	if !IsTransient(err) {
+		t.Errorf("HTTP 503 should be transient: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2 (one retry)", calls.Load())
+	}
+}
+
+func TestScriptedProvider(t *testing.T) {
+	p := NewScriptedProvider("first", "second")
+
+	for _, expect := range []string{"first", "second"} {
+		resp, err := p.Complete(context.Background(), "q")
+		if err != nil || resp.Text != expect {
+			t.Errorf("expected %q, got %v / %v", expect, resp, err)
+		}
+	}
+
+	if _, err := p.Complete(context.Background(), "q"); err == nil {
+		t.Error("expected error once script is exhausted")
+	}
+	if len(p.Prompts()) != 3 {
+		t.Errorf("expected 3 recorded prompts, got %d", len(p.Prompts()))
+	}
+}
+
+func TestAnthropicProvider_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Error("expected a streaming request")
+		}
+		w.Write([]byte("event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"usage\":{\"input_tokens\":120,\"output_tokens\":1}}}\n\n" +
+			"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Specification \"}}\n\n" +
+			"event: ping\ndata: {\"type\":\"ping\"}\n\n" +
+			"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"passes all checks.\"}}\n\n" +
+			"event: message_delta\ndata: {\"type\":\"message_delta\",\"usage\":{\"output_tokens\":7}}\n\n"))
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_ANTHROPIC_KEY", "secret")
+	p, _ := NewProvider(config.ProviderConfig{Name: "anthropic", Model: "m", BaseURL: server.URL, APIKeyEnv: "TEST_ANTHROPIC_KEY"})
+
+	var streamed bytes.Buffer
+	resp, err := p.Complete(withStream(context.Background(), &streamed), "prompt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "Specification passes all checks." || streamed.String() != resp.Text {
+		t.Errorf("text = %q, streamed %q", resp.Text, streamed.String())
+	}
+	if resp.Usage == nil || resp.Usage.InputTokens != 120 || resp.Usage.OutputTokens != 7 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestAnthropicProvider_StreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n"))
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_ANTHROPIC_KEY", "secret")
+	retries := 0
+	p, _ := NewProvider(config.ProviderConfig{Name: "anthropic", Model: "m", BaseURL: server.URL, APIKeyEnv: "TEST_ANTHROPIC_KEY", Retries: &retries})
+
+	_, err := p.Complete(withStream(context.Background(), io.Discard), "prompt")
+	if err == nil || !IsTransient(err) {
+		t.Errorf("err = %v, want a transient overload error", err)
+	}
+}
+
+func TestAnthropicProvider_StreamErrorWithoutDetail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("event: error\ndata: {\"type\":\"error\",\"detail\":\"upstream reset\"}\n\n"))
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_ANTHROPIC_KEY", "secret")
+	retries := 0
+	p, _ := NewProvider(config.ProviderConfig{Name: "anthropic", Model: "m", BaseURL: server.URL, APIKeyEnv: "TEST_ANTHROPIC_KEY", Retries: &retries})
+
+	_, err := p.Complete(withStream(context.Background(), io.Discard), "prompt")
+	if err == nil || !strings.Contains(err.Error(), "upstream reset") {
+		t.Errorf("err = %v, want the raw event in the message", err)
+	}
+}
+
+func TestOpenAIProvider_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"role\":\"assistant\",\"content\":\"o\"}}]}\n\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"k\"}}]}\n\n" +
+			"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":9,\"completion_tokens\":2}}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	p, _ := NewProvider(config.ProviderConfig{Name: "openai", Model: "local", BaseURL: server.URL})
+
+	var streamed bytes.Buffer
+	resp, err := p.Complete(withStream(context.Background(), &streamed), "prompt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "ok" || streamed.String() != "ok" {
+		t.Errorf("text = %q, streamed %q", resp.Text, streamed.String())
+	}
+	if resp.Usage == nil || resp.Usage.InputTokens != 9 || resp.Usage.OutputTokens != 2 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestClaudeStream(t *testing.T) {
+	var streamed bytes.Buffer
+	s := &claudeStream{out: &streamed}
+	lines := `{"type":"system","subtype":"init"}
+{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"Specification "}}}
+{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"passes all checks."}}}
+{"type":"assistant","message":{"content":[{"type":"text","text":"Specification passes all checks."}]}}
+{"type":"result","is_error":false,"result":"Specification passes all checks.","usage":{"input_tokens":3,"cache_read_input_tokens":100,"output_tokens":7}}
+`
+	// The CLI's output arrives in arbitrary pieces
+	for len(lines) > 0 {
+		n := min(17, len(lines))
+		s.Write([]byte(lines[:n]))
+		lines = lines[n:]
+	}
+
+	resp, err := s.response()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streamed.String() != "Specification passes all checks." || resp.Text != streamed.String() {
+		t.Errorf("streamed %q, text %q", streamed.String(), resp.Text)
+	}
+	if resp.Usage == nil || resp.Usage.InputTokens != 103 || resp.Usage.OutputTokens != 7 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+
+	// Without partial messages the whole assistant message is streamed once
+	streamed.Reset()
+	s = &claudeStream{out: &streamed}
+	s.Write([]byte(`{"type":"assistant","message":{"content":[{"type":"text","text":"ok"}]}}` + "\n" + `{"type":"result","is_error":true,"result":"overloaded"}`))
+	if _, err := s.response(); err == nil || !IsTransient(err) || streamed.String() != "ok" {
+		t.Errorf("err = %v, streamed %q", err, streamed.String())
+	}
+
+	// Other error results are not retried
+	s = &claudeStream{out: io.Discard}
+	s.Write([]byte(`{"type":"result","is_error":true,"result":"Invalid API key"}` + "\n"))
+	if _, err := s.response(); err == nil || IsTransient(err) {
+		t.Errorf("err = %v, want a permanent error", err)
+	}
+}
+
+func TestClaudeCLIError(t *testing.T) {
+	failed := errors.New("exit status 1")
+	if err := claudeCLIError(failed, "API Error: 529 Overloaded"); !IsTransient(err) {
+		t.Errorf("overload should be retried: %v", err)
+	}
+	if err := claudeCLIError(failed, "Request timed out"); !IsTransient(err) {
+		t.Errorf("timeout should be retried: %v", err)
+	}
+	if err := claudeCLIError(failed, "error: unknown option '--bogus'"); IsTransient(err) {
+		t.Errorf("usage errors should not be retried: %v", err)
+	}
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	killed := exec.Command("sh", "-c", "kill -KILL $$").Run()
+	if err := claudeCLIError(killed, ""); !IsTransient(err) {
+		t.Errorf("a killed CLI should be retried: %v", err)
+	}
+}
@@ -0,0 +1,276 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Quantification analyzer IDs, shared with the semantic rules they approximate
+const (
+	rulePerfQuantified    = "perf-quantified"
+	ruleNumericDerivation = "numeric-derivation"
+)
+
+var (
+	perfQuantifiedAnalyzer = Analyzer{
+		ID:   rulePerfQuantified,
+		Name: "Performance claims quantified",
+		Run:  analyzePerfQuantified,
+	}
+	numericDerivationAnalyzer = Analyzer{
+		ID:   ruleNumericDerivation,
+		Name: "Numeric constants have rationale",
+		Run:  analyzeNumericDerivation,
+	}
+)
+
+// Quantity dimensions
+const (
+	DimensionDuration   = "duration"
+	DimensionThroughput = "throughput"
+	DimensionSize       = "size"
+	DimensionRatio      = "ratio"
+	DimensionCount      = "count"
+)
+
+// Quantity is a number with its unit, e.g. 100ms or 1000 req/s
+type Quantity struct {
+	Value     float64
+	Unit      string // As written, "" for a bare number
+	Dimension string
+}
+
+func (q Quantity) String() string {
+	return strconv.FormatFloat(q.Value, 'f', -1, 64) + q.Unit
+}
+
+// unitDimensions maps lower-cased units to what they measure
+var unitDimensions = map[string]string{
+	"ns": DimensionDuration, "us": DimensionDuration, "µs": DimensionDuration, "ms": DimensionDuration,
+	"s": DimensionDuration, "sec": DimensionDuration, "secs": DimensionDuration, "second": DimensionDuration,
+	"seconds": DimensionDuration, "m": DimensionDuration, "min": DimensionDuration, "mins": DimensionDuration,
+	"minute": DimensionDuration, "minutes": DimensionDuration, "h": DimensionDuration, "hr": DimensionDuration,
+	"hour": DimensionDuration, "hours": DimensionDuration, "d": DimensionDuration, "day": DimensionDuration,
+	"days": DimensionDuration,
+
+	"rps": DimensionThroughput, "qps": DimensionThroughput, "tps": DimensionThroughput,
+	"req/s": DimensionThroughput, "req/sec": DimensionThroughput, "req/min": DimensionThroughput,
+	"requests/s": DimensionThroughput, "requests/sec": DimensionThroughput, "requests/second": DimensionThroughput,
+	"requests/minute": DimensionThroughput, "requests/min": DimensionThroughput, "ops/s": DimensionThroughput,
+	"ops/sec": DimensionThroughput, "msg/s": DimensionThroughput, "msgs/s": DimensionThroughput,
+	"messages/s": DimensionThroughput, "messages/second": DimensionThroughput, "events/s": DimensionThroughput,
+	"events/sec": DimensionThroughput, "/s": DimensionThroughput, "/sec": DimensionThroughput,
+	"/min": DimensionThroughput, "mbps": DimensionThroughput, "gbps": DimensionThroughput,
+	"mb/s": DimensionThroughput, "gb/s": DimensionThroughput,
+
+	"b": DimensionSize, "kb": DimensionSize, "mb": DimensionSize, "gb": DimensionSize, "tb": DimensionSize,
+	"kib": DimensionSize, "mib": DimensionSize, "gib": DimensionSize, "tib": DimensionSize,
+	"bytes": DimensionSize,
+
+	"%": DimensionRatio, "percent": DimensionRatio,
+}
+
+// quantityPattern matches a number and an optional unit: 100ms, 1,000 req/s, 2.5 GB, 99.9%
+var quantityPattern = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)*)\s*(%|µs|[a-z]+(?:/[a-z]+)?|/[a-z]+)?`)
+
+// perSecondPattern matches throughput written out, as in "1000 requests per second"
+var perSecondPattern = regexp.MustCompile(`(?i)\d\s*[a-z]*\s+(?:per|/)\s*(?:second|sec|minute|min|hour)\b`)
+
+// ParseQuantities returns the numbers in text with their units
+// Numbers followed by a word that is not a unit are counts (25 connections)
+func ParseQuantities(text string) []Quantity {
+	var quantities []Quantity
+	for _, m := range quantityPattern.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] > 0 && isIdentByte(text[m[0]-1]) {
+			continue // Part of an identifier: v2, p99
+		}
+		number := strings.ReplaceAll(text[m[2]:m[3]], ",", "")
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			continue
+		}
+
+		q := Quantity{Value: value, Dimension: DimensionCount}
+		if m[4] >= 0 {
+			unit := text[m[4]:m[5]]
+			if dimension, ok := unitDimensions[strings.ToLower(unit)]; ok {
+				q.Unit, q.Dimension = unit, dimension
+			}
+		}
+		quantities = append(quantities, q)
+	}
+	if perSecondPattern.MatchString(text) {
+		for i := range quantities {
+			if quantities[i].Dimension == DimensionCount {
+				quantities[i].Dimension = DimensionThroughput
+				break
+			}
+		}
+	}
+	return quantities
+}
+
+// vagueTerms are performance adjectives and the dimensions that would quantify them
+var vagueTerms = []struct {
+	pattern    *regexp.Regexp
+	dimensions []string
+	example    string
+}{
+	{regexp.MustCompile(`(?i)\b(fast(?:er|est)?|quick(?:ly)?|low[- ]latency|responsive|real[- ]time|instant(?:ly|aneous)?|snappy|speedy)\b`),
+		[]string{DimensionDuration}, `a latency, e.g. "p99 < 200ms"`},
+	{regexp.MustCompile(`(?i)\b(scalable|scales?(?: well)?|high[- ]throughput|high[- ]volume|massive(?:ly)?)\b`),
+		[]string{DimensionThroughput, DimensionCount}, `a throughput, e.g. "5000 req/s"`},
+	{regexp.MustCompile(`(?i)\b(efficient(?:ly)?|lightweight|performant|high[- ]performance|optimi[sz]ed|minimal (?:overhead|memory|footprint))\b`),
+		[]string{DimensionDuration, DimensionThroughput, DimensionSize, DimensionRatio}, `a latency, throughput or resource limit, e.g. "< 256MB RSS"`},
+}
+
+// performanceSectionPattern matches titles of sections where claims must be quantified
+var performanceSectionPattern = regexp.MustCompile(`(?i)perform|concern|scalab|latency|throughput|capacity|non-functional|\bsla\b|\bslo`)
+
+// analyzePerfQuantified flags vague performance adjectives with no matching quantity on the line or next to it
+func analyzePerfQuantified(src *SpecSources) []AnalyzerIssue {
+	resolve := attributeReplacer(src.Attributes)
+	severity := DefaultSeverity(rulePerfQuantified)
+
+	var issues []AnalyzerIssue
+	for _, doc := range src.Documents {
+		file := src.RelPath(doc.FilePath)
+		for i, line := range doc.Prose {
+			if !performanceSectionPattern.MatchString(line.Section) {
+				continue
+			}
+			text := inlineCodePattern.ReplaceAllString(line.Text, "")
+
+			// The line and the lines directly above and below it
+			var nearby []Quantity
+			for j := i - 1; j <= i+1; j++ {
+				if j < 0 || j >= len(doc.Prose) || doc.Prose[j].Section != line.Section || abs(doc.Prose[j].Line-line.Line) > 1 {
+					continue
+				}
+				nearby = append(nearby, ParseQuantities(resolve.Replace(doc.Prose[j].Text))...)
+			}
+
+			for _, term := range vagueTerms {
+				word := term.pattern.FindString(text)
+				if word == "" || hasDimension(nearby, term.dimensions) {
+					continue
+				}
+				issues = append(issues, AnalyzerIssue{
+					Rule:     rulePerfQuantified,
+					Severity: severity,
+					File:     file,
+					Line:     line.Line,
+					Message:  fmt.Sprintf("%q is not quantified: state %s", word, term.example),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+func hasDimension(quantities []Quantity, dimensions []string) bool {
+	for _, q := range quantities {
+		for _, d := range dimensions {
+			if q.Dimension == d {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// numericValuePattern matches attribute values that are a single quantity: 25, 300s, 1.5GB, 99.9%
+var numericValuePattern = regexp.MustCompile(`(?i)^[<>~≤≥]?\s*\d+(?:[.,]\d+)?\s*(?:%|µs|[a-z]+(?:/[a-z]+)?)?$`)
+
+// attributeRefPattern matches {name} references
+var attributeRefPattern = regexp.MustCompile(`\{([a-zA-Z0-9_-]+)\}`)
+
+// derivationPattern matches text that explains where a number comes from
+var derivationPattern = regexp.MustCompile(`(?i)[=÷×]|\b(?:because|based on|derived|derivation|rationale|since|so that|to keep|to allow|headroom|budget|per (?:second|minute|request|user|connection|core)|calculated|measured|benchmark|worst case|peak)\b`)
+
+// boundPattern matches text ending in a comparison, so the number that follows is a target
+// or limit the spec sets (p99 < {x}, rate limit: {y}) rather than a constant to derive
+var boundPattern = regexp.MustCompile(`(?i)(?:[<>≤≥]|\b(?:under|below|above|at most|at least|up to|within|limit|max|maximum|min|minimum))[\s:=*_]*$`)
+
+// analyzeNumericDerivation flags numeric attributes with no rationale: no comment line above
+// the definition, no reference whose line explains the number and no use as a target or limit
+func analyzeNumericDerivation(src *SpecSources) []AnalyzerIssue {
+	index := src.attributes()
+	severity := DefaultSeverity(ruleNumericDerivation)
+
+	// Attributes referenced on a line that explains them, or as a bound
+	explained := make(map[string]bool)
+	for _, doc := range src.Documents {
+		var lines []string
+		for _, line := range doc.Prose {
+			lines = append(lines, line.Text)
+		}
+		for _, table := range doc.Tables {
+			for _, row := range table.Rows {
+				lines = append(lines, strings.Join(row.Cells, " | "))
+			}
+		}
+		for _, line := range lines {
+			derived := derivationPattern.MatchString(line)
+			for _, m := range attributeRefPattern.FindAllStringSubmatchIndex(line, -1) {
+				if derived || boundPattern.MatchString(line[:m[0]]) {
+					explained[line[m[2]:m[3]]] = true
+				}
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var issues []AnalyzerIssue
+	for _, def := range index.Definitions {
+		value := strings.TrimSpace(def.Value)
+		if seen[def.Name] || builtinAttributes[def.Name] || !numericValuePattern.MatchString(value) ||
+			strings.Contains(def.Name, "version") {
+			continue
+		}
+		seen[def.Name] = true
+		if explained[def.Name] || hasCommentAbove(src.Content[def.FilePath], def.Line) {
+			continue
+		}
+
+		what := "number"
+		if q := ParseQuantities(value); len(q) > 0 && q[0].Dimension != DimensionCount {
+			what = q[0].Dimension
+		}
+		issues = append(issues, AnalyzerIssue{
+			Rule:     ruleNumericDerivation,
+			Severity: severity,
+			File:     src.RelPath(def.FilePath),
+			Line:     def.Line,
+			Message:  fmt.Sprintf("{%s} is a %s (%s) with no rationale: add a // comment above it, or explain the number where it is used", def.Name, what, value),
+		})
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// hasCommentAbove reports whether the line above a 1-based line is a // comment
+// Block comment delimiters (////) do not count
+func hasCommentAbove(content string, line int) bool {
+	lines := strings.Split(content, "\n")
+	if line < 2 || line-2 >= len(lines) {
+		return false
+	}
+	text := strings.TrimSpace(lines[line-2])
+	return strings.HasPrefix(text, "//") && !strings.HasPrefix(text, "////")
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseQuantities(t *testing.T) {
+	tests := map[string]string{
+		"P99 < 100ms":                       "100ms duration",
+		"1,000 req/s sustained":             "1000req/s throughput",
+		"2.5 GB heap, 99.9% uptime":         "2.5GB size, 99.9% ratio",
+		"25 connections":                    "25 count",
+		"5000 requests per second":          "5000 throughput",
+		"3 retries a minute":                "3 count",
+		"TTL: 300s, refreshed after 5 min":  "300s duration, 5min duration",
+		"p99 and v2 are identifiers, not 3": "3 count",
+	}
+	for text, want := range tests {
+		var got []string
+		for _, q := range ParseQuantities(text) {
+			got = append(got, q.String()+" "+q.Dimension)
+		}
+		if strings.Join(got, ", ") != want {
+			t.Errorf("ParseQuantities(%q) = %q, want %q", text, strings.Join(got, ", "), want)
+		}
+	}
+}
+
+func TestQuantificationAnalyzers(t *testing.T) {
+	manifest := `= Search
+:p99: 80ms
+// Two replicas per zone across three zones
+:replicas: 6
+:pool-size: 40
+:cache-ttl: 10m
+:go-version: 1.22
+
+== Performance
+
+Search must be fast.
+
+Lookups are fast: p99 under {p99}.
+The index is scalable
+to 5000 req/s per node.
+
+Responses stay efficient ` + "`fast path`" + `.
+
+== Storage
+
+The pool holds {pool-size} connections = 4 workers x 10 queries in flight.
+Entries expire after {cache-ttl}. Writes are fast.
+`
+	src := loadSpecFiles(t, map[string]string{"MANIFEST.adoc": manifest})
+
+	var got []string
+	for _, issue := range append(analyzePerfQuantified(src), analyzeNumericDerivation(src)...) {
+		got = append(got, issue.Location()+" "+issue.Rule+" "+issue.Message)
+	}
+	// {p99} is a target ("under {p99}") and {pool-size} is derived; {cache-ttl} has no rationale
+	want := []string{
+		`MANIFEST.adoc:11 perf-quantified "fast" is not quantified: state a latency, e.g. "p99 < 200ms"`,
+		`MANIFEST.adoc:17 perf-quantified "efficient" is not quantified: state a latency, throughput or resource limit, e.g. "< 256MB RSS"`,
+		`MANIFEST.adoc:6 numeric-derivation {cache-ttl} is a duration (10m) with no rationale: add a // comment above it, or explain the number where it is used`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
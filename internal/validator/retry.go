@@ -0,0 +1,108 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Retry defaults for transient provider failures
+const (
+	DefaultProviderTimeout = 10 * time.Minute
+	DefaultRetries         = 2
+	DefaultRetryDelay      = 2 * time.Second
+	maxRetryDelay          = 30 * time.Second
+)
+
+// TransientError marks a provider failure that is worth retrying
+// (rate limits, overload, server errors, dropped connections)
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string { return e.Err.Error() }
+
+func (e *TransientError) Unwrap() error { return e.Err }
+
+// IsTransient reports whether err is worth retrying
+// Per-call timeouts count as transient; cancellation of the caller's context does not
+func IsTransient(err error) bool {
+	var transient *TransientError
+	if errors.As(err, &transient) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryProvider retries transient failures with exponential backoff
+type retryProvider struct {
+	Provider
+	retries   int
+	baseDelay time.Duration
+}
+
+// WithRetry wraps p so transient failures are retried up to retries times
+func WithRetry(p Provider, retries int, baseDelay time.Duration) Provider {
+	if retries <= 0 {
+		return p
+	}
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryDelay
+	}
+	return &retryProvider{Provider: p, retries: retries, baseDelay: baseDelay}
+}
+
+// Complete calls the wrapped provider, backing off 1x, 2x, 4x... the base delay between attempts
+func (r *retryProvider) Complete(ctx context.Context, prompt string) (*Response, error) {
+	var lastErr error
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 {
+			// A streamed attempt may have printed part of its reply before failing
+			if w := streamOf(ctx); w != nil {
+				fmt.Fprintf(w, "\n\n[%v - retrying]\n\n", lastErr)
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(r.backoff(attempt)):
+			}
+		}
+
+		resp, err := r.Provider.Complete(ctx, prompt)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !IsTransient(err) {
+			return nil, err
+		}
+		lastErr = err
+
+		// The failed attempt may have been billed; stop once it spends the budget
+		if m := meterOf(ctx); m != nil && attempt < r.retries {
+			m.recordFailed(ctx, prompt, fmt.Sprintf("attempt %d failed", attempt+1))
+			if m.spent() {
+				return nil, fmt.Errorf("not retrying, the budget is spent after %d attempt(s): %w", attempt+1, lastErr)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("failed after %d attempts: %w", r.retries+1, lastErr)
+}
+
+// backoff returns the delay before the given retry attempt (1-based)
+func (r *retryProvider) backoff(attempt int) time.Duration {
+	delay := r.baseDelay << (attempt - 1)
+	if delay > maxRetryDelay || delay <= 0 {
+		return maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithRetry_RecoversFromTransientErrors(t *testing.T) {
+	p := &ScriptedProvider{Replies: []ScriptedReply{
+		{Err: &TransientError{Err: errors.New("HTTP 529: overloaded")}},
+		{Err: &TransientError{Err: errors.New("HTTP 503: unavailable")}},
+		{Text: "ok"},
+	}}
+
+	resp, err := WithRetry(p, 2, time.Millisecond).Complete(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if resp.Text != "ok" {
+		t.Errorf("Text = %q, want ok", resp.Text)
+	}
+	if calls := len(p.Prompts()); calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestWithRetry_GivesUp(t *testing.T) {
+	p := &ScriptedProvider{Replies: []ScriptedReply{
+		{Err: &TransientError{Err: errors.New("HTTP 429: rate limited")}},
+		{Err: &TransientError{Err: errors.New("HTTP 429: rate limited")}},
+		{Text: "too late"},
+	}}
+
+	_, err := WithRetry(p, 1, time.Millisecond).Complete(context.Background(), "prompt")
+	if err == nil || !strings.Contains(err.Error(), "failed after 2 attempts") {
+		t.Fatalf("err = %v, want failure after 2 attempts", err)
+	}
+}
+
+func TestWithRetry_DoesNotRetryPermanentErrors(t *testing.T) {
+	p := &ScriptedProvider{Replies: []ScriptedReply{
+		{Err: errors.New("HTTP 401: invalid api key")},
+		{Text: "unreachable"},
+	}}
+
+	_, err := WithRetry(p, 3, time.Millisecond).Complete(context.Background(), "prompt")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls := len(p.Prompts()); calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRunUltraValidation_ContinuesWithQuorum(t *testing.T) {
+	finding := "- Rule: exact-versions\n- Location: Dependencies\n- Issue: No version for redis\n"
+	p := &ScriptedProvider{Replies: []ScriptedReply{
+		{Text: finding},
+		{Err: errors.New("claude CLI failed: exit status 1")},
+		{Text: finding},
+	}}
+
+	var out bytes.Buffer
+	result, err := RunUltraValidation(context.Background(), "= Spec\n", &out, ValidationOptions{Provider: p})
+	if err != nil {
+		t.Fatalf("RunUltraValidation failed: %v", err)
+	}
+	if len(result.DegradedRuns) != 1 {
+		t.Errorf("DegradedRuns = %v, want one run", result.DegradedRuns)
+	}
+	if len(result.Findings) != 1 || result.Findings[0].Confidence != 1 {
+		t.Errorf("Findings = %+v, want one finding with confidence 1", result.Findings)
+	}
+	if !strings.Contains(out.String(), "(degraded)") {
+		t.Errorf("output does not mark the degraded run:\n%s", out.String())
+	}
+}
+
+func TestRunUltraValidation_BelowQuorum(t *testing.T) {
+	p := &ScriptedProvider{Replies: []ScriptedReply{
+		{Text: "Specification passes all checks."},
+		{Err: errors.New("boom")},
+		{Err: errors.New("boom")},
+	}}
+
+	var out bytes.Buffer
+	_, err := RunUltraValidation(context.Background(), "= Spec\n", &out, ValidationOptions{Provider: p})
+	if err == nil || !strings.Contains(err.Error(), "quorum 2") {
+		t.Fatalf("err = %v, want quorum failure", err)
+	}
+}
@@ -0,0 +1,545 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+// How a rule is checked
+const (
+	RuleSemantic = "semantic"        // By the provider, from the validate prompt checklist
+	RuleNative   = "native"          // By a native analyzer in the structural phase
+	RuleHybrid   = "native+semantic" // Approximated natively, then checked by the provider
+)
+
+// Rule is a built-in validation rule
+type Rule struct {
+	ID        string        `json:"id"`
+	Kind      string        `json:"kind"`     // RuleSemantic, RuleNative or RuleHybrid
+	Severity  string        `json:"severity"` // Default; native rules report the most severe case at this level
+	Summary   string        `json:"summary"`
+	Checklist string        `json:"-"` // Wording in the validate prompt; "" for native-only rules
+	Outline   string        `json:"-"` // Wording in the outline prompt, for rules in crossChunkRules
+	Rationale string        `json:"rationale"`
+	Examples  []RuleExample `json:"examples,omitempty"`
+	Doc       string        `json:"doc,omitempty"` // Where the rule is explained, relative to the repo root
+}
+
+// RuleExample contrasts spec text that breaks a rule with text that follows it
+type RuleExample struct {
+	Bad  string `json:"bad,omitempty"`
+	Good string `json:"good"`
+}
+
+// builtinRules is the rule registry: the semantic checklist in prompt order, then native-only rules
+var builtinRules = []Rule{
+	{
+		ID:        ruleExactVersions,
+		Kind:      RuleHybrid,
+		Severity:  SeverityError,
+		Summary:   "All dependencies have exact versions",
+		Checklist: `All library/service dependencies must have exact versions (e.g., "PostgreSQL 16", "React@18.2.0", not just "PostgreSQL" or "a database")`,
+		Rationale: "Unpinned dependencies drift between builds, pull in breaking changes and make the implementation impossible to reproduce.",
+		Examples: []RuleExample{
+			{Bad: "Use database", Good: "Use PostgreSQL 16 (Docker: postgres:16-alpine)"},
+			{Bad: "Install framework and UI library", Good: "Install React 18.2.0 and Next.js 14.1.0"},
+		},
+		Doc: "docs/anti-patterns.md#missing-versions",
+	},
+	{
+		ID:        "no-or-choices",
+		Kind:      RuleSemantic,
+		Severity:  SeverityError,
+		Summary:   "No unresolved alternatives",
+		Checklist: `No unresolved "X or Y" alternatives in requirements (ignore error message examples like "Email or password incorrect")`,
+		Rationale: "The AI has to choose arbitrarily, may pick the wrong option for the context and makes inconsistent choices across related decisions.",
+		Examples: []RuleExample{
+			{Bad: "Storage: Database A or Database B", Good: "Storage: PostgreSQL 16"},
+			{Bad: "Use Library X or Library Y for visualization", Good: "Use Library X v3.4.0 for visualization"},
+		},
+		Doc: "docs/anti-patterns.md#unresolved-options",
+	},
+	{
+		ID:        "no-conditionals",
+		Kind:      RuleSemantic,
+		Severity:  SeverityError,
+		Summary:   "No conditional logic (if needed, optional, TBD)",
+		Checklist: `No conditional logic deferring decisions ("if needed", "when required", "optional", "future", "TBD", "TODO")`,
+		Rationale: `The decision is deferred to implementation, where the AI cannot tell when "if needed" applies.`,
+		Examples: []RuleExample{
+			{Bad: "If query is slow, add caching", Good: "Add caching with 5-minute TTL for queries"},
+			{Bad: "When load exceeds threshold, implement sharding", Good: "Implement sharding on user_id (8 shards)"},
+		},
+		Doc: "docs/anti-patterns.md#conditional-implementation",
+	},
+	{
+		ID:        "no-optional",
+		Kind:      RuleSemantic,
+		Severity:  SeverityWarning,
+		Summary:   "No optional sections",
+		Checklist: "No optional sections - features are either fully specified or explicitly excluded",
+		Rationale: "Optional features leave the scope ambiguous; the AI includes or omits them unpredictably.",
+		Examples: []RuleExample{
+			{Bad: "Future: Implement caching", Good: "Caching: Not included in current scope"},
+			{Bad: "Optional: Add real-time updates", Good: "Include feature in spec OR omit entirely"},
+		},
+		Doc: "docs/anti-patterns.md#optional-sections",
+	},
+	{
+		ID:        "file-tree",
+		Kind:      RuleSemantic,
+		Severity:  SeverityWarning,
+		Summary:   "Complete file structure provided",
+		Checklist: "Complete file/directory structure provided",
+		Outline:   "The spec has a section giving the complete file/directory structure",
+		Rationale: "Without a file tree the AI invents its own layout, and files the spec describes end up in different places across runs.",
+		Doc:       "docs/structure.md",
+	},
+	{
+		ID:        "types-complete",
+		Kind:      RuleSemantic,
+		Severity:  SeverityError,
+		Summary:   "All types fully defined",
+		Checklist: "All data types fully defined with every field and type annotation",
+		Outline:   "Every type referenced in a declaration is itself declared somewhere in the outline (or is a standard/library type)",
+		Rationale: "The AI cannot generate code for a type without knowing its structure; field names and types must all be specified.",
+		Doc:       "docs/validation.md#type-reference-without-definition",
+	},
+	{
+		ID:        "example-data",
+		Kind:      RuleSemantic,
+		Severity:  SeverityWarning,
+		Summary:   "Actual examples, not just schemas",
+		Checklist: "Actual example data provided, not just schemas",
+		Rationale: "A schema alone leaves formats, units and edge values to guesswork; a real example pins them down.",
+		Doc:       "docs/structure.md",
+	},
+	{
+		ID:        "db-schema",
+		Kind:      RuleSemantic,
+		Severity:  SeverityError,
+		Summary:   "Database schema complete",
+		Checklist: "Database schema complete with tables, columns, indexes, constraints",
+		Rationale: "Missing columns, indexes or constraints surface as migrations after the fact, or as data the implementation cannot store.",
+		Doc:       "docs/structure.md",
+	},
+	{
+		ID:        "api-routes",
+		Kind:      RuleSemantic,
+		Severity:  SeverityError,
+		Summary:   "API routes fully specified",
+		Checklist: "API routes fully specified with method, path, params, all response codes",
+		Rationale: "Clients and servers built from the same spec only agree when every route, parameter and response code is stated.",
+		Doc:       "docs/structure.md",
+	},
+	{
+		ID:        rulePerfQuantified,
+		Kind:      RuleHybrid,
+		Severity:  SeverityWarning,
+		Summary:   "Performance has numbers, not adjectives",
+		Checklist: `Performance requirements have concrete numbers (e.g., "P99 <100ms"), not adjectives ("fast", "efficient")`,
+		Rationale: `"Fast" is subjective and cannot be validated; without numbers the AI cannot make the right trade-offs.`,
+		Examples: []RuleExample{
+			{Bad: "Should be fast", Good: "P99 latency <100ms"},
+			{Bad: "Support large datasets", Good: "Support up to 1M records in memory (~64MB)"},
+		},
+		Doc: "docs/anti-patterns.md#vague-requirements",
+	},
+	{
+		ID:        ruleNumericDerivation,
+		Kind:      RuleHybrid,
+		Severity:  SeverityInfo,
+		Summary:   "Constants have rationale",
+		Checklist: "Numeric constants have rationale or derivation",
+		Rationale: "A constant without a derivation cannot be adjusted safely; the AI does not know which way to lean when a related value changes.",
+		Doc:       "docs/validation.md#ambiguous-intent",
+	},
+	{
+		ID:        "data-formats",
+		Kind:      RuleSemantic,
+		Severity:  SeverityWarning,
+		Summary:   "Format examples with real values",
+		Checklist: "Data format examples with real values",
+		Rationale: "Serialized formats are easy to get subtly wrong; real values settle field names, casing and encodings.",
+		Doc:       "docs/structure.md",
+	},
+	{
+		ID:        "error-handling",
+		Kind:      RuleSemantic,
+		Severity:  SeverityError,
+		Summary:   "Error responses specified",
+		Checklist: `Error responses specified, not "handle appropriately"`,
+		Rationale: `"Appropriately" is interpreted differently on every run; callers need the exact status codes and error bodies.`,
+		Examples: []RuleExample{
+			{Bad: "Handle errors appropriately", Good: "Return 400 + error JSON on validation failure"},
+		},
+		Doc: "docs/anti-patterns.md#vague-requirements",
+	},
+	{
+		ID:        "concurrency",
+		Kind:      RuleSemantic,
+		Severity:  SeverityWarning,
+		Summary:   "Threading model specified if needed",
+		Checklist: "Concurrency/threading model specified if applicable",
+		Rationale: "An unstated concurrency model leads to races, or to a design that serializes work the spec expects to run in parallel.",
+		Doc:       "docs/structure.md",
+	},
+	{
+		ID:        "persistence",
+		Kind:      RuleSemantic,
+		Severity:  SeverityError,
+		Summary:   "Storage format concrete",
+		Checklist: `Storage format specified concretely, not "save to disk"`,
+		Rationale: `"Save to disk" leaves the format, location and compatibility of stored data to the AI, and stored data outlives the code.`,
+		Doc:       "docs/structure.md",
+	},
+	{
+		ID:        "deployment",
+		Kind:      RuleSemantic,
+		Severity:  SeverityWarning,
+		Summary:   "Deployment config complete",
+		Checklist: "Deployment configuration complete with specific platform",
+		Rationale: "Without a target platform the AI cannot write the configuration, and the build may not run where it is deployed.",
+		Doc:       "docs/structure.md",
+	},
+	{
+		ID:        "secrets-separated",
+		Kind:      RuleSemantic,
+		Severity:  SeverityWarning,
+		Summary:   "Config/secrets properly separated",
+		Checklist: "Config/secrets/constants properly separated",
+		Rationale: "Secrets mixed into config or code end up committed; config mixed into constants cannot change without a rebuild.",
+		Doc:       "docs/structure.md",
+	},
+	{
+		ID:        "no-weak-language",
+		Kind:      RuleSemantic,
+		Severity:  SeverityWarning,
+		Summary:   "No should/could/might",
+		Checklist: `No weak obligation words ("should", "could", "might", "may") - use definitive language`,
+		Rationale: "The AI reads hedged requirements as optional, so they are implemented inconsistently or not at all.",
+		Examples: []RuleExample{
+			{Bad: "Could use connection pooling", Good: "Use connection pooling (max 25 connections)"},
+			{Bad: "Might need rate limiting", Good: "Implement rate limiting (100/min)"},
+		},
+		Doc: "docs/anti-patterns.md#weak-obligation-language",
+	},
+	{
+		ID:        "context-section",
+		Kind:      RuleSemantic,
+		Severity:  SeverityError,
+		Summary:   "Context section with Identity and Stack",
+		Checklist: `Spec has a "Context" section (or equivalent) with at minimum Identity and Stack. Abstract/Approach/Scope are valuable additions but not strictly required.`,
+		Outline:   `The spec has a "Context" section (or "Overview"/"System Context") with at minimum Identity and Stack subsections`,
+		Rationale: "Intent scattered through implementation details leaves edge cases to guesswork; a Context section is the single source of truth for what the system is and why.",
+		Doc:       "docs/validation.md#ambiguous-intent",
+	},
+	{
+		ID:        ruleSpecSchema,
+		Kind:      RuleNative,
+		Severity:  SeverityError,
+		Summary:   "Required sections and fields present",
+		Rationale: "The schema names the sections every spec of its kind needs; severities can be set per section in the schema file.",
+		Doc:       "README.md#spec-schema",
+	},
+	{
+		ID:        ruleUndefinedAttribute,
+		Kind:      RuleNative,
+		Severity:  SeverityError,
+		Summary:   "Attribute references are defined",
+		Rationale: "An undefined {attribute} compiles to its literal name, so the AI reads a placeholder instead of a value.",
+		Doc:       "docs/structure.md#solution-tagged-sections--attributes",
+	},
+	{
+		ID:        ruleUnusedAttribute,
+		Kind:      RuleNative,
+		Severity:  SeverityWarning,
+		Summary:   "Defined attributes are referenced",
+		Rationale: "An unused attribute is usually a stale value, or a place where the text repeats the value by hand.",
+		Doc:       "docs/structure.md#solution-tagged-sections--attributes",
+	},
+	{
+		ID:        ruleRedefinedAttribute,
+		Kind:      RuleNative,
+		Severity:  SeverityError,
+		Summary:   "Attributes are defined once",
+		Rationale: "A later definition silently wins for the rest of the spec; redefining with a different value is an error, with the same value a warning.",
+		Doc:       "docs/structure.md#solution-tagged-sections--attributes",
+	},
+	{
+		ID:        ruleHardcodedValue,
+		Kind:      RuleNative,
+		Severity:  SeverityInfo,
+		Summary:   "Attribute values referenced, not repeated",
+		Rationale: "A value typed by hand where an attribute exists goes stale when the attribute changes.",
+		Doc:       "docs/structure.md#solution-tagged-sections--attributes",
+	},
+	{
+		ID:        ruleTypesSQL,
+		Kind:      RuleNative,
+		Severity:  SeverityError,
+		Summary:   "Go types match SQL schema",
+		Rationale: "A struct and its table disagreeing on columns or types forces the AI to pick one of them.",
+		Doc:       "docs/structure.md#type-definitions",
+	},
+	{
+		ID:        ruleTypesJSON,
+		Kind:      RuleNative,
+		Severity:  SeverityWarning,
+		Summary:   "JSON examples match Go types",
+		Rationale: "An example with keys the type does not have, or of the wrong type, contradicts the definition it illustrates.",
+		Doc:       "docs/structure.md#data-format-specifications",
+	},
+	{
+		ID:        ruleUndefinedType,
+		Kind:      RuleNative,
+		Severity:  SeverityWarning,
+		Summary:   "Referenced types are declared",
+		Rationale: "The AI cannot generate code for a type without knowing its structure.",
+		Doc:       "docs/validation.md#type-reference-without-definition",
+	},
+}
+
+// Rules returns the built-in rules: the semantic checklist in prompt order, then native-only rules
+func Rules() []Rule {
+	return slices.Clone(builtinRules)
+}
+
+// LookupRule finds a built-in rule by ID
+func LookupRule(id string) (Rule, bool) {
+	id = strings.ToLower(id)
+	for _, rule := range builtinRules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// checklistRules returns the rules in the validate prompt checklist, leaving out disabled ones
+func checklistRules(disabled map[string]bool) []Rule {
+	var rules []Rule
+	for _, rule := range builtinRules {
+		if rule.Checklist != "" && !disabled[rule.ID] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// DisabledRules collects rules.disabled from .spec.yaml, rejecting unknown rule IDs
+func DisabledRules(cfg *config.SpecConfig) (map[string]bool, error) {
+	disabled := make(map[string]bool)
+	for _, id := range cfg.Rules.Disabled {
+		id = normalizeRuleID(id)
+		_, builtin := LookupRule(id)
+		custom := slices.ContainsFunc(cfg.Rules.Custom, func(r config.CustomRule) bool { return normalizeRuleID(r.ID) == id })
+		if !builtin && !custom {
+			return nil, fmt.Errorf("unknown rule %q in rules.disabled (see cca rules)", id)
+		}
+		disabled[id] = true
+	}
+	return disabled, nil
+}
+
+// ApplyRuleConfig drops the checks of disabled native rules and applies configured
+// severities to native issues, then re-evaluates the structural result
+func ApplyRuleConfig(result *ValidationResult, severities map[string]string, disabled map[string]bool) {
+	analyzers := make(map[string]bool)
+	for _, analyzer := range Analyzers() {
+		analyzers[analyzer.ID] = true
+	}
+
+	var checks []StructuralCheck
+	for _, check := range result.StructuralChecks {
+		if !analyzers[check.ID] {
+			checks = append(checks, check)
+			continue
+		}
+		if disabled[check.ID] {
+			continue
+		}
+		issues := ApplyIssueConfig(check.Issues, severities, nil)
+		checks = append(checks, analyzerCheck(Analyzer{ID: check.ID, Name: check.Name}, issues))
+	}
+	result.StructuralChecks = checks
+	result.StructuralPassed = AllStructuralChecksPassed(checks)
+}
+
+// ApplyIssueConfig drops native issues of disabled rules and applies configured severities
+// The input slice is left unchanged
+func ApplyIssueConfig(issues []AnalyzerIssue, severities map[string]string, disabled map[string]bool) []AnalyzerIssue {
+	var configured []AnalyzerIssue
+	for _, issue := range issues {
+		if disabled[issue.Rule] {
+			continue
+		}
+		if severity, ok := severities[issue.Rule]; ok {
+			issue.Severity = severity
+		}
+		configured = append(configured, issue)
+	}
+	return configured
+}
+
+// DropDisabled removes findings for disabled rules
+func DropDisabled(findings []Finding, disabled map[string]bool) []Finding {
+	if len(disabled) == 0 {
+		return findings
+	}
+	var kept []Finding
+	for _, f := range findings {
+		if !disabled[normalizeRuleID(f.Rule)] {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// RuleStatus is a rule as configured for a project
+type RuleStatus struct {
+	Rule
+	Custom  bool `json:"custom,omitempty"`
+	Enabled bool `json:"enabled"`
+}
+
+// ProjectRules lists the built-in and custom rules with the severities and
+// disabled rules configured in .spec.yaml applied
+func ProjectRules(cfg *config.SpecConfig) ([]RuleStatus, error) {
+	severities, err := RuleSeverities(cfg)
+	if err != nil {
+		return nil, err
+	}
+	disabled, err := DisabledRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []RuleStatus
+	for _, rule := range builtinRules {
+		statuses = append(statuses, RuleStatus{Rule: rule})
+	}
+	for _, custom := range cfg.Rules.Custom {
+		rule := Rule{
+			ID:        custom.ID,
+			Kind:      RuleSemantic,
+			Severity:  SeverityWarning,
+			Summary:   custom.Description,
+			Checklist: custom.Description,
+			Rationale: "Project rule from .spec.yaml.",
+		}
+		for _, example := range custom.Examples {
+			rule.Examples = append(rule.Examples, RuleExample{Good: example})
+		}
+		statuses = append(statuses, RuleStatus{Rule: rule, Custom: true})
+	}
+
+	for i := range statuses {
+		id := normalizeRuleID(statuses[i].ID)
+		if severity, ok := severities[id]; ok {
+			statuses[i].Severity = severity
+		}
+		statuses[i].Enabled = !disabled[id]
+	}
+	return statuses, nil
+}
+
+// FindRuleStatus finds a project rule by ID
+func FindRuleStatus(statuses []RuleStatus, id string) (RuleStatus, error) {
+	id = normalizeRuleID(id)
+	var ids []string
+	for _, status := range statuses {
+		if normalizeRuleID(status.ID) == id {
+			return status, nil
+		}
+		ids = append(ids, status.ID)
+	}
+	sort.Strings(ids)
+	return RuleStatus{}, fmt.Errorf("unknown rule %q (available: %s)", id, strings.Join(ids, ", "))
+}
+
+// FormatRuleList formats project rules as a table
+func FormatRuleList(statuses []RuleStatus) string {
+	rows := [][]string{{"RULE", "KIND", "SEVERITY", "STATUS", "CHECKS"}}
+	for _, status := range statuses {
+		kind, state := status.Kind, "enabled"
+		if status.Custom {
+			kind += " (custom)"
+		}
+		if !status.Enabled {
+			state = "disabled"
+		}
+		rows = append(rows, []string{status.ID, kind, status.Severity, state, status.Summary})
+	}
+	return formatColumns(rows)
+}
+
+// FormatRuleListJSON formats project rules as JSON
+func FormatRuleListJSON(statuses []RuleStatus) string {
+	if statuses == nil {
+		statuses = []RuleStatus{}
+	}
+	data, _ := json.MarshalIndent(statuses, "", "  ")
+	return string(data)
+}
+
+// FormatRuleExplanation explains a rule: what it checks, why, examples and how to suppress it
+func FormatRuleExplanation(status RuleStatus) string {
+	var sb strings.Builder
+
+	state := "enabled"
+	if !status.Enabled {
+		state = "disabled in .spec.yaml"
+	}
+	fmt.Fprintf(&sb, "%s - %s\n", status.ID, status.Summary)
+	fmt.Fprintf(&sb, "Kind: %s, severity: %s, %s\n", status.Kind, status.Severity, state)
+
+	if status.Checklist != "" && status.Checklist != status.Summary {
+		fmt.Fprintf(&sb, "\n%s\n", status.Checklist)
+	}
+	fmt.Fprintf(&sb, "\nWhy: %s\n", status.Rationale)
+
+	if len(status.Examples) > 0 {
+		sb.WriteString("\nExamples:\n")
+		for _, example := range status.Examples {
+			if example.Bad != "" {
+				fmt.Fprintf(&sb, "  ✗ %s\n", example.Bad)
+			}
+			fmt.Fprintf(&sb, "  ✓ %s\n", example.Good)
+		}
+	}
+	if status.Doc != "" {
+		fmt.Fprintf(&sb, "\nSee %s\n", status.Doc)
+	}
+
+	sb.WriteString("\nTo suppress it, add to .spec.yaml:\n")
+	fmt.Fprintf(&sb, "  rules:\n    disabled:\n      - %s\n", status.ID)
+	sb.WriteString("Or keep it and lower its severity:\n")
+	fmt.Fprintf(&sb, "  rules:\n    severity:\n      %s: info\n", status.ID)
+	return sb.String()
+}
+
+// RulesMarkdown renders the built-in rules as the Markdown table in docs/validation.md
+func RulesMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("| Rule | Checks | Kind | Default severity |\n")
+	sb.WriteString("|------|--------|------|------------------|\n")
+	for _, rule := range builtinRules {
+		id := "`" + rule.ID + "`"
+		if rule.Doc != "" {
+			// The table lives in docs/, so links are relative to it
+			link, ok := strings.CutPrefix(rule.Doc, "docs/")
+			if !ok {
+				link = "../" + rule.Doc
+			}
+			id = fmt.Sprintf("[%s](%s)", id, link)
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", id, rule.Summary, rule.Kind, rule.Severity)
+	}
+	return sb.String()
+}
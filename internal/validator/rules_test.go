@@ -0,0 +1,186 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func TestRules_Registry(t *testing.T) {
+	ids := make(map[string]Rule)
+	for _, rule := range Rules() {
+		if _, dup := ids[rule.ID]; dup {
+			t.Errorf("rule %s is registered twice", rule.ID)
+		}
+		ids[rule.ID] = rule
+		if NormalizeSeverity(rule.Severity) != rule.Severity || rule.Summary == "" || rule.Rationale == "" {
+			t.Errorf("rule %s is incomplete: %+v", rule.ID, rule)
+		}
+		if (rule.Kind == RuleNative) != (rule.Checklist == "") {
+			t.Errorf("rule %s: only native rules stay out of the checklist", rule.ID)
+		}
+	}
+
+	for _, analyzer := range Analyzers() {
+		if rule, ok := ids[analyzer.ID]; !ok || rule.Kind == RuleSemantic {
+			t.Errorf("analyzer %s has no native rule in the registry", analyzer.ID)
+		}
+	}
+	if n := len(checklistRules(nil)); n != 19 {
+		t.Errorf("checklist has %d rules, want 19", n)
+	}
+	if len(ListRules()) != len(Rules()) {
+		t.Error("ListRules should cover the whole registry")
+	}
+}
+
+func TestRules_ExamplesFromDocs(t *testing.T) {
+	for _, rule := range Rules() {
+		if rule.Doc == "" {
+			continue
+		}
+		doc, err := os.ReadFile(filepath.Join("..", "..", strings.SplitN(rule.Doc, "#", 2)[0]))
+		if err != nil {
+			t.Errorf("rule %s: %v", rule.ID, err)
+			continue
+		}
+		for _, example := range rule.Examples {
+			if !strings.Contains(string(doc), example.Bad) || !strings.Contains(string(doc), example.Good) {
+				t.Errorf("rule %s: example %+v is not in %s", rule.ID, example, rule.Doc)
+			}
+		}
+	}
+}
+
+func TestRules_DocsTable(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "docs", "validation.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := string(data)
+	start := strings.Index(doc, "<!-- rules:begin")
+	end := strings.Index(doc, "<!-- rules:end -->")
+	if start < 0 || end < start {
+		t.Fatal("docs/validation.md has no generated rule table")
+	}
+	table := doc[strings.Index(doc[start:], "\n")+start+1 : end]
+	if table != RulesMarkdown() {
+		t.Errorf("docs/validation.md rule table is stale - regenerate it with cca rules --markdown")
+	}
+}
+
+func TestRender_DisabledRules(t *testing.T) {
+	prompts, err := NewPromptConfig(&config.SpecConfig{
+		Rules: config.RulesConfig{
+			Custom:   []config.CustomRule{{ID: "queue-dlq", Description: "Every queue has a DLQ"}, {ID: "Endpoint-Authz", Description: "Every endpoint states an authz rule"}},
+			Disabled: []string{"context-section", "endpoint-authz"},
+		},
+	}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prompt, err := prompts.Render("validate", TemplateData{CompiledSpec: "# Spec"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"## The 18-Point Completeness Checklist", "18. **no-weak-language**", "19. **queue-dlq**"} {
+		if !strings.Contains(prompt, expect) {
+			t.Errorf("expected prompt to contain %q", expect)
+		}
+	}
+	for _, unexpected := range []string{"context-section", "Context Section Guidance", "Endpoint-Authz"} {
+		if strings.Contains(prompt, unexpected) {
+			t.Errorf("disabled rule leaked into the prompt: %q", unexpected)
+		}
+	}
+
+	if _, err := DisabledRules(&config.SpecConfig{Rules: config.RulesConfig{Disabled: []string{"no-such-rule"}}}); err == nil {
+		t.Error("expected an error for an unknown disabled rule")
+	}
+}
+
+func TestApplyRuleConfig(t *testing.T) {
+	result := &ValidationResult{StructuralChecks: []StructuralCheck{
+		{ID: "compiles", Passed: true},
+		analyzerCheck(undefinedAttributeAnalyzer, []AnalyzerIssue{{Rule: ruleUndefinedAttribute, Severity: SeverityError, File: "a.adoc", Line: 3}}),
+		analyzerCheck(unusedAttributeAnalyzer, []AnalyzerIssue{{Rule: ruleUnusedAttribute, Severity: SeverityWarning, File: "a.adoc", Line: 1}}),
+	}}
+
+	ApplyRuleConfig(result, map[string]string{ruleUndefinedAttribute: SeverityWarning}, map[string]bool{ruleUnusedAttribute: true})
+
+	var ids []string
+	for _, check := range result.StructuralChecks {
+		ids = append(ids, check.ID)
+	}
+	if strings.Join(ids, ",") != "compiles,undefined-attribute" {
+		t.Errorf("checks = %v, want the disabled analyzer dropped", ids)
+	}
+	if !result.StructuralPassed || result.StructuralChecks[1].Issues[0].Severity != SeverityWarning {
+		t.Errorf("configured severity not applied: %+v", result.StructuralChecks[1])
+	}
+
+	findings := DropDisabled([]Finding{{Rule: "File-Tree"}, {Rule: "db-schema"}}, map[string]bool{"file-tree": true})
+	if len(findings) != 1 || findings[0].Rule != "db-schema" {
+		t.Errorf("findings = %+v", findings)
+	}
+}
+
+func TestApplyIssueConfig(t *testing.T) {
+	issues := []AnalyzerIssue{
+		{Rule: ruleUndefinedAttribute, Severity: SeverityError, File: "a.adoc", Line: 3},
+		{Rule: ruleUnusedAttribute, Severity: SeverityWarning, File: "a.adoc", Line: 1},
+		{Rule: ruleHardcodedValue, Severity: SeverityInfo, File: "b.adoc", Line: 7},
+	}
+
+	got := ApplyIssueConfig(issues, map[string]string{ruleUndefinedAttribute: SeverityInfo}, map[string]bool{ruleUnusedAttribute: true})
+	if len(got) != 2 || got[0].Rule != ruleUndefinedAttribute || got[1].Rule != ruleHardcodedValue {
+		t.Fatalf("issues = %+v, want the disabled rule dropped", got)
+	}
+	if got[0].Severity != SeverityInfo {
+		t.Errorf("configured severity not applied: %+v", got[0])
+	}
+	if issues[0].Severity != SeverityError {
+		t.Errorf("input issues modified: %+v", issues[0])
+	}
+
+	// Info-only issues stay below the default threshold
+	findings := AnalyzerFindings([]StructuralCheck{{Issues: got}})
+	if n := CountAtOrAbove(findings, SeverityError); n != 0 {
+		t.Errorf("CountAtOrAbove(error) = %d, want 0", n)
+	}
+	if n := CountAtOrAbove(findings, SeverityInfo); n != 2 {
+		t.Errorf("CountAtOrAbove(info) = %d, want 2", n)
+	}
+}
+
+func TestFormatRuleExplanation(t *testing.T) {
+	rules, err := ProjectRules(&config.SpecConfig{Rules: config.RulesConfig{
+		Severity: map[string]string{"no-weak-language": "error"},
+		Disabled: []string{"no-weak-language"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, err := FindRuleStatus(rules, "No-Weak-Language")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := FormatRuleExplanation(rule)
+	for _, expect := range []string{
+		"Kind: semantic, severity: error, disabled in .spec.yaml",
+		"✗ Could use connection pooling",
+		"✓ Use connection pooling (max 25 connections)",
+		"    disabled:\n      - no-weak-language",
+	} {
+		if !strings.Contains(out, expect) {
+			t.Errorf("expected explanation to contain %q:\n%s", expect, out)
+		}
+	}
+	if _, err := FindRuleStatus(rules, "nope"); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+}
@@ -0,0 +1,330 @@
+package validator
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+//go:embed schemas/default.yaml
+var defaultSchema []byte
+
+// ruleSpecSchema is the analyzer ID for schema issues
+const ruleSpecSchema = "spec-schema"
+
+var schemaAnalyzer = Analyzer{
+	ID:   ruleSpecSchema,
+	Name: "Required sections and fields",
+	Run:  analyzeSchema,
+}
+
+// SpecSchema declares the sections and labelled fields a spec must contain
+type SpecSchema struct {
+	Sections []SchemaSection       `yaml:"sections"`
+	Order    []string              `yaml:"order"` // Top-level titles that must appear in this relative order
+	Kinds    map[string]SchemaKind `yaml:"kinds"` // Extra requirements per project kind
+	Kind     string                `yaml:"-"`     // Kind applied by ForKind, "" for the base schema
+}
+
+// SchemaKind adds sections and replaces the ordering for one project kind
+type SchemaKind struct {
+	Sections []SchemaSection `yaml:"sections"`
+	Order    []string        `yaml:"order"`
+}
+
+// SchemaSection is a required section, matched by title or alias
+type SchemaSection struct {
+	Title    string          `yaml:"title"`
+	Aliases  []string        `yaml:"aliases"`
+	Fields   []string        `yaml:"fields"`   // Labelled fields such as *Paradigm:*
+	Sections []SchemaSection `yaml:"sections"` // Required subsections
+	Severity string          `yaml:"severity"` // error (default), warning or info
+}
+
+// ExportSchema writes the embedded default schema to path as a starting point for overrides
+func ExportSchema(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err := os.WriteFile(path, defaultSchema, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ParseSpecSchema parses a schema and checks its section titles and severities
+func ParseSpecSchema(data []byte) (*SpecSchema, error) {
+	var schema SpecSchema
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	var check func(sections []SchemaSection) error
+	check = func(sections []SchemaSection) error {
+		for _, section := range sections {
+			if section.Title == "" {
+				return fmt.Errorf("invalid schema: section without a title")
+			}
+			if section.Severity != "" && NormalizeSeverity(section.Severity) == "" {
+				return fmt.Errorf("invalid schema: section %q has unknown severity %q", section.Title, section.Severity)
+			}
+			if err := check(section.Sections); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(schema.Sections); err != nil {
+		return nil, err
+	}
+	for _, kind := range schema.Kinds {
+		if err := check(kind.Sections); err != nil {
+			return nil, err
+		}
+	}
+
+	return &schema, nil
+}
+
+// LoadSpecSchema loads the schema configured in .spec.yaml (or the embedded default)
+// and applies the configured project kind
+func LoadSpecSchema(cfg *config.SpecConfig, baseDir string) (*SpecSchema, error) {
+	data := defaultSchema
+	if cfg.Schema.File != "" {
+		path := cfg.Schema.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+	}
+
+	schema, err := ParseSpecSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	return schema.ForKind(cfg.Schema.Kind)
+}
+
+// ForKind returns the schema with a project kind's sections and ordering applied
+func (s *SpecSchema) ForKind(kind string) (*SpecSchema, error) {
+	if kind == "" {
+		return s, nil
+	}
+
+	extra, ok := s.Kinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema kind %q (available: %s)", kind, strings.Join(s.KindNames(), ", "))
+	}
+
+	resolved := &SpecSchema{
+		Sections: append(append([]SchemaSection{}, s.Sections...), extra.Sections...),
+		Order:    s.Order,
+		Kinds:    s.Kinds,
+		Kind:     kind,
+	}
+	if len(extra.Order) > 0 {
+		resolved.Order = extra.Order
+	}
+	return resolved, nil
+}
+
+// KindNames returns the declared project kinds, sorted
+func (s *SpecSchema) KindNames() []string {
+	names := make([]string, 0, len(s.Kinds))
+	for name := range s.Kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matches reports whether a heading title names this section
+func (s SchemaSection) matches(title string) bool {
+	title = strings.TrimSpace(title)
+	if strings.EqualFold(title, s.Title) {
+		return true
+	}
+	for _, alias := range s.Aliases {
+		if strings.EqualFold(title, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s SchemaSection) severity() string {
+	if s.Severity == "" {
+		return SeverityError
+	}
+	return NormalizeSeverity(s.Severity)
+}
+
+// fieldLabelPattern matches labelled lines: *Paradigm:* x, **Paradigm:** x, *Paradigm*: x, Paradigm:: x
+var fieldLabelPattern = regexp.MustCompile(`^\s*(?:[*-]\s+)?(?:\*{1,2}([^*]+?):\*{1,2}|\*{1,2}([^*]+?)\*{1,2}:|([A-Za-z][\w /-]*?)::(?:\s|$))`)
+
+// sectionFields returns the field labels declared in a section body, lower-cased
+func sectionFields(body []string) map[string]bool {
+	fields := make(map[string]bool)
+	for _, line := range body {
+		m := fieldLabelPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		for _, label := range m[1:] {
+			if label != "" {
+				fields[strings.ToLower(strings.TrimSpace(label))] = true
+			}
+		}
+	}
+	return fields
+}
+
+// analyzeSchema checks the spec outline against the configured schema
+func analyzeSchema(src *SpecSources) []AnalyzerIssue {
+	schema := src.Schema
+	if schema == nil {
+		var err error
+		if schema, err = ParseSpecSchema(defaultSchema); err != nil {
+			return nil
+		}
+	}
+
+	outline, err := parser.BuildOutline(src.ManifestPath)
+	if err != nil {
+		return nil // Unreadable manifests fail the compile check
+	}
+	return checkSchema(schema, outline, src.RelPath(src.ManifestPath), src.RelPath)
+}
+
+// checkSchema reports required sections, fields and ordering missing from an outline
+// Missing top-level sections are reported at line 1 of the manifest
+func checkSchema(schema *SpecSchema, outline []parser.OutlineSection, manifest string, relPath func(string) string) []AnalyzerIssue {
+	var issues []AnalyzerIssue
+
+	// Top-level sections are the shallowest headings below the document title
+	topLevel := 0
+	for _, section := range outline {
+		if section.Level > 0 && (topLevel == 0 || section.Level < topLevel) {
+			topLevel = section.Level
+		}
+	}
+
+	var checkSections func(required []SchemaSection, candidates []int, parent *parser.OutlineSection)
+	checkSections = func(required []SchemaSection, candidates []int, parent *parser.OutlineSection) {
+		for _, req := range required {
+			found := -1
+			for _, i := range candidates {
+				if req.matches(outline[i].Title) {
+					found = i
+					break
+				}
+			}
+
+			if found < 0 {
+				issue := AnalyzerIssue{Rule: ruleSpecSchema, Severity: req.severity(), File: manifest, Line: 1}
+				if parent == nil {
+					issue.Message = fmt.Sprintf("missing required section %q%s", req.Title, aliasHint(req))
+				} else {
+					issue.File, issue.Line = relPath(parent.FilePath), parent.StartLine
+					issue.Message = fmt.Sprintf("%q is missing required subsection %q%s", parent.Title, req.Title, aliasHint(req))
+				}
+				issues = append(issues, issue)
+				continue
+			}
+
+			section := &outline[found]
+			if len(req.Fields) > 0 {
+				fields := sectionFields(section.Body)
+				for _, field := range req.Fields {
+					if !fields[strings.ToLower(field)] {
+						issues = append(issues, AnalyzerIssue{
+							Rule:     ruleSpecSchema,
+							Severity: req.severity(),
+							File:     relPath(section.FilePath),
+							Line:     section.StartLine,
+							Message:  fmt.Sprintf("%q is missing the *%s:* field", section.Title, field),
+						})
+					}
+				}
+			}
+			if len(req.Sections) > 0 {
+				checkSections(req.Sections, descendants(outline, found), section)
+			}
+		}
+	}
+
+	var top []int
+	for i, section := range outline {
+		if section.Level == topLevel {
+			top = append(top, i)
+		}
+	}
+	checkSections(schema.Sections, top, nil)
+
+	// Ordering: each listed section must come after the ones listed before it
+	prevIndex, prevTitle := -1, ""
+	for _, title := range schema.Order {
+		req := findSchemaSection(schema.Sections, title)
+		idx := -1
+		for _, i := range top {
+			if req.matches(outline[i].Title) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			continue // Reported as missing above
+		}
+		if idx < prevIndex {
+			issues = append(issues, AnalyzerIssue{
+				Rule:     ruleSpecSchema,
+				Severity: req.severity(),
+				File:     relPath(outline[idx].FilePath),
+				Line:     outline[idx].StartLine,
+				Message:  fmt.Sprintf("%q must come after %q (expected order: %s)", outline[idx].Title, prevTitle, strings.Join(schema.Order, ", ")),
+			})
+			continue
+		}
+		prevIndex, prevTitle = idx, outline[idx].Title
+	}
+
+	return issues
+}
+
+// descendants returns the indexes of sections nested under outline[parent]
+func descendants(outline []parser.OutlineSection, parent int) []int {
+	var nested []int
+	for i := parent + 1; i < len(outline) && outline[i].Level > outline[parent].Level; i++ {
+		nested = append(nested, i)
+	}
+	return nested
+}
+
+// findSchemaSection looks up a top-level schema section by title, falling back to a bare title
+func findSchemaSection(sections []SchemaSection, title string) SchemaSection {
+	for _, section := range sections {
+		if section.matches(title) {
+			return section
+		}
+	}
+	return SchemaSection{Title: title}
+}
+
+func aliasHint(s SchemaSection) string {
+	if len(s.Aliases) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (or %s)", strings.Join(s.Aliases, ", "))
+}
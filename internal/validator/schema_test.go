@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+func schemaIssues(t *testing.T, manifest string, schema *SpecSchema) []AnalyzerIssue {
+	t.Helper()
+	path := writeSpecFiles(t, map[string]string{"MANIFEST.adoc": manifest})
+	return analyzeSchema(&SpecSources{ManifestPath: path, Schema: schema})
+}
+
+func TestSchemaDefault(t *testing.T) {
+	manifest := `= Orders
+
+== Data Model
+
+== Overview
+
+=== Identity
+
+*Name:* Orders
+
+=== Tech Stack
+
+Language:: Go 1.22
+
+== Testing Requirements
+`
+	issues := schemaIssues(t, manifest, nil)
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Location()+" "+issue.Severity+" "+issue.Message)
+	}
+	want := []string{
+		`MANIFEST.adoc:7 warning "Identity" is missing the *Paradigm:* field`,
+		`MANIFEST.adoc:3 warning "Data Model" must come after "Overview" (expected order: Context, Core Types)`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSchemaMissingSections(t *testing.T) {
+	issues := schemaIssues(t, "= Orders\n\n== Context\n\n=== Identity\n\n*Name:* x\n*Paradigm:* y\n", nil)
+
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.Location()+" "+issue.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{
+		`MANIFEST.adoc:3 "Context" is missing required subsection "Stack"`,
+		`MANIFEST.adoc:1 missing required section "Core Types"`,
+		`MANIFEST.adoc:1 missing required section "Testing"`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in:\n%s", want, joined)
+		}
+	}
+}
+
+func TestSchemaDefault_NoCoreTypesPassesQuick(t *testing.T) {
+	content := "= Orders\n\n== Context\n\n=== Identity\n\n*Name:* Orders\n*Paradigm:* service\n\n=== Stack\n\n*Language:* Go 1.22\n\n== Testing\n\nUnit tests cover every handler.\n"
+	checks := runAnalyzers(loadSpecFiles(t, map[string]string{"MANIFEST.adoc": content}))
+
+	failOn, _ := ParseFailOn("")
+	findings := AnalyzerFindings(checks)
+	if !AllStructuralChecksPassed(checks) || CountAtOrAbove(findings, failOn) > 0 {
+		t.Errorf("spec without Core Types should pass --fail-on %s: %+v", failOn, findings)
+	}
+	if len(findings) != 1 || findings[0].Severity != SeverityWarning || !strings.Contains(findings[0].Issue, `"Core Types"`) {
+		t.Errorf("expected one Core Types warning, got %+v", findings)
+	}
+}
+
+func TestLoadSpecSchemaKinds(t *testing.T) {
+	schema, err := LoadSpecSchema(&config.SpecConfig{Schema: config.SchemaConfig{Kind: "cli"}}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Kind != "cli" || findSchemaSection(schema.Sections, "Command Reference").Title != "Commands" {
+		t.Errorf("cli sections not applied: %+v", schema.Sections)
+	}
+	if strings.Join(schema.Order, ",") != "Context,Core Types,Commands" {
+		t.Errorf("order = %v", schema.Order)
+	}
+
+	if _, err := LoadSpecSchema(&config.SpecConfig{Schema: config.SchemaConfig{Kind: "game"}}, "."); err == nil || !strings.Contains(err.Error(), "cli, library, service") {
+		t.Errorf("expected unknown kind error, got %v", err)
+	}
+
+	dir := filepath.Dir(writeSpecFiles(t, map[string]string{"schema.yaml": "sections:\n  - title: Glossary\n    severity: warning\n"}))
+	schema, err = LoadSpecSchema(&config.SpecConfig{Schema: config.SchemaConfig{File: "schema.yaml"}}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outline := []parser.OutlineSection{{SectionInfo: parser.SectionInfo{Title: "Orders", Level: 0}}}
+	issues := checkSchema(schema, outline, "MANIFEST.adoc", func(p string) string { return p })
+	if len(issues) != 1 || issues[0].Severity != SeverityWarning {
+		t.Errorf("custom schema issues = %+v", issues)
+	}
+
+	if _, err := ParseSpecSchema([]byte("sections:\n  - title: X\n    severity: fatal\n")); err == nil {
+		t.Error("expected invalid severity error")
+	}
+}
+
+func TestSectionFields(t *testing.T) {
+	fields := sectionFields([]string{"*Name:* Orders", "**Paradigm:** REST", "*Language*: Go", "Runtime:: Linux", "* bullet: no"})
+	for _, want := range []string{"name", "paradigm", "language", "runtime"} {
+		if !fields[want] {
+			t.Errorf("field %q not found in %v", want, fields)
+		}
+	}
+	if len(fields) != 4 {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+}
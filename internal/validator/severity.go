@@ -0,0 +1,170 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+// Finding severities, most severe first
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// FailOnNone disables failing on semantic findings
+const FailOnNone = "none"
+
+// Severity words providers tend to use instead of the requested ones
+var severityAliases = map[string]string{
+	"critical": SeverityError,
+	"blocker":  SeverityError,
+	"high":     SeverityError,
+	"major":    SeverityError,
+	"medium":   SeverityWarning,
+	"minor":    SeverityWarning,
+	"low":      SeverityInfo,
+	"note":     SeverityInfo,
+}
+
+// NormalizeSeverity maps a severity (or a common synonym) to error, warning or info
+// Returns "" for anything unrecognized
+func NormalizeSeverity(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return s
+	}
+	return severityAliases[s]
+}
+
+// severityRank orders severities: error 3, warning 2, info 1, unknown 0
+func severityRank(s string) int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	}
+	return 0
+}
+
+// normalizeRuleID is the form rule IDs are compared in: trimmed and lowercase
+func normalizeRuleID(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
+
+// RuleSeverities collects configured severities from .spec.yaml (rules.severity
+// and custom rule severity), validating every value
+// Rule IDs are normalized, so a severity applies however the ID is cased.
+func RuleSeverities(cfg *config.SpecConfig) (map[string]string, error) {
+	severities := make(map[string]string)
+	set := func(rule, value string) error {
+		severity := NormalizeSeverity(value)
+		if severity == "" {
+			return fmt.Errorf("invalid severity %q for rule %s (use error, warning or info)", value, rule)
+		}
+		severities[normalizeRuleID(rule)] = severity
+		return nil
+	}
+
+	for _, rule := range cfg.Rules.Custom {
+		if rule.Severity == "" {
+			continue
+		}
+		if err := set(rule.ID, rule.Severity); err != nil {
+			return nil, err
+		}
+	}
+	for rule, value := range cfg.Rules.Severity {
+		if err := set(rule, value); err != nil {
+			return nil, err
+		}
+	}
+	return severities, nil
+}
+
+// ApplySeverities sets each finding's severity: a configured severity for the rule wins,
+// then the one the provider assigned, then the rule's default
+func ApplySeverities(findings []Finding, configured map[string]string) {
+	for i := range findings {
+		rule := normalizeRuleID(findings[i].Rule)
+		if severity, ok := configured[rule]; ok {
+			findings[i].Severity = severity
+			continue
+		}
+		if severity := NormalizeSeverity(findings[i].Severity); severity != "" {
+			findings[i].Severity = severity
+			continue
+		}
+		findings[i].Severity = DefaultSeverity(rule)
+	}
+}
+
+// DefaultSeverity returns the built-in severity of a rule; unknown rules default to warning
+func DefaultSeverity(rule string) string {
+	if r, ok := LookupRule(rule); ok {
+		return r.Severity
+	}
+	return SeverityWarning
+}
+
+// ParseFailOn validates a --fail-on value ("" means error)
+func ParseFailOn(value string) (string, error) {
+	if value == "" {
+		return SeverityError, nil
+	}
+	value = strings.ToLower(value)
+	switch value {
+	case SeverityError, SeverityWarning, SeverityInfo, FailOnNone:
+		return value, nil
+	}
+	return "", fmt.Errorf("invalid fail-on %q (use error, warning, info or none)", value)
+}
+
+// CountAtOrAbove counts findings at least as severe as min
+// Nothing counts when min is "none"
+func CountAtOrAbove(findings []Finding, min string) int {
+	threshold := severityRank(min)
+	if threshold == 0 {
+		return 0
+	}
+	count := 0
+	for _, f := range findings {
+		if severityRank(f.Severity) >= threshold {
+			count++
+		}
+	}
+	return count
+}
+
+// FormatSeverityCounts summarizes findings by severity, e.g. "2 error, 1 warning"
+func FormatSeverityCounts(findings []Finding) string {
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+
+	var parts []string
+	for _, severity := range []string{SeverityError, SeverityWarning, SeverityInfo} {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	if len(parts) == 0 {
+		return "no findings"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// moreSevere returns the more severe of two severities
+func moreSevere(a, b string) string {
+	if severityRank(NormalizeSeverity(b)) > severityRank(NormalizeSeverity(a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func TestApplySeverities(t *testing.T) {
+	findings := ParseFindings(`- Rule: exact-versions
+- Location: Stack
+- Issue: Redis has no version
+
+- Rule: no-weak-language
+- Severity: high
+- Location: API
+- Issue: "should retry"
+
+- Rule: queue-dlq
+- Severity: info
+- Location: Queues
+- Issue: No DLQ
+`)
+
+	ApplySeverities(findings, map[string]string{"queue-dlq": SeverityError})
+
+	want := []string{SeverityError, SeverityError, SeverityError}
+	for i, f := range findings {
+		if f.Severity != want[i] {
+			t.Errorf("%s: severity = %q, want %q", f.Rule, f.Severity, want[i])
+		}
+	}
+}
+
+func TestCountAtOrAbove(t *testing.T) {
+	findings := []Finding{
+		{Rule: "a", Severity: SeverityError},
+		{Rule: "b", Severity: SeverityWarning},
+		{Rule: "c", Severity: SeverityInfo},
+	}
+
+	tests := map[string]int{
+		SeverityError:   1,
+		SeverityWarning: 2,
+		SeverityInfo:    3,
+		FailOnNone:      0,
+	}
+	for failOn, want := range tests {
+		if got := CountAtOrAbove(findings, failOn); got != want {
+			t.Errorf("CountAtOrAbove(%s) = %d, want %d", failOn, got, want)
+		}
+	}
+}
+
+func TestRuleSeverities_Invalid(t *testing.T) {
+	cfg := &config.SpecConfig{Rules: config.RulesConfig{
+		Severity: map[string]string{"no-weak-language": "fatal"},
+	}}
+	if _, err := RuleSeverities(cfg); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
+
+func TestRuleSeverities_MixedCaseID(t *testing.T) {
+	cfg := &config.SpecConfig{Rules: config.RulesConfig{
+		Custom:   []config.CustomRule{{ID: "PII-Logging", Description: "No PII in logs", Severity: "error"}},
+		Severity: map[string]string{"No-Weak-Language": "info"},
+	}}
+	severities, err := RuleSeverities(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []Finding{{Rule: "PII-Logging", Severity: SeverityInfo}, {Rule: "no-weak-language"}}
+	ApplySeverities(findings, severities)
+	if findings[0].Severity != SeverityError || findings[1].Severity != SeverityInfo {
+		t.Errorf("severities = %q, %q; want error, info", findings[0].Severity, findings[1].Severity)
+	}
+}
@@ -0,0 +1,169 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/compiler"
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+// SpecSlice is the part of a spec validated with --section or --file
+// Native rules report only issues inside it; the model sees it with the Context section
+// and is told the rest of the spec exists but is out of scope
+type SpecSlice struct {
+	Target   string   // e.g. section "API Specification" or file api.adoc
+	Sections []string // Titles of the sections in the slice
+	Others   []string // Top-level titles of the rest of the spec
+	Context  string   // Compiled Context section, set by Validate for the prompt
+
+	section string               // --section query, "" for a file slice
+	file    string               // Source file of a file slice
+	ranges  map[string]lineRange // Source lines in the slice, by path relative to the manifest directory
+}
+
+// lineRange is an inclusive range of source lines; end is 0 for the end of the file
+type lineRange struct {
+	start, end int
+}
+
+// ResolveSlice finds the source lines covered by a section query or a spec file
+// The section is matched like compile --section and covers its subsections and the files
+// they include; a file covers itself and the files it includes
+func ResolveSlice(manifestPath, section, file string) (*SpecSlice, error) {
+	src, err := LoadSpecSources(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	structure := src.Structure
+	slice := &SpecSlice{section: section, ranges: make(map[string]lineRange)}
+
+	var root string
+	var rootRange lineRange
+	if section != "" {
+		found := parser.FindSection(structure, section)
+		if found == nil {
+			return nil, fmt.Errorf("section not found: %s (see cca list)", section)
+		}
+		extent := compiler.WithSubsections(structure, *found)
+		root, rootRange = extent.FilePath, lineRange{start: extent.StartLine, end: max(extent.EndLine, 0)}
+		slice.Target = fmt.Sprintf("section %q", found.Title)
+	} else {
+		if root, err = specFile(src, file); err != nil {
+			return nil, err
+		}
+		rootRange = lineRange{start: 1}
+		slice.file = root
+		slice.Target = "file " + src.RelPath(root)
+	}
+
+	// The root range, then every file included from it
+	slice.ranges[src.RelPath(root)] = rootRange
+	for _, inc := range parser.ExtractIncludes(src.Content[root]) {
+		if inc.Line < rootRange.start || (rootRange.end > 0 && inc.Line > rootRange.end) {
+			continue
+		}
+		child := parser.ResolveIncludePath(filepath.Dir(root), inc.Path)
+		slice.ranges[src.RelPath(child)] = lineRange{start: 1}
+		nested, _ := parser.GetIncludedFiles(child)
+		for _, path := range nested {
+			slice.ranges[src.RelPath(path)] = lineRange{start: 1}
+		}
+	}
+
+	outline, err := parser.BuildOutline(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec outline: %w", err)
+	}
+	for _, s := range outline {
+		switch {
+		case slice.Contains(src.RelPath(s.FilePath), s.StartLine):
+			slice.Sections = append(slice.Sections, s.Title)
+		case s.Level == 1:
+			slice.Others = append(slice.Others, s.Title)
+		}
+	}
+	return slice, nil
+}
+
+// specFile resolves a --file path against the working directory, then the manifest
+// directory, and checks that it belongs to the spec
+func specFile(src *SpecSources, file string) (string, error) {
+	var candidates []string
+	if abs, err := filepath.Abs(file); err == nil {
+		candidates = append(candidates, abs)
+	}
+	if !filepath.IsAbs(file) {
+		if abs, err := filepath.Abs(filepath.Join(filepath.Dir(src.ManifestPath), file)); err == nil {
+			candidates = append(candidates, abs)
+		}
+	}
+
+	for _, candidate := range candidates {
+		for path := range src.Content {
+			if abs, err := filepath.Abs(path); err == nil && abs == candidate {
+				return path, nil
+			}
+		}
+	}
+
+	var files []string
+	for path := range src.Content {
+		files = append(files, "  - "+src.RelPath(path))
+	}
+	sort.Strings(files)
+	if _, err := os.Stat(file); err != nil {
+		return "", fmt.Errorf("file not found: %s", file)
+	}
+	return "", fmt.Errorf("%s is not part of the spec\nSpec files:\n%s", file, strings.Join(files, "\n"))
+}
+
+// Contains reports whether a source line, by path relative to the manifest directory, is in the slice
+func (s *SpecSlice) Contains(file string, line int) bool {
+	r, ok := s.ranges[file]
+	return ok && line >= r.start && (r.end == 0 || line <= r.end)
+}
+
+// Compile compiles the slice to Markdown with the manifest's attributes resolved
+func (s *SpecSlice) Compile(manifestPath string) (string, error) {
+	if s.section != "" {
+		return compiler.CompileSection(manifestPath, s.section)
+	}
+	return compiler.CompileFile(manifestPath, s.file)
+}
+
+// Scope drops native analyzer issues outside the slice and re-evaluates the structural result
+// Checks without located issues, such as compiles, still cover the whole spec
+func (s *SpecSlice) Scope(result *ValidationResult) {
+	analyzers := make(map[string]bool)
+	for _, analyzer := range Analyzers() {
+		analyzers[analyzer.ID] = true
+	}
+
+	for i, check := range result.StructuralChecks {
+		if !analyzers[check.ID] {
+			continue
+		}
+		var issues []AnalyzerIssue
+		for _, issue := range check.Issues {
+			if s.Contains(issue.File, issue.Line) {
+				issues = append(issues, issue)
+			}
+		}
+		result.StructuralChecks[i] = analyzerCheck(Analyzer{ID: check.ID, Name: check.Name}, issues)
+	}
+	result.StructuralPassed = AllStructuralChecksPassed(result.StructuralChecks)
+}
+
+// contextSection extracts the compiled Context section, with its subsections, from the full spec
+func contextSection(compiledSpec string) string {
+	sections := compiler.SplitMarkdownSections(compiledSpec)
+	var sb strings.Builder
+	for _, i := range contextSectionIndexes(sections) {
+		sb.WriteString(sections[i].Content)
+	}
+	return strings.TrimSpace(sb.String())
+}
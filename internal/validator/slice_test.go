@@ -0,0 +1,143 @@
+package validator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSliceSpec(t *testing.T) string {
+	t.Helper()
+	return writeSpecFiles(t, map[string]string{
+		"MANIFEST.adoc": `= Orders
+:unused-knob: 3
+
+== Context
+
+Order service.
+
+include::api.adoc[]
+
+== Storage
+
+Uses {undefined-store}.
+
+include::storage/tables.adoc[]
+`,
+		"api.adoc": `== API
+
+Returns {missing-attr}.
+
+=== Errors
+
+Errors use {other-missing}.
+
+include::api/shapes.adoc[]
+`,
+		"api/shapes.adoc": `Shapes reference {shape-missing}.
+`,
+		"storage/tables.adoc": `=== Tables
+
+Tables reference {table-missing}.
+`,
+	})
+}
+
+func TestResolveSlice(t *testing.T) {
+	manifest := writeSliceSpec(t)
+
+	tests := []struct {
+		name, section, file string
+		target, sections    string
+		others              string
+	}{
+		{"section with subsections", "storage", "", `section "Storage"`, "Storage, Tables", "Context, API"},
+		{"file with includes", "", filepath.Join(filepath.Dir(manifest), "api.adoc"), "file api.adoc", "API, Errors", "Context, Storage"},
+		{"file relative to the manifest", "", "storage/tables.adoc", "file storage/tables.adoc", "Tables", "Context, API, Storage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slice, err := ResolveSlice(manifest, tt.section, tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if slice.Target != tt.target {
+				t.Errorf("target = %q, want %q", slice.Target, tt.target)
+			}
+			if got := strings.Join(slice.Sections, ", "); got != tt.sections {
+				t.Errorf("sections = %q, want %q", got, tt.sections)
+			}
+			if got := strings.Join(slice.Others, ", "); got != tt.others {
+				t.Errorf("others = %q, want %q", got, tt.others)
+			}
+		})
+	}
+
+	if _, err := ResolveSlice(manifest, "", "nope.adoc"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing file: %v", err)
+	}
+	if _, err := ResolveSlice(manifest, "Billing", ""); err == nil {
+		t.Error("expected an error for an unknown section")
+	}
+}
+
+func TestSpecSliceScope(t *testing.T) {
+	manifest := writeSliceSpec(t)
+	src, err := LoadSpecSources(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ValidationResult{StructuralChecks: append([]StructuralCheck{{ID: "compiles", Passed: true}}, runAnalyzers(src)...)}
+
+	slice, err := ResolveSlice(manifest, "API", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slice.Scope(result)
+
+	var got []string
+	for _, check := range result.StructuralChecks {
+		for _, issue := range check.Issues {
+			got = append(got, issue.Location()+" "+issue.Rule)
+		}
+	}
+	want := []string{
+		"api.adoc:3 undefined-attribute",
+		"api.adoc:7 undefined-attribute",
+		"api/shapes.adoc:1 undefined-attribute",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("scoped issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !result.StructuralPassed {
+		t.Error("analyzer issues should not fail the structural phase")
+	}
+	if n := CountAtOrAbove(AnalyzerFindings(result.StructuralChecks), SeverityError); n != 3 {
+		t.Errorf("%d error findings in the slice, want 3", n)
+	}
+	if result.StructuralChecks[0].ID != "compiles" || !result.StructuralChecks[0].Passed {
+		t.Error("whole-spec checks should be kept as they are")
+	}
+}
+
+func TestRender_Slice(t *testing.T) {
+	opts := ValidationOptions{Slice: &SpecSlice{
+		Target:  `section "Storage"`,
+		Others:  []string{"Context", "API"},
+		Context: "## Context\n\nOrder service.",
+	}}
+	prompt, err := opts.Prompts.Render("validate", opts.validateData("## Storage"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{
+		`You are reviewing only section "Storage" of a larger specification. The rest of the spec is out of scope; it also contains these sections: Context, API.`,
+		"Do not report sections, types, routes, dependencies or examples as missing",
+		"## Shared Context",
+		"Order service.",
+	} {
+		if !strings.Contains(prompt, expect) {
+			t.Errorf("expected prompt to contain %q", expect)
+		}
+	}
+}
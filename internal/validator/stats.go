@@ -0,0 +1,337 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/compiler"
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+// File size guidelines from docs/structure.md
+const (
+	fileNavigableLines = 500   // Beyond this a file is hard to navigate; split by concern
+	fileReadLineLimit  = 2000  // Lines an AI file read returns in one call
+	fileReadTokenLimit = 25000 // Tokens an AI file read returns before truncating
+)
+
+// File size statuses
+const (
+	SizeOK       = "ok"
+	SizeLarge    = "large"     // Over fileNavigableLines
+	SizeOverRead = "over-read" // Over an AI read limit
+)
+
+// SpecStats summarizes where a spec's bulk lives and how many native findings each part has
+type SpecStats struct {
+	Files      int            `json:"files"`
+	Sections   map[int]int    `json:"sections"` // Section count by level, 1 for ==
+	Words      int            `json:"words"`
+	Attributes int            `json:"attributes"`
+	Blocks     map[string]int `json:"blocks"` // Source blocks by language, "none" when unlabelled
+	Tables     int            `json:"tables"`
+	Tokens     int            `json:"tokens"`   // Approximate, of the compiled spec when Compiled
+	Compiled   bool           `json:"compiled"` // Token counts come from the compiled Markdown, not the sources
+	Issues     int            `json:"issues"`   // Native analyzer issues
+
+	TopSections []SectionStats `json:"top_sections"`
+	FileStats   []FileStats    `json:"file_stats"`
+}
+
+// SectionStats measures a top-level section together with its subsections
+type SectionStats struct {
+	Title   string  `json:"title"`
+	File    string  `json:"file"`
+	Line    int     `json:"line"`
+	Words   int     `json:"words"`
+	Tokens  int     `json:"tokens"`
+	Issues  int     `json:"issues"`
+	Density float64 `json:"density"` // Issues per 1000 words
+}
+
+// FileStats measures one source file against the size guidelines
+type FileStats struct {
+	Path   string `json:"path"` // Relative to the manifest directory
+	Lines  int    `json:"lines"`
+	Words  int    `json:"words"`
+	Tokens int    `json:"tokens"` // Of the source, attribute references resolved
+	Size   string `json:"size"`   // ok, large or over-read
+	Issues int    `json:"issues"`
+}
+
+// preambleTitle names the text between the document title and the first section
+const preambleTitle = "(preamble)"
+
+// BuildStats measures the spec and attributes native analyzer issues to sections and files
+// compiled is the spec compiled to Markdown, or "" to estimate tokens from the sources
+func BuildStats(src *SpecSources, compiled string) (*SpecStats, error) {
+	outline, err := parser.BuildOutline(src.ManifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec outline: %w", err)
+	}
+	resolve := attributeReplacer(src.Attributes)
+
+	stats := &SpecStats{
+		Files:    len(src.Documents),
+		Sections: make(map[int]int),
+		Blocks:   make(map[string]int),
+		Compiled: compiled != "",
+	}
+	for name := range src.Attributes {
+		if !builtinAttributes[name] {
+			stats.Attributes++
+		}
+	}
+
+	var issues []AnalyzerIssue
+	for _, analyzer := range Analyzers() {
+		issues = append(issues, analyzer.Run(src)...)
+	}
+	stats.Issues = len(issues)
+
+	fileIssues := make(map[string]int)
+	for _, issue := range issues {
+		fileIssues[issue.File]++
+	}
+	for _, doc := range src.Documents {
+		content := src.Content[doc.FilePath]
+		file := FileStats{
+			Path:   src.RelPath(doc.FilePath),
+			Lines:  strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1,
+			Words:  len(strings.Fields(content)),
+			Tokens: EstimateTokens(resolve.Replace(content)),
+			Issues: fileIssues[src.RelPath(doc.FilePath)],
+		}
+		file.Size = fileSize(file)
+		stats.FileStats = append(stats.FileStats, file)
+		stats.Words += file.Words
+
+		for _, block := range doc.Blocks {
+			lang := strings.ToLower(block.Language)
+			if lang == "" {
+				lang = "none"
+			}
+			stats.Blocks[lang]++
+		}
+		stats.Tables += len(doc.Tables)
+	}
+
+	for _, section := range outline {
+		if section.Level > 0 {
+			stats.Sections[section.Level]++
+		}
+	}
+
+	stats.TopSections = topSectionStats(src, outline, issues, resolve)
+	if compiled != "" {
+		stats.Tokens = EstimateTokens(compiled)
+		applyCompiledTokens(stats.TopSections, compiled)
+	} else {
+		for _, file := range stats.FileStats {
+			stats.Tokens += file.Tokens
+		}
+	}
+	return stats, nil
+}
+
+// topSectionStats measures each level-1 section with its subsections; text under the
+// document title before the first section is reported as the preamble
+func topSectionStats(src *SpecSources, outline []parser.OutlineSection, issues []AnalyzerIssue, resolve *strings.Replacer) []SectionStats {
+	var sections []SectionStats
+	owner := make([]int, len(outline)) // Index into sections for each outline entry
+	for i, section := range outline {
+		switch {
+		case section.Level <= 0:
+			sections = append(sections, SectionStats{Title: preambleTitle, File: src.RelPath(section.FilePath), Line: section.StartLine})
+		case section.Level == 1 || len(sections) == 0:
+			sections = append(sections, SectionStats{Title: section.Title, File: src.RelPath(section.FilePath), Line: section.StartLine})
+		}
+		owner[i] = len(sections) - 1
+
+		text := resolve.Replace(strings.Join(section.Body, "\n"))
+		if section.Level > 0 {
+			text = section.Title + "\n" + text
+		}
+		sections[owner[i]].Words += len(strings.Fields(text))
+		sections[owner[i]].Tokens += EstimateTokens(text)
+	}
+
+	for _, issue := range issues {
+		if i := sectionAt(src, outline, issue); i >= 0 {
+			sections[owner[i]].Issues++
+		}
+	}
+
+	// Keep the preamble only when there is something in it
+	var kept []SectionStats
+	for _, s := range sections {
+		if s.Title == preambleTitle && s.Words == 0 && s.Issues == 0 {
+			continue
+		}
+		if s.Words > 0 {
+			s.Density = float64(s.Issues) * 1000 / float64(s.Words)
+		}
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+// sectionAt returns the outline entry whose heading most closely precedes the issue in its file,
+// or -1 when the file has no headings
+func sectionAt(src *SpecSources, outline []parser.OutlineSection, issue AnalyzerIssue) int {
+	found := -1
+	for i, section := range outline {
+		if src.RelPath(section.FilePath) == issue.File && section.StartLine <= issue.Line &&
+			(found < 0 || section.StartLine > outline[found].StartLine) {
+			found = i
+		}
+	}
+	if found >= 0 {
+		return found
+	}
+
+	// Above the file's first heading: the section before it, which includes the file
+	for i, section := range outline {
+		if src.RelPath(section.FilePath) == issue.File {
+			return max(i-1, 0)
+		}
+	}
+	return -1
+}
+
+// applyCompiledTokens replaces source estimates with counts from the compiled Markdown
+// Level-1 AsciiDoc sections are ## headings once compiled; text before the first is the preamble
+func applyCompiledTokens(sections []SectionStats, compiled string) {
+	tokens := make(map[string]int)
+	current := preambleTitle
+	for _, md := range compiler.SplitMarkdownSections(compiled) {
+		if md.Level == 2 {
+			current = md.Title
+		}
+		tokens[current] += EstimateTokens(md.Content)
+	}
+
+	for i := range sections {
+		if n, ok := tokens[sections[i].Title]; ok {
+			sections[i].Tokens = n
+		}
+	}
+}
+
+// fileSize checks a file against the size guidelines
+func fileSize(file FileStats) string {
+	switch {
+	case file.Lines > fileReadLineLimit || file.Tokens > fileReadTokenLimit:
+		return SizeOverRead
+	case file.Lines > fileNavigableLines:
+		return SizeLarge
+	}
+	return SizeOK
+}
+
+// FormatStats renders the summary, block and table counts, then the per-section and per-file tables
+func FormatStats(stats *SpecStats) string {
+	var sb strings.Builder
+
+	var levels []string
+	for level := 1; len(levels) < len(stats.Sections); level++ {
+		if n, ok := stats.Sections[level]; ok {
+			levels = append(levels, fmt.Sprintf("%s %d", strings.Repeat("=", level+1), n))
+		}
+	}
+	sections := 0
+	for _, n := range stats.Sections {
+		sections += n
+	}
+	tokenSource := "sources"
+	if stats.Compiled {
+		tokenSource = "compiled"
+	}
+
+	fmt.Fprintf(&sb, "Files:       %d\n", stats.Files)
+	fmt.Fprintf(&sb, "Sections:    %d", sections)
+	if len(levels) > 0 {
+		fmt.Fprintf(&sb, " (%s)", strings.Join(levels, ", "))
+	}
+	fmt.Fprintf(&sb, "\nWords:       %d\n", stats.Words)
+	fmt.Fprintf(&sb, "Attributes:  %d\n", stats.Attributes)
+	fmt.Fprintf(&sb, "Tables:      %d\n", stats.Tables)
+	fmt.Fprintf(&sb, "Tokens:      ~%d (%s)\n", stats.Tokens, tokenSource)
+	fmt.Fprintf(&sb, "Issues:      %d native\n", stats.Issues)
+
+	if len(stats.Blocks) > 0 {
+		langs := make([]string, 0, len(stats.Blocks))
+		for lang := range stats.Blocks {
+			langs = append(langs, lang)
+		}
+		sort.Slice(langs, func(i, j int) bool {
+			if stats.Blocks[langs[i]] != stats.Blocks[langs[j]] {
+				return stats.Blocks[langs[i]] > stats.Blocks[langs[j]]
+			}
+			return langs[i] < langs[j]
+		})
+		var parts []string
+		for _, lang := range langs {
+			parts = append(parts, fmt.Sprintf("%s %d", lang, stats.Blocks[lang]))
+		}
+		fmt.Fprintf(&sb, "Blocks:      %s\n", strings.Join(parts, ", "))
+	}
+
+	if len(stats.TopSections) > 0 {
+		sb.WriteString("\n")
+		rows := [][]string{{"SECTION", "LOCATION", "WORDS", "TOKENS", "SHARE", "ISSUES", "PER 1K WORDS"}}
+		total := 0
+		for _, s := range stats.TopSections {
+			total += s.Tokens
+		}
+		for _, s := range stats.TopSections {
+			share := "-"
+			if total > 0 {
+				share = fmt.Sprintf("%.0f%%", float64(s.Tokens)*100/float64(total))
+			}
+			rows = append(rows, []string{s.Title, fmt.Sprintf("%s:%d", s.File, s.Line), fmt.Sprint(s.Words),
+				fmt.Sprint(s.Tokens), share, fmt.Sprint(s.Issues), fmt.Sprintf("%.1f", s.Density)})
+		}
+		sb.WriteString(formatColumns(rows))
+	}
+
+	sb.WriteString("\n")
+	rows := [][]string{{"FILE", "LINES", "WORDS", "TOKENS", "ISSUES", "SIZE"}}
+	var oversized []FileStats
+	for _, f := range stats.FileStats {
+		rows = append(rows, []string{f.Path, fmt.Sprint(f.Lines), fmt.Sprint(f.Words), fmt.Sprint(f.Tokens), fmt.Sprint(f.Issues), f.Size})
+		if f.Size != SizeOK {
+			oversized = append(oversized, f)
+		}
+	}
+	sb.WriteString(formatColumns(rows))
+
+	if len(oversized) == 0 {
+		fmt.Fprintf(&sb, "\n\033[32m✓\033[0m All files within size guidelines (%d lines)\n", fileNavigableLines)
+		return sb.String()
+	}
+	sb.WriteString("\nSize guidelines (docs/structure.md):\n")
+	for _, f := range oversized {
+		if f.Size == SizeOverRead {
+			fmt.Fprintf(&sb, "  \033[31m✗\033[0m %s: %d lines, ~%d tokens - over an AI read limit (%d lines or %d tokens), split it\n",
+				f.Path, f.Lines, f.Tokens, fileReadLineLimit, fileReadTokenLimit)
+		} else {
+			fmt.Fprintf(&sb, "  \033[33m!\033[0m %s: %d lines - over %d, consider splitting by concern\n", f.Path, f.Lines, fileNavigableLines)
+		}
+	}
+	return sb.String()
+}
+
+// FormatStatsJSON renders the stats as JSON
+func FormatStatsJSON(stats *SpecStats) string {
+	if stats.TopSections == nil {
+		stats.TopSections = []SectionStats{}
+	}
+	if stats.FileStats == nil {
+		stats.FileStats = []FileStats{}
+	}
+	data, _ := json.MarshalIndent(stats, "", "  ")
+	return string(data)
+}
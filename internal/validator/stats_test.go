@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildStats(t *testing.T) {
+	manifest := `= Service
+:max-conns: 40
+
+Overview of the service.
+
+== Context
+
+Built for {max-conns} connections.
+
+include::api.adoc[leveloffset=+1]
+`
+	api := `= API
+
+[source,go]
+----
+type Order struct {
+    Lines []LineItem
+}
+----
+
+== Errors
+
+|===
+| Code | Meaning
+| 404 | Not found
+|===
+
+` + strings.Repeat("Each endpoint returns JSON.\n", 600)
+
+	src := loadSpecFiles(t, map[string]string{"MANIFEST.adoc": manifest, "api.adoc": api})
+
+	stats, err := BuildStats(src, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Files != 2 || stats.Attributes != 1 || stats.Tables != 1 || stats.Blocks["go"] != 1 {
+		t.Errorf("files %d, attributes %d, tables %d, blocks %v", stats.Files, stats.Attributes, stats.Tables, stats.Blocks)
+	}
+	if stats.Sections[1] != 2 || stats.Sections[2] != 1 {
+		t.Errorf("sections by level = %v, want 2 at level 1 and 1 at level 2", stats.Sections)
+	}
+
+	var titles []string
+	for _, s := range stats.TopSections {
+		titles = append(titles, s.Title)
+	}
+	if strings.Join(titles, ", ") != "(preamble), Context, API" {
+		t.Fatalf("top sections = %v", titles)
+	}
+	// The API section includes its == Errors subsection and the undefined LineItem type
+	apiSection := stats.TopSections[2]
+	if apiSection.Words < 2400 || apiSection.Issues == 0 || apiSection.File != "api.adoc" {
+		t.Errorf("API section = %+v", apiSection)
+	}
+	// Missing schema sections and the unexplained {max-conns} are reported in the document header
+	if stats.TopSections[0].Issues == 0 {
+		t.Error("preamble has no issues")
+	}
+
+	sizes := map[string]string{}
+	for _, f := range stats.FileStats {
+		sizes[f.Path] = f.Size
+	}
+	if sizes["MANIFEST.adoc"] != SizeOK || sizes["api.adoc"] != SizeLarge {
+		t.Errorf("sizes = %v", sizes)
+	}
+	if out := FormatStats(stats); !strings.Contains(out, "api.adoc: 616 lines - over 500, consider splitting by concern") {
+		t.Errorf("size guideline missing:\n%s", out)
+	}
+}
+
+func TestApplyCompiledTokens(t *testing.T) {
+	sections := []SectionStats{{Title: "(preamble)"}, {Title: "Context", Tokens: 1}, {Title: "Missing", Tokens: 7}}
+	compiled := "# Service\n\nIntro.\n\n## Context\n\nSome context here.\n\n### Goals\n\nMore.\n"
+	applyCompiledTokens(sections, compiled)
+	if sections[0].Tokens == 0 || sections[1].Tokens <= EstimateTokens("## Context\n\nSome context here.\n\n") || sections[2].Tokens != 7 {
+		t.Errorf("tokens = %+v", sections)
+	}
+}
@@ -0,0 +1,210 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Frames of the progress indicator on the status line
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+type streamKey struct{}
+
+// withStream asks providers to write reply text to w as it arrives
+// Providers that cannot stream write the whole reply once it is complete
+func withStream(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, streamKey{}, w)
+}
+
+// streamOf returns the writer replies are streamed to, nil when the caller buffers them
+func streamOf(ctx context.Context) io.Writer {
+	w, _ := ctx.Value(streamKey{}).(io.Writer)
+	return w
+}
+
+// statusLine redraws a one-line status on stderr every 100ms
+// It does nothing when stderr is not a terminal.
+type statusLine struct {
+	render func(frame string) string
+
+	mu     sync.Mutex
+	frame  int
+	shown  bool
+	paused bool // Streamed text is mid-line; the status waits for the next line start
+	done   chan struct{}
+	ended  chan struct{}
+}
+
+// startStatus draws render's status until Stop is called or ctx is cancelled
+func startStatus(ctx context.Context, render func(frame string) string) *statusLine {
+	s := &statusLine{render: render}
+	if !isTTY() {
+		return s
+	}
+
+	s.done, s.ended = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(s.ended)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			s.mu.Lock()
+			s.draw()
+			s.frame++
+			s.mu.Unlock()
+			select {
+			case <-s.done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Stop clears the status line
+func (s *statusLine) Stop() {
+	if s.done == nil {
+		return
+	}
+	close(s.done)
+	<-s.ended
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clear()
+}
+
+// draw writes the status over the current line; callers hold s.mu
+func (s *statusLine) draw() {
+	if s.done == nil || s.paused {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\r\033[K%s", s.render(spinnerFrames[s.frame%len(spinnerFrames)]))
+	s.shown = true
+}
+
+// clear erases the status so other output can take its line; callers hold s.mu
+func (s *statusLine) clear() {
+	if s.shown {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+		s.shown = false
+	}
+}
+
+// startSpinner shows a spinner on stderr until the returned stop function is called
+// or ctx is cancelled. Does nothing if stderr is not a terminal.
+func startSpinner(ctx context.Context, label string) (stop func()) {
+	return startStatus(ctx, func(frame string) string { return label + " " + frame }).Stop
+}
+
+// streamWriter prints streamed reply text to w, keeping the status line below it
+// The status shows the label, elapsed time and tokens received so far.
+type streamWriter struct {
+	w      io.Writer
+	status *statusLine
+	start  time.Time
+	tokens int
+	wrote  int
+}
+
+// startStream streams reply text to w under a status line labelled label
+func startStream(ctx context.Context, w io.Writer, label string) *streamWriter {
+	sw := &streamWriter{w: w, start: time.Now()}
+	sw.status = startStatus(ctx, func(frame string) string {
+		return fmt.Sprintf("%s %s %s, ~%d tokens", frame, label, formatElapsed(time.Since(sw.start)), sw.tokens)
+	})
+	return sw
+}
+
+// Write prints text as it arrives; the status line is hidden while a line is incomplete
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	sw.status.mu.Lock()
+	defer sw.status.mu.Unlock()
+
+	sw.status.clear()
+	n, err := sw.w.Write(p)
+	if n > 0 {
+		sw.wrote += n
+		sw.tokens += EstimateTokens(string(p[:n]))
+		sw.status.paused = p[n-1] != '\n'
+	}
+	sw.status.draw()
+	return n, err
+}
+
+// Stop clears the status line and reports whether any text was streamed
+func (sw *streamWriter) Stop() bool {
+	sw.status.Stop()
+	return sw.wrote > 0
+}
+
+// Ultra run states shown on the progress line
+const (
+	runRunning = iota
+	runDone
+	runFailed
+)
+
+// runProgress is the live status of parallel ultra runs: one entry per run with its elapsed time
+type runProgress struct {
+	start   time.Time
+	mu      sync.Mutex
+	states  []int
+	elapsed []time.Duration
+	status  *statusLine
+}
+
+// startRunProgress shows the progress line for runs ultra runs
+func startRunProgress(ctx context.Context, runs int) *runProgress {
+	p := &runProgress{start: time.Now(), states: make([]int, runs), elapsed: make([]time.Duration, runs)}
+	p.status = startStatus(ctx, p.render)
+	return p
+}
+
+// Finish marks run (0-based) as done or failed and freezes its elapsed time
+func (p *runProgress) Finish(run int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.states[run] = runDone
+	if err != nil {
+		p.states[run] = runFailed
+	}
+	p.elapsed[run] = time.Since(p.start)
+}
+
+// Stop clears the progress line
+func (p *runProgress) Stop() {
+	p.status.Stop()
+}
+
+// render formats every run, e.g. "Ultra validation: run 1 ✓ done 42s | run 2 ⠹ running 51s"
+func (p *runProgress) render(frame string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	runs := make([]string, len(p.states))
+	for i, state := range p.states {
+		switch state {
+		case runDone:
+			runs[i] = fmt.Sprintf("run %d ✓ done %s", i+1, formatElapsed(p.elapsed[i]))
+		case runFailed:
+			runs[i] = fmt.Sprintf("run %d ✗ failed %s", i+1, formatElapsed(p.elapsed[i]))
+		default:
+			runs[i] = fmt.Sprintf("run %d %s running %s", i+1, frame, formatElapsed(time.Since(p.start)))
+		}
+	}
+	return "Ultra validation: " + strings.Join(runs, " | ")
+}
+
+// formatElapsed rounds a duration to whole seconds, e.g. "1m5s"
+func formatElapsed(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
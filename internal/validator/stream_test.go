@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunSemanticValidation_Stream(t *testing.T) {
+	reply := "- Rule: exact-versions\n- Location: Dependencies\n- Issue: No version for redis\n"
+	p := &ScriptedProvider{Replies: []ScriptedReply{
+		{Err: &TransientError{Err: errors.New("HTTP 529: overloaded")}},
+		{Text: reply},
+	}}
+
+	var out bytes.Buffer
+	text, err := RunSemanticValidation(context.Background(), "= Spec\n", &out, ValidationOptions{Provider: WithRetry(p, 1, time.Millisecond), Stream: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != reply {
+		t.Errorf("text = %q, want the reply alone", text)
+	}
+	if !strings.Contains(out.String(), "[HTTP 529: overloaded - retrying]") || strings.Count(out.String(), reply) != 1 {
+		t.Errorf("streamed output:\n%s", out.String())
+	}
+
+	// Buffered mode writes the reply once it is complete
+	out.Reset()
+	text, err = RunSemanticValidation(context.Background(), "= Spec\n", &out, ValidationOptions{Provider: NewScriptedProvider(reply)})
+	if err != nil || text != reply || out.String() != reply {
+		t.Errorf("buffered output = %q, %v", out.String(), err)
+	}
+}
+
+func TestRunProgress(t *testing.T) {
+	progress := startRunProgress(context.Background(), 3)
+	progress.Finish(0, nil)
+	progress.Finish(2, errors.New("boom"))
+	progress.Stop()
+
+	line := progress.render("⠋")
+	for _, expect := range []string{"run 1 ✓ done 0s", "run 2 ⠋ running", "run 3 ✗ failed 0s"} {
+		if !strings.Contains(line, expect) {
+			t.Errorf("expected %q in %q", expect, line)
+		}
+	}
+}
+
+func TestRunUltraValidation_StreamsSynthesis(t *testing.T) {
+	p := NewScriptedProvider("Specification passes all checks.", "Specification passes all checks.", "Specification passes all checks.", "All runs agree.")
+
+	var out bytes.Buffer
+	if _, err := RunUltraValidation(context.Background(), "= Spec\n", &out, ValidationOptions{Provider: p, Synthesize: true, Stream: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "=== Synthesis ===\n\nAll runs agree.") {
+		t.Errorf("output:\n%s", out.String())
+	}
+}
@@ -11,15 +11,17 @@ import (
 
 // StructuralCheck represents a fast pre-flight check
 type StructuralCheck struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Passed  bool   `json:"passed"`
-	Message string `json:"message"`
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Passed  bool            `json:"passed"`
+	Message string          `json:"message"`
+	Issues  []AnalyzerIssue `json:"issues,omitempty"` // Located issues from native analyzers
 }
 
 // RunStructuralChecks performs fast pre-flight validation
 // These checks don't require Claude - they're instant Go checks
-func RunStructuralChecks(manifestPath string) ([]StructuralCheck, error) {
+// A nil schema checks required sections against the embedded default
+func RunStructuralChecks(manifestPath string, schema *SpecSchema) ([]StructuralCheck, error) {
 	var checks []StructuralCheck
 
 	// Check 1: Spec compiles
@@ -86,6 +88,14 @@ func RunStructuralChecks(manifestPath string) ([]StructuralCheck, error) {
 	}
 	checks = append(checks, attrsCheck)
 
+	// Native analyzers over the parsed sources
+	src, err := LoadSpecSources(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	src.Schema = schema
+	checks = append(checks, runAnalyzers(src)...)
+
 	return checks, nil
 }
 
@@ -107,21 +117,22 @@ const (
 )
 
 // FormatStructuralChecks formats checks for display
-func FormatStructuralChecks(checks []StructuralCheck) string {
-	return formatStructuralChecks(checks, true)
+// A check with issues at or above failOn is marked failed even though it does not stop validation
+func FormatStructuralChecks(checks []StructuralCheck, failOn string) string {
+	return formatStructuralChecks(checks, failOn, true)
 }
 
 // FormatStructuralChecksPlain formats checks without color
-func FormatStructuralChecksPlain(checks []StructuralCheck) string {
-	return formatStructuralChecks(checks, false)
+func FormatStructuralChecksPlain(checks []StructuralCheck, failOn string) string {
+	return formatStructuralChecks(checks, failOn, false)
 }
 
-func formatStructuralChecks(checks []StructuralCheck, color bool) string {
+func formatStructuralChecks(checks []StructuralCheck, failOn string, color bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("Structural Checks:\n")
 	for _, check := range checks {
-		if check.Passed {
+		if check.Passed && CountAtOrAbove(AnalyzerFindings([]StructuralCheck{check}), failOn) == 0 {
 			if color {
 				sb.WriteString(fmt.Sprintf("  %s✓%s %s: %s\n", colorGreen, colorReset, check.Name, check.Message))
 			} else {
@@ -134,6 +145,9 @@ func formatStructuralChecks(checks []StructuralCheck, color bool) string {
 				sb.WriteString(fmt.Sprintf("  ✗ %s: %s\n", check.Name, check.Message))
 			}
 		}
+		for _, issue := range check.Issues {
+			sb.WriteString(fmt.Sprintf("      %s: [%s] %s\n", issue.Location(), issue.Severity, issue.Message))
+		}
 	}
 
 	return sb.String()
@@ -144,4 +158,3 @@ func FormatStructuralChecksJSON(checks []StructuralCheck) string {
 	data, _ := json.MarshalIndent(checks, "", "  ")
 	return string(data)
 }
-
@@ -0,0 +1,366 @@
+package validator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+
+	specparser "github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
+)
+
+// ruleUndefinedType is the analyzer ID for type references without a definition
+const ruleUndefinedType = "undefined-type"
+
+var undefinedTypeAnalyzer = Analyzer{
+	ID:   ruleUndefinedType,
+	Name: "Referenced types are defined",
+	Run:  analyzeUndefinedTypes,
+}
+
+// TypeReference is a use or declaration of a type name
+type TypeReference struct {
+	Name string
+	File string
+	Line int
+}
+
+// knownTypes are library and language types that specs use without defining
+var knownTypes = map[string]bool{
+	"String": true, "Number": true, "Boolean": true, "Object": true, "Array": true, "Date": true,
+	"Error": true, "Promise": true, "Map": true, "Set": true, "Record": true, "Partial": true,
+	"Readonly": true, "Pick": true, "Omit": true, "Buffer": true, "Uint8Array": true,
+	"Integer": true, "Long": true, "Double": true, "Float": true, "Short": true, "Byte": true,
+	"Char": true, "Character": true, "Void": true, "List": true, "Optional": true, "Stream": true,
+	"Instant": true, "Duration": true, "LocalDate": true, "LocalDateTime": true, "BigDecimal": true,
+	"Option": true, "Result": true, "Vec": true, "Box": true, "Some": true, "None": true, "Ok": true,
+	"Err": true, "Self": true, "HashMap": true, "HashSet": true, "BTreeMap": true, "Arc": true,
+	"Rc": true, "Mutex": true, "RwLock": true, "Cow": true, "Any": true, "Dict": true, "Tuple": true,
+	"Union": true, "Callable": true, "Iterable": true, "Iterator": true, "Sequence": true,
+	"Mapping": true, "Literal": true, "Enum": true, "Exception": true, "True": true, "False": true,
+	"Null": true,
+}
+
+// Library types of a source block language, known throughout a spec that has blocks in that language
+var (
+	typeScriptTypes = typeSet(`Request Response Headers URL URLSearchParams FormData Blob File ReadableStream
+		WritableStream AbortController AbortSignal HTMLElement HTMLInputElement HTMLFormElement HTMLDivElement
+		HTMLButtonElement Element Node Document Window Event MouseEvent KeyboardEvent EventTarget Function RegExp
+		Symbol BigInt ArrayBuffer DataView WeakMap WeakSet Required Awaited ReturnType Parameters Exclude Extract
+		NonNullable AsyncIterable Generator TypeError RangeError SyntaxError`)
+	pythonTypes = typeSet(`ValueError KeyError TypeError IndexError AttributeError RuntimeError NotImplementedError
+		OSError IOError LookupError StopIteration BaseException Decimal Path Set FrozenSet Type TypeVar Generic
+		Protocol NamedTuple TypedDict Awaitable Coroutine AsyncIterator Generator BaseModel Field IntEnum
+		Counter OrderedDict DefaultDict Deque`)
+	javaTypes = typeSet(`IOException UncheckedIOException IllegalArgumentException IllegalStateException
+		RuntimeException NullPointerException Throwable Collection ArrayList LinkedList TreeMap LinkedHashMap
+		Queue Deque CompletableFuture Future Supplier Consumer Function Predicate BiFunction Runnable Class
+		LocalTime ZonedDateTime OffsetDateTime BigInteger StringBuilder HttpServletRequest HttpServletResponse
+		ResponseEntity HttpStatus Logger`)
+	kotlinTypes = typeSet(`Int Unit Nothing MutableList MutableMap MutableSet Flow Pair Triple Throwable
+		IllegalArgumentException IllegalStateException`)
+	rustTypes = typeSet(`Path PathBuf Ordering Default Clone Debug Display Send Sync Fn FnMut FnOnce`)
+)
+
+var languageTypes = map[string]map[string]bool{
+	"typescript": typeScriptTypes, "ts": typeScriptTypes,
+	"python": pythonTypes, "py": pythonTypes,
+	"java":   javaTypes,
+	"kotlin": kotlinTypes,
+	"rust":   rustTypes,
+}
+
+func typeSet(names string) map[string]bool {
+	set := make(map[string]bool)
+	for _, name := range strings.Fields(names) {
+		set[name] = true
+	}
+	return set
+}
+
+// Type declarations in any language: type X, struct X, class X, message X, ...
+var typeDeclarationPattern = regexp.MustCompile(`\b(?:type|struct|enum|interface|class|trait|message|record|union|protocol)\s+([A-Z]\w*)`)
+
+// typeNamePattern matches identifiers that read as type names: capitalized with a lower-case letter
+// All-caps words (JSON, UUID, HTTP) are abbreviations, not types
+var typeNamePattern = regexp.MustCompile(`[A-Z][A-Za-z0-9]*[a-z][A-Za-z0-9]*`)
+
+// Type positions in source blocks other than Go, and in Go fragments that do not parse
+var sourceTypeRefPattern = regexp.MustCompile(`(?:[:<(,|]\s*|->\s*|\[\]\s*\*?|\*|map\[[^\]]*\]\s*\*?|\b(?:extends|implements)\s+|^\s*[a-z]\w*\s+(?:\[\]|\*)*)([A-Z]\w*)`)
+
+// Type positions in prose: []Foo, map[string]Bar, "returns Baz", "of type Qux"
+var proseTypeRefPattern = regexp.MustCompile("(?:\\[\\]\\*?|map\\[[^\\]]*\\]\\*?|\\breturns?\\s+(?:an?\\s+|the\\s+|a list of\\s+|an array of\\s+|a slice of\\s+)?`?(?:\\[\\]|\\*)*|\\bof type\\s+`?(?:\\[\\]|\\*)*)([A-Z]\\w*)")
+
+// typeSectionPattern matches titles of sections whose subheadings name types
+var typeSectionPattern = regexp.MustCompile(`(?i)\b(?:types?|models?|entities|data structures)\b`)
+
+// typeCheckedLanguages are the source block languages scanned for type declarations and references
+var typeCheckedLanguages = map[string]bool{
+	"go": true, "golang": true, "typescript": true, "ts": true, "rust": true, "java": true,
+	"kotlin": true, "swift": true, "python": true, "py": true, "proto": true, "protobuf": true,
+	"csharp": true, "cs": true, "scala": true, "graphql": true,
+}
+
+// TypeReferences collects declared types and the places types are referenced
+// Declarations come from source blocks and from subheadings of type sections;
+// references come from source blocks, prose and the Type column of tables
+func TypeReferences(src *SpecSources) (defined map[string]TypeReference, refs []TypeReference) {
+	defined = make(map[string]TypeReference)
+	define := func(ref TypeReference) {
+		if _, ok := defined[ref.Name]; !ok {
+			defined[ref.Name] = ref
+		}
+	}
+
+	for _, doc := range src.Documents {
+		file := src.RelPath(doc.FilePath)
+
+		for _, block := range doc.Blocks {
+			if !typeCheckedLanguages[block.Language] {
+				continue
+			}
+			defs, uses := blockTypeReferences(block, file)
+			for _, def := range defs {
+				define(def)
+			}
+			refs = append(refs, uses...)
+		}
+
+		for _, line := range doc.Prose {
+			for _, name := range matchTypeRefs(proseTypeRefPattern, line.Text) {
+				refs = append(refs, TypeReference{Name: name, File: file, Line: line.Line})
+			}
+		}
+
+		for _, table := range doc.Tables {
+			column := -1
+			for i, cell := range table.Header {
+				if strings.EqualFold(strings.TrimSpace(cell), "type") {
+					column = i
+				}
+			}
+			if column < 0 {
+				continue
+			}
+			for _, row := range table.Rows {
+				if column >= len(row.Cells) {
+					continue
+				}
+				for _, name := range typeNames(row.Cells[column]) {
+					refs = append(refs, TypeReference{Name: name, File: file, Line: row.Line})
+				}
+			}
+		}
+	}
+
+	// Subheadings of type sections: === User, === `LineItem`
+	if outline, err := specparser.BuildOutline(src.ManifestPath); err == nil {
+		for i, section := range outline {
+			if !typeSectionPattern.MatchString(section.Title) {
+				continue
+			}
+			for _, j := range descendants(outline, i) {
+				title := strings.Fields(strings.Trim(outline[j].Title, "`"))
+				if len(title) > 0 && typeNamePattern.FindString(title[0]) == title[0] {
+					define(TypeReference{Name: title[0], File: src.RelPath(outline[j].FilePath), Line: outline[j].StartLine})
+				}
+			}
+		}
+	}
+
+	return defined, refs
+}
+
+// blockTypeReferences returns the types a source block declares and references
+// Go blocks are parsed; other languages, and Go fragments that do not parse, are matched by pattern
+func blockTypeReferences(block specparser.SourceBlock, file string) (defs, refs []TypeReference) {
+	if block.Language == "go" || block.Language == "golang" {
+		if defs, refs, ok := goTypeReferences(strings.Join(block.Lines, "\n"), block.StartLine, file); ok {
+			return defs, refs
+		}
+	}
+
+	for i, line := range block.Lines {
+		line = stripCodeLiterals(line)
+		for _, m := range typeDeclarationPattern.FindAllStringSubmatch(line, -1) {
+			defs = append(defs, TypeReference{Name: m[1], File: file, Line: block.StartLine + i})
+		}
+		for _, name := range matchTypeRefs(sourceTypeRefPattern, line) {
+			refs = append(refs, TypeReference{Name: name, File: file, Line: block.StartLine + i})
+		}
+	}
+	return defs, refs
+}
+
+// goTypeReferences parses a Go block, returning declared types and identifiers used in type positions
+func goTypeReferences(source string, startLine int, file string) (defs, refs []TypeReference, ok bool) {
+	if !strings.HasPrefix(strings.TrimSpace(source), "package ") {
+		source = "package spec\n" + source
+		startLine--
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", source, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil, false
+	}
+	ref := func(ident *ast.Ident) TypeReference {
+		return TypeReference{Name: ident.Name, File: file, Line: startLine + fset.Position(ident.Pos()).Line - 1}
+	}
+
+	seen := make(map[token.Pos]bool)
+	typeParams := make(map[string]bool)
+	useType := func(expr ast.Expr) {
+		ast.Inspect(expr, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				return false // Package-qualified: time.Time, uuid.UUID
+			case *ast.Ident:
+				if !seen[n.Pos()] && typeNamePattern.FindString(n.Name) == n.Name {
+					seen[n.Pos()] = true
+					refs = append(refs, ref(n))
+				}
+			}
+			return true
+		})
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.TypeSpec:
+			defs = append(defs, ref(n.Name))
+			if n.TypeParams != nil {
+				for _, param := range n.TypeParams.List {
+					for _, name := range param.Names {
+						typeParams[name.Name] = true
+					}
+				}
+			}
+			if _, isStruct := n.Type.(*ast.StructType); !isStruct {
+				useType(n.Type)
+			}
+		case *ast.Field:
+			useType(n.Type)
+		case *ast.ValueSpec:
+			if n.Type != nil {
+				useType(n.Type)
+			}
+		case *ast.CompositeLit:
+			if n.Type != nil {
+				useType(n.Type)
+			}
+		}
+		return true
+	})
+
+	filtered := refs[:0]
+	for _, r := range refs {
+		if !typeParams[r.Name] {
+			filtered = append(filtered, r)
+		}
+	}
+	return defs, filtered, true
+}
+
+// stringLiteralPattern matches quoted strings, whose contents are not code
+var stringLiteralPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+
+// stripCodeLiterals removes string literals and trailing // or # comments from a source line
+func stripCodeLiterals(line string) string {
+	line = stringLiteralPattern.ReplaceAllString(line, `""`)
+	if idx := strings.Index(line, "//"); idx >= 0 {
+		line = line[:idx]
+	}
+	if idx := strings.Index(line, "#"); idx >= 0 {
+		line = line[:idx]
+	}
+	return line
+}
+
+// matchTypeRefs returns the type names captured by pattern, skipping package-qualified names
+func matchTypeRefs(pattern *regexp.Regexp, text string) []string {
+	var names []string
+	for _, m := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[2], m[3]
+		if start > 0 && text[start-1] == '.' || end < len(text) && text[end] == '.' {
+			continue
+		}
+		name := text[start:end]
+		if typeNamePattern.FindString(name) == name {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// typeNames returns the type names in a type expression such as `[]LineItem` or map[string]*Price
+func typeNames(expr string) []string {
+	var names []string
+	for _, loc := range typeNamePattern.FindAllStringIndex(expr, -1) {
+		start, end := loc[0], loc[1]
+		if start > 0 && isIdentByte(expr[start-1]) || end < len(expr) && isIdentByte(expr[end]) {
+			continue // Part of a longer identifier, e.g. ID in UserIDs
+		}
+		if start > 0 && expr[start-1] == '.' || end < len(expr) && expr[end] == '.' {
+			continue
+		}
+		names = append(names, expr[start:end])
+	}
+	return names
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// analyzeUndefinedTypes reports each undefined type once, at its first reference
+func analyzeUndefinedTypes(src *SpecSources) []AnalyzerIssue {
+	defined, refs := TypeReferences(src)
+
+	// A spec with TypeScript blocks may name Request or HTMLElement anywhere without defining them
+	library := make(map[string]bool)
+	for _, doc := range src.Documents {
+		for _, block := range doc.Blocks {
+			for name := range languageTypes[block.Language] {
+				library[name] = true
+			}
+		}
+	}
+
+	byName := make(map[string][]TypeReference)
+	var names []string
+	for _, r := range refs {
+		if _, ok := defined[r.Name]; ok || knownTypes[r.Name] || library[r.Name] {
+			continue
+		}
+		if _, ok := byName[r.Name]; !ok {
+			names = append(names, r.Name)
+		}
+		byName[r.Name] = append(byName[r.Name], r)
+	}
+	sort.Strings(names)
+
+	var issues []AnalyzerIssue
+	for _, name := range names {
+		uses := byName[name]
+		message := fmt.Sprintf("type %s is referenced but never defined", name)
+		if len(uses) > 1 {
+			var others []string
+			for _, use := range uses[1:] {
+				others = append(others, fmt.Sprintf("%s:%d", use.File, use.Line))
+			}
+			message += fmt.Sprintf(" (also at %s)", strings.Join(others, ", "))
+		}
+		issues = append(issues, AnalyzerIssue{
+			Rule:     ruleUndefinedType,
+			Severity: SeverityWarning,
+			File:     uses[0].File,
+			Line:     uses[0].Line,
+			Message:  message,
+		})
+	}
+	return issues
+}
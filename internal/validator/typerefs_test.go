@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeUndefinedTypes(t *testing.T) {
+	manifest := `= Orders
+
+== Core Types
+
+=== Customer
+
+Customers are loaded from the CRM.
+
+=== Order
+
+[source,go]
+----
+type Order struct {
+    ID       uuid.UUID
+    Customer Customer
+    Lines    []LineItem
+    Totals   map[string]*Money
+    Placed   time.Time
+}
+
+type Store[T any] interface {
+    Get(id string) (T, error)
+}
+
+func (s *Service) Place(o Order) (*Receipt, error) { ... }
+----
+
+== API
+
+` + "`POST /orders`" + ` returns a Receipt and fails with JSON errors.
+Each shipment carries a []LineItem copied from the order.
+
+[source,typescript]
+----
+interface Refund {
+  order: Order;
+  reason: RefundReason; // "Damaged" or "Late"
+}
+----
+
+|===
+| Field | Type
+
+| discount
+| ` + "`Discount`" + `
+| createdAt
+| Date
+|===
+`
+	src := loadSpecFiles(t, map[string]string{"MANIFEST.adoc": manifest})
+
+	issues := analyzeUndefinedTypes(src)
+	got := make([]string, len(issues))
+	for i, issue := range issues {
+		got[i] = issue.Location() + " " + issue.Message
+	}
+
+	want := []string{
+		"MANIFEST.adoc:44 type Discount is referenced but never defined",
+		"MANIFEST.adoc:16 type LineItem is referenced but never defined (also at MANIFEST.adoc:31)",
+		"MANIFEST.adoc:17 type Money is referenced but never defined",
+		"MANIFEST.adoc:25 type Receipt is referenced but never defined (also at MANIFEST.adoc:30)",
+		"MANIFEST.adoc:37 type RefundReason is referenced but never defined",
+		"MANIFEST.adoc:25 type Service is referenced but never defined",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAnalyzeUndefinedTypes_LibraryTypes(t *testing.T) {
+	manifest := `= Gateway
+
+== API
+
+The handler returns a Response built from the upstream reply.
+
+[source,typescript]
+----
+interface Handler {
+  handle(req: Request): Promise<Response>;
+  mount(root: HTMLElement): void;
+  route: Route;
+}
+----
+
+[source,python]
+----
+def parse(raw: str) -> Config:
+    raise ValueError("bad input")
+
+def lookup(key: str) -> KeyError:
+    ...
+----
+
+[source,java]
+----
+public interface Upstream {
+    Reply call(HttpServletRequest request, CallOptions options) throws IOException;
+}
+----
+`
+	src := loadSpecFiles(t, map[string]string{"MANIFEST.adoc": manifest})
+
+	issues := analyzeUndefinedTypes(src)
+	var got []string
+	for _, issue := range issues {
+		if issue.Severity != SeverityWarning {
+			t.Errorf("%s: severity = %s, want warning", issue.Message, issue.Severity)
+		}
+		got = append(got, issue.Message)
+	}
+	want := []string{
+		"type CallOptions is referenced but never defined",
+		"type Config is referenced but never defined",
+		"type Route is referenced but never defined",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFormatStructuralChecks_FailOn(t *testing.T) {
+	checks := []StructuralCheck{{
+		ID:      ruleUndefinedType,
+		Name:    undefinedTypeAnalyzer.Name,
+		Passed:  true,
+		Message: "1 issue(s): 1 warning",
+		Issues:  []AnalyzerIssue{{Rule: ruleUndefinedType, Severity: SeverityWarning, File: "MANIFEST.adoc", Line: 3, Message: "type Route is referenced but never defined"}},
+	}}
+
+	if out := FormatStructuralChecksPlain(checks, SeverityError); !strings.Contains(out, "✓ Referenced types are defined") {
+		t.Errorf("warning under --fail-on error should pass:\n%s", out)
+	}
+	if out := FormatStructuralChecksPlain(checks, SeverityWarning); !strings.Contains(out, "✗ Referenced types are defined") {
+		t.Errorf("warning under --fail-on warning should fail:\n%s", out)
+	}
+}
+
+func TestTypeNames(t *testing.T) {
+	tests := map[string]string{
+		"`[]LineItem`":          "LineItem",
+		"map[string]*Price":     "Price",
+		"time.Time":             "",
+		"string (UUID)":         "",
+		"Result<Order, ApiErr>": "Result Order ApiErr",
+	}
+	for expr, want := range tests {
+		if got := strings.Join(typeNames(expr), " "); got != want {
+			t.Errorf("typeNames(%q) = %q, want %q", expr, got, want)
+		}
+	}
+}
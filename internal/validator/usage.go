@@ -0,0 +1,311 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+// Output tokens assumed per call when estimating a run before it happens
+const estimatedOutputTokens = 2000
+
+// Splits text the way BPE tokenizers tend to: letter runs, digit runs,
+// whitespace runs and single symbols
+var tokenPiecePattern = regexp.MustCompile(`\p{L}+|\p{N}+|\s+|[^\s\p{L}\p{N}]`)
+
+// EstimateTokens approximates the token count of text for Claude-style BPE vocabularies
+// Common words are one token, long words one per ~6 letters, digits one per 3,
+// symbols and newlines one each; a single space merges into the following word
+func EstimateTokens(text string) int {
+	tokens := 0
+	for _, piece := range tokenPiecePattern.FindAllString(text, -1) {
+		r, _ := utf8.DecodeRuneInString(piece)
+		n := utf8.RuneCountInString(piece)
+		switch {
+		case unicode.IsSpace(r):
+			if newlines := strings.Count(piece, "\n"); newlines > 0 {
+				tokens += newlines
+			} else if n > 1 {
+				tokens++ // Indentation run
+			}
+		case unicode.IsLetter(r):
+			if r >= utf8.RuneSelf {
+				tokens += n // Non-Latin scripts rarely merge
+			} else {
+				tokens += 1 + (n-1)/6
+			}
+		case unicode.IsDigit(r):
+			tokens += (n + 2) / 3
+		default:
+			tokens++
+		}
+	}
+	return tokens
+}
+
+// CallUsage is the token usage of one provider call
+type CallUsage struct {
+	Label string `json:"label"` // e.g. "validate", "run 2", "run 1 / chunk 3/4", "synthesis"
+	Usage
+	Estimated bool `json:"estimated,omitempty"` // Provider did not report usage; counted with EstimateTokens
+}
+
+// UsageReport totals the provider calls of a validation run
+type UsageReport struct {
+	Calls  []CallUsage `json:"calls"`
+	Total  Usage       `json:"total"`
+	Cost   float64     `json:"cost_usd,omitempty"`
+	Priced bool        `json:"-"` // A price was configured for the model
+}
+
+// NewUsageReport totals calls and prices them when a price is known
+func NewUsageReport(calls []CallUsage, price config.Price, priced bool) *UsageReport {
+	report := &UsageReport{Calls: calls, Priced: priced}
+	for _, call := range calls {
+		report.Total.InputTokens += call.InputTokens
+		report.Total.OutputTokens += call.OutputTokens
+	}
+	if priced {
+		report.Cost = usageCost(report.Total, price)
+	}
+	return report
+}
+
+// FormatUsage formats the per-call token breakdown with a total
+func FormatUsage(report *UsageReport) string {
+	var sb strings.Builder
+	sb.WriteString("=== Token Usage ===\n\n")
+
+	estimated := false
+	for _, call := range report.Calls {
+		marker := " "
+		if call.Estimated {
+			marker = "~"
+			estimated = true
+		}
+		sb.WriteString(fmt.Sprintf("  %-24s %s%9d in  %s%9d out\n", call.Label, marker, call.InputTokens, marker, call.OutputTokens))
+	}
+	sb.WriteString(fmt.Sprintf("  %-24s  %9d in   %9d out", "Total", report.Total.InputTokens, report.Total.OutputTokens))
+	if report.Priced {
+		sb.WriteString(fmt.Sprintf("   $%.2f", report.Cost))
+	}
+	sb.WriteString("\n")
+	if estimated {
+		sb.WriteString("  (~ estimated: the provider does not report usage)\n")
+	}
+	return sb.String()
+}
+
+// PriceFor looks up the configured price for a provider's model, falling back to the provider name
+func PriceFor(pricing map[string]config.Price, p Provider) (config.Price, bool) {
+	if price, ok := pricing[p.Model()]; ok && p.Model() != "" {
+		return price, true
+	}
+	price, ok := pricing[p.Name()]
+	return price, ok
+}
+
+func usageCost(u Usage, price config.Price) float64 {
+	return (float64(u.InputTokens)*price.Input + float64(u.OutputTokens)*price.Output) / 1e6
+}
+
+// RunEstimate is the expected size of a semantic run, computed before calling the provider
+type RunEstimate struct {
+	Calls int
+	Usage
+}
+
+// EstimateRun renders every prompt the run would send and estimates its tokens
+// Output is assumed to be estimatedOutputTokens per call
+func EstimateRun(compiledSpec string, opts ValidationOptions) (*RunEstimate, error) {
+	var prompts []string
+	if opts.Chunks != nil {
+		var err error
+		if prompts, err = chunkPrompts(opts.Chunks, opts); err != nil {
+			return nil, err
+		}
+	} else {
+		prompt, err := opts.Prompts.Render("validate", opts.validateData(compiledSpec))
+		if err != nil {
+			return nil, fmt.Errorf("failed to render prompt: %w", err)
+		}
+		prompts = []string{prompt}
+	}
+
+	estimate := &RunEstimate{Calls: len(prompts)}
+	for _, prompt := range prompts {
+		estimate.InputTokens += EstimateTokens(prompt)
+	}
+
+	if opts.Ultra {
+		runs := opts.ultraRuns()
+		estimate.Calls *= runs
+		estimate.InputTokens *= runs
+		if opts.Synthesize {
+			// Synthesis reads every run's report
+			estimate.Calls++
+			estimate.InputTokens += runs*estimatedOutputTokens + 1000
+		}
+	}
+	estimate.OutputTokens = estimate.Calls * estimatedOutputTokens
+	return estimate, nil
+}
+
+// BudgetError refuses a run whose estimate exceeds budget.tokens or budget.cost in .spec.yaml
+type BudgetError struct {
+	Estimate *RunEstimate
+	Tokens   int     // Estimated input + output tokens, set when the token budget is exceeded
+	Cost     float64 // Estimated cost, set when the cost budget is exceeded
+	Budget   config.BudgetConfig
+}
+
+func (e *BudgetError) Error() string {
+	usage := fmt.Sprintf("~%d input + ~%d output tokens over %d call(s)", e.Estimate.InputTokens, e.Estimate.OutputTokens, e.Estimate.Calls)
+	if e.Tokens > 0 {
+		return fmt.Sprintf("over budget: estimated %d tokens (%s), budget.tokens is %d (raise it in .spec.yaml, or use --since/--quick)", e.Tokens, usage, e.Budget.Tokens)
+	}
+	return fmt.Sprintf("over budget: estimated $%.2f (%s), budget.cost is $%.2f (raise it in .spec.yaml, or use --since/--quick)", e.Cost, usage, e.Budget.Cost)
+}
+
+// checkBudget refuses an estimated run that exceeds the configured budget with a *BudgetError
+func checkBudget(estimate *RunEstimate, opts ValidationOptions) error {
+	budget := opts.Budget
+	total := estimate.InputTokens + estimate.OutputTokens
+	if budget.Tokens > 0 && total > budget.Tokens {
+		return &BudgetError{Estimate: estimate, Tokens: total, Budget: budget}
+	}
+	if budget.Cost > 0 {
+		price, ok := PriceFor(opts.Pricing, opts.provider())
+		if !ok {
+			return fmt.Errorf("budget.cost is set but no pricing is configured for %s", ProviderLabel(opts.provider()))
+		}
+		if cost := usageCost(estimate.Usage, price); cost > budget.Cost {
+			return &BudgetError{Estimate: estimate, Cost: cost, Budget: budget}
+		}
+	}
+	return nil
+}
+
+// completeWithinBudget sends a single prompt, refusing it with a *BudgetError when its
+// estimate exceeds the configured budget, and returns the reply with the call's usage
+func completeWithinBudget(ctx context.Context, label, prompt string, opts ValidationOptions) (*Response, *UsageReport, error) {
+	estimate := &RunEstimate{Calls: 1, Usage: Usage{InputTokens: EstimateTokens(prompt), OutputTokens: estimatedOutputTokens}}
+	if err := checkBudget(estimate, opts); err != nil {
+		return nil, nil, err
+	}
+
+	meter := newMeteredProvider(opts.provider(), opts)
+	resp, err := meter.Complete(withCallLabel(ctx, label), prompt)
+	return resp, NewUsageReport(meter.Calls(), meter.price, meter.priced), err
+}
+
+// meteredProvider records the token usage of every call, including failed attempts
+// The retry layer reports the attempts it retries through the context; the meter
+// records the final reply, or the final failure when it was transient
+type meteredProvider struct {
+	Provider
+	budget config.BudgetConfig // Retries stop once the recorded calls spend it
+	price  config.Price
+	priced bool
+
+	mu    sync.Mutex
+	calls []CallUsage
+}
+
+// newMeteredProvider meters p against the budget and pricing in opts
+func newMeteredProvider(p Provider, opts ValidationOptions) *meteredProvider {
+	price, priced := PriceFor(opts.Pricing, p)
+	return &meteredProvider{Provider: p, budget: opts.Budget, price: price, priced: priced}
+}
+
+// Complete forwards to the wrapped provider, estimating usage it does not report
+func (m *meteredProvider) Complete(ctx context.Context, prompt string) (*Response, error) {
+	resp, err := m.Provider.Complete(withMeter(ctx, m), prompt)
+	if err != nil {
+		if ctx.Err() == nil && IsTransient(err) {
+			m.recordFailed(ctx, prompt, "failed")
+		}
+		return nil, err
+	}
+
+	call := CallUsage{Label: meterLabel(ctx)}
+	if resp.Usage != nil {
+		call.Usage = *resp.Usage
+	} else {
+		call.Usage = Usage{InputTokens: EstimateTokens(prompt), OutputTokens: EstimateTokens(resp.Text)}
+		call.Estimated = true
+	}
+	m.record(call)
+
+	return resp, nil
+}
+
+// recordFailed counts the prompt of a failed attempt, which timeouts and server errors may still bill
+func (m *meteredProvider) recordFailed(ctx context.Context, prompt, note string) {
+	m.record(CallUsage{
+		Label:     meterLabel(ctx) + " (" + note + ")",
+		Usage:     Usage{InputTokens: EstimateTokens(prompt)},
+		Estimated: true,
+	})
+}
+
+func (m *meteredProvider) record(call CallUsage) {
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	m.mu.Unlock()
+}
+
+// spent reports whether the recorded calls have used up the budget
+func (m *meteredProvider) spent() bool {
+	report := NewUsageReport(m.Calls(), m.price, m.priced)
+	total := report.Total.InputTokens + report.Total.OutputTokens
+	return m.budget.Tokens > 0 && total > m.budget.Tokens || m.budget.Cost > 0 && m.priced && report.Cost > m.budget.Cost
+}
+
+type meterKey struct{}
+
+// withMeter lets the retry layer below m report the attempts it retries
+func withMeter(ctx context.Context, m *meteredProvider) context.Context {
+	return context.WithValue(ctx, meterKey{}, m)
+}
+
+func meterOf(ctx context.Context) *meteredProvider {
+	m, _ := ctx.Value(meterKey{}).(*meteredProvider)
+	return m
+}
+
+func meterLabel(ctx context.Context) string {
+	if label := callLabel(ctx); label != "" {
+		return label
+	}
+	return "validate"
+}
+
+// Calls returns the recorded calls
+func (m *meteredProvider) Calls() []CallUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CallUsage(nil), m.calls...)
+}
+
+type callLabelKey struct{}
+
+// withCallLabel names the provider calls made with ctx for usage reporting
+// Labels nest, e.g. "run 2 / chunk 1/3"
+func withCallLabel(ctx context.Context, label string) context.Context {
+	if parent := callLabel(ctx); parent != "" {
+		label = parent + " / " + label
+	}
+	return context.WithValue(ctx, callLabelKey{}, label)
+}
+
+func callLabel(ctx context.Context) string {
+	label, _ := ctx.Value(callLabelKey{}).(string)
+	return label
+}
@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello world", 2},
+		{"PostgreSQL 16.2", 5},      // "Postgre" "SQL" "16" "." "2"
+		{"internationalization", 4}, // long word splits
+		{"a\nb\n", 4},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMeteredProvider(t *testing.T) {
+	meter := &meteredProvider{Provider: NewScriptedProvider("first reply", "second reply")}
+
+	meter.Complete(context.Background(), "prompt one")
+	meter.Complete(withCallLabel(withCallLabel(context.Background(), "run 2"), "chunk 1/3"), "prompt two")
+
+	calls := meter.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	if calls[0].Label != "validate" || calls[1].Label != "run 2 / chunk 1/3" {
+		t.Errorf("unexpected labels: %q, %q", calls[0].Label, calls[1].Label)
+	}
+	if !calls[0].Estimated || calls[0].InputTokens != 2 {
+		t.Errorf("expected estimated usage for scripted provider, got %+v", calls[0])
+	}
+
+	report := NewUsageReport(calls, config.Price{Input: 3, Output: 15}, true)
+	if report.Total.InputTokens != 4 || report.Total.OutputTokens != 4 {
+		t.Errorf("unexpected total: %+v", report.Total)
+	}
+	if !strings.Contains(FormatUsage(report), "$0.00") {
+		t.Errorf("expected cost in report:\n%s", FormatUsage(report))
+	}
+}
+
+func TestMeteredProvider_CountsRetriedAttempts(t *testing.T) {
+	p := &ScriptedProvider{Replies: []ScriptedReply{
+		{Err: &TransientError{Err: errors.New("HTTP 503: unavailable")}},
+		{Err: context.DeadlineExceeded},
+		{Text: "ok"},
+	}}
+	meter := newMeteredProvider(WithRetry(p, 2, time.Millisecond), ValidationOptions{})
+
+	if _, err := meter.Complete(withCallLabel(context.Background(), "run 1"), "prompt one"); err != nil {
+		t.Fatal(err)
+	}
+
+	var labels []string
+	for _, call := range meter.Calls() {
+		labels = append(labels, call.Label)
+		if call.InputTokens != 2 {
+			t.Errorf("%s: input tokens = %d, want 2", call.Label, call.InputTokens)
+		}
+	}
+	want := "run 1 (attempt 1 failed)|run 1 (attempt 2 failed)|run 1"
+	if strings.Join(labels, "|") != want {
+		t.Errorf("labels = %v, want %s", labels, want)
+	}
+}
+
+func TestMeteredProvider_StopsRetryingOverBudget(t *testing.T) {
+	p := &ScriptedProvider{Replies: []ScriptedReply{
+		{Err: &TransientError{Err: errors.New("HTTP 529: overloaded")}},
+		{Err: &TransientError{Err: errors.New("HTTP 529: overloaded")}},
+		{Text: "unreachable"},
+	}}
+	opts := ValidationOptions{Budget: config.BudgetConfig{Tokens: 3}}
+	meter := newMeteredProvider(WithRetry(p, 2, time.Millisecond), opts)
+
+	_, err := meter.Complete(context.Background(), "a prompt that is long enough")
+	if err == nil || !strings.Contains(err.Error(), "budget is spent after 1 attempt(s)") {
+		t.Fatalf("err = %v, want budget stop", err)
+	}
+	if calls := len(p.Prompts()); calls != 1 {
+		t.Errorf("provider calls = %d, want 1", calls)
+	}
+	if calls := meter.Calls(); len(calls) != 2 || calls[1].Label != "validate (failed)" {
+		t.Errorf("recorded calls = %+v, want the retried attempt and the final failure", calls)
+	}
+}
+
+func TestCheckBudget(t *testing.T) {
+	estimate := &RunEstimate{Calls: 3, Usage: Usage{InputTokens: 90000, OutputTokens: 6000}}
+	provider := NewScriptedProvider()
+
+	opts := ValidationOptions{Provider: provider, Budget: config.BudgetConfig{Tokens: 50000}}
+	err := checkBudget(estimate, opts)
+	var overBudget *BudgetError
+	if !errors.As(err, &overBudget) {
+		t.Fatalf("err = %v, want a budget refusal", err)
+	}
+	if !strings.Contains(err.Error(), "estimated 96000 tokens (~90000 input + ~6000 output tokens over 3 call(s)), budget.tokens is 50000") {
+		t.Errorf("refusal does not show the estimate and the limit: %v", err)
+	}
+
+	opts.Budget = config.BudgetConfig{Cost: 1}
+	if err := checkBudget(estimate, opts); err == nil || errors.As(err, &overBudget) {
+		t.Errorf("err = %v, want a configuration error for a cost budget without pricing", err)
+	}
+
+	opts.Pricing = map[string]config.Price{"scripted": {Input: 3, Output: 15}}
+	if err := checkBudget(estimate, opts); err != nil {
+		t.Errorf("$0.36 run should fit a $1 budget: %v", err)
+	}
+}
@@ -1,12 +1,20 @@
 package validator
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/emontenegr/ClaudeCodeArchitect/internal/compiler"
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/differ"
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/parser"
 )
 
 // ValidationResult represents the complete validation result
@@ -15,23 +23,39 @@ type ValidationResult struct {
 	StructuralPassed bool              `json:"structural_passed"`
 	SemanticRun      bool              `json:"semantic_run"`
 	Cancelled        bool              `json:"cancelled"`
+	Cached           bool              `json:"cached,omitempty"`
+	DegradedRuns     []int             `json:"degraded_runs,omitempty"` // Ultra runs that failed
+	Usage            *UsageReport      `json:"usage,omitempty"`         // Provider calls made by this run
+	Findings         []Finding         `json:"findings,omitempty"`
 }
 
 // Validate runs the hybrid validation: structural checks + Claude semantic analysis
-func Validate(manifestPath string, output io.Writer, opts ValidationOptions) (*ValidationResult, error) {
+// Cancelling ctx (e.g. on Ctrl-C) stops in-flight provider calls and marks the result cancelled
+func Validate(ctx context.Context, manifestPath string, output io.Writer, opts ValidationOptions) (*ValidationResult, error) {
 	result := &ValidationResult{}
 
 	// Phase 1: Fast structural checks
 	fmt.Fprint(output, "=== Phase 1: Structural Checks ===\n\n")
 
-	checks, err := RunStructuralChecks(manifestPath)
+	checks, err := RunStructuralChecks(manifestPath, opts.Schema)
 	if err != nil {
 		return nil, fmt.Errorf("structural checks failed: %w", err)
 	}
 	result.StructuralChecks = checks
-	result.StructuralPassed = AllStructuralChecksPassed(checks)
+	ApplyRuleConfig(result, opts.Severities, opts.Disabled)
+
+	// --section and --file: native rules only report issues inside the slice
+	if opts.Section != "" || opts.File != "" {
+		slice, err := ResolveSlice(manifestPath, opts.Section, opts.File)
+		if err != nil {
+			return nil, err
+		}
+		slice.Scope(result)
+		opts.Slice = slice
+		fmt.Fprintf(output, "Scoped to %s (%d section(s))\n\n", slice.Target, len(slice.Sections))
+	}
 
-	fmt.Fprint(output, FormatStructuralChecks(checks))
+	fmt.Fprint(output, FormatStructuralChecks(result.StructuralChecks, opts.FailOn))
 	fmt.Fprintln(output)
 
 	// If structural checks failed, stop here
@@ -42,11 +66,15 @@ func Validate(manifestPath string, output io.Writer, opts ValidationOptions) (*V
 
 	fmt.Fprint(output, "\033[32m✓\033[0m Structural checks passed\n\n")
 
-	// Phase 2: Semantic validation with Claude
-	fmt.Fprint(output, "=== Phase 2: Semantic Validation (Claude) ===\n\n")
+	provider := opts.provider()
+	meter := newMeteredProvider(provider, opts)
+	opts.Provider = meter
 
-	if !IsClaudeAvailable() {
-		return nil, fmt.Errorf("claude CLI not found - required for semantic validation\n\nInstall from: https://claude.ai/code\n\nOr use 'validate --quick' for structural checks only")
+	// Phase 2: Semantic validation with the configured provider
+	fmt.Fprintf(output, "=== Phase 2: Semantic Validation (%s) ===\n\n", ProviderLabel(provider))
+
+	if err := provider.Available(); err != nil {
+		return nil, fmt.Errorf("%v - required for semantic validation\n\nOr use 'validate --quick' for structural checks only", err)
 	}
 
 	// Compile the spec
@@ -55,39 +83,224 @@ func Validate(manifestPath string, output io.Writer, opts ValidationOptions) (*V
 		return nil, fmt.Errorf("failed to compile spec: %w", err)
 	}
 
-	// Check spec size and confirm if large
-	proceed, err := CheckSpecSize(compiledSpec, opts, output)
+	// Incremental mode: only send sections changed since the given ref
+	specToValidate, incremental, err := semanticInput(manifestPath, compiledSpec, &opts)
 	if err != nil {
-		return nil, fmt.Errorf("size check failed: %w", err)
+		return nil, err
 	}
-	if !proceed {
-		fmt.Fprintln(output, "Validation cancelled by user.")
-		result.Cancelled = true
-		return result, nil
+	if incremental != nil {
+		if len(incremental.Changed) == 0 {
+			fmt.Fprintf(output, "No section changes since %s.\n\n", opts.Since)
+			result.Findings, err = reportCachedFindings(manifestPath, nil, compiledSpec, output)
+			if err != nil {
+				return nil, err
+			}
+			ApplySeverities(result.Findings, opts.Severities)
+			result.Findings = DropDisabled(result.Findings, opts.Disabled)
+			return result, nil
+		}
+		formatIncrementalHeader(output, incremental, opts.Since)
 	}
 
-	// Run Claude validation (ultra or normal)
-	result.SemanticRun = true
-	if opts.Ultra {
-		if err := RunUltraValidation(compiledSpec, output); err != nil {
-			return nil, fmt.Errorf("ultra validation failed: %w", err)
+	// Replay a cached result for an identical spec, prompt, provider and rule set
+	cache := NewResponseCache(manifestPath)
+	cacheKey, err := semanticCacheKey(specToValidate, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build cache key: %w", err)
+	}
+
+	var cached *CacheEntry
+	if !opts.NoCache {
+		if cached, err = cache.Get(cacheKey); err != nil {
+			fmt.Fprintf(output, "Warning: failed to read cache: %v\n", err)
 		}
+	}
+
+	// Run semantic validation (ultra or normal), keeping a copy for findings extraction
+	var semanticBuf bytes.Buffer
+	semanticOutput := io.MultiWriter(output, &semanticBuf)
+
+	var findings []Finding
+	result.SemanticRun = true
+	if cached != nil {
+		result.Cached = true
+		fmt.Fprintf(output, "Using cached result from %s (--no-cache to re-run)\n\n", cached.CreatedAt.Local().Format("2006-01-02 15:04"))
+		fmt.Fprint(semanticOutput, cached.Output)
+		findings = cached.Findings
 	} else {
-		if err := RunClaudeValidation(compiledSpec, output); err != nil {
-			return nil, fmt.Errorf("semantic validation failed: %w", err)
+		// Check spec size and confirm if large
+		proceed, err := CheckSpecSize(specToValidate, opts, output)
+		var overBudget *BudgetError
+		if errors.As(err, &overBudget) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("size check failed: %w", err)
+		}
+		if !proceed {
+			fmt.Fprintln(output, "Validation cancelled by user.")
+			result.SemanticRun = false
+			result.Cancelled = true
+			return result, nil
+		}
+
+		if opts.Chunks != nil {
+			fmt.Fprintf(output, "Chunked validation: %d chunks, %d in parallel", len(opts.Chunks.Chunks), opts.chunkConcurrency())
+			if rules := outlineRuleIDs(opts.Prompts.Disabled); len(rules) > 0 {
+				fmt.Fprintf(output, ", then an outline pass for %s", strings.Join(rules, ", "))
+			}
+			fmt.Fprint(output, "\n\n")
+		}
+
+		degraded := false
+		if opts.Ultra {
+			ultra, err := RunUltraValidation(ctx, specToValidate, semanticOutput, opts)
+			if errors.Is(err, context.Canceled) {
+				return cancelled(result, output), nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("ultra validation failed: %w", err)
+			}
+			findings = ultra.Findings
+			result.DegradedRuns = ultra.DegradedRuns
+			degraded = len(ultra.DegradedRuns) > 0 || ultra.SynthesisFailed
+		} else {
+			// The reply alone is kept: a streamed attempt that failed and was retried
+			// has already been printed
+			text, err := RunSemanticValidation(ctx, specToValidate, output, opts)
+			if errors.Is(err, context.Canceled) {
+				return cancelled(result, output), nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("semantic validation failed: %w", err)
+			}
+			semanticBuf.WriteString(text)
+			findings = ParseFindings(text)
 		}
+		AssignSections(findings, sectionTitles(compiledSpec))
+
+		// Partial ultra results are not cached so the next run retries the failed calls
+		if !degraded {
+			if err := cache.Put(cacheKey, semanticBuf.String(), findings); err != nil {
+				fmt.Fprintf(output, "Warning: failed to write cache: %v\n", err)
+			}
+		}
+
+		result.Usage = NewUsageReport(meter.Calls(), meter.price, meter.priced)
+		fmt.Fprint(output, "\n"+FormatUsage(result.Usage))
 	}
 
 	fmt.Fprintln(output)
 
+	if incremental != nil {
+		carried, err := reportCachedFindings(manifestPath, incremental.Included, compiledSpec, output)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, carried...)
+	}
+	ApplySeverities(findings, opts.Severities)
+	findings = DropDisabled(findings, opts.Disabled)
+	result.Findings = findings
+
+	// A slice replaces only its own sections' recorded findings
+	recorded := findings
+	if opts.Slice != nil {
+		if record, err := LoadFindings(manifestPath); err == nil && record != nil {
+			recorded = append(CarryOverFindings(record.Findings, opts.Slice.Sections, sectionTitles(compiledSpec)), findings...)
+		}
+	}
+
+	commit, _ := differ.GetCurrentCommit(filepath.Dir(manifestPath))
+	if err := SaveFindings(manifestPath, &FindingsRecord{
+		Commit:    commit,
+		UpdatedAt: time.Now().UTC(),
+		Findings:  recorded,
+	}); err != nil {
+		fmt.Fprintf(output, "Warning: failed to record findings: %v\n", err)
+	}
+
 	return result, nil
 }
 
+// semanticInput returns the spec text sent for semantic validation: the --section or --file
+// slice, or the sections changed since --since, split into chunks (opts.Chunks) when large
+// The incremental spec is nil unless --since is set; with no changes there is nothing to send
+func semanticInput(manifestPath, compiledSpec string, opts *ValidationOptions) (string, *IncrementalSpec, error) {
+	specToValidate := compiledSpec
+	var incremental *IncrementalSpec
+	var err error
+	if opts.Slice != nil {
+		if specToValidate, err = opts.Slice.Compile(manifestPath); err != nil {
+			return "", nil, fmt.Errorf("failed to compile %s: %w", opts.Slice.Target, err)
+		}
+		if !slices.ContainsFunc(opts.Slice.Sections, isContextTitle) {
+			opts.Slice.Context = contextSection(compiledSpec)
+		}
+	} else if opts.Since != "" {
+		if incremental, err = PrepareIncremental(manifestPath, compiledSpec, opts.Since); err != nil {
+			return "", nil, err
+		}
+		if len(incremental.Changed) == 0 {
+			return "", incremental, nil
+		}
+		specToValidate = incremental.Content
+		opts.Incremental = incremental
+	}
+
+	// Large specs are split into section-aligned chunks instead of one oversized call
+	if !opts.NoChunk && opts.Slice == nil && len(specToValidate) > SizeLargeThreshold {
+		opts.Chunks = planChunks(manifestPath, specToValidate, opts.ChunkSize)
+	}
+	return specToValidate, incremental, nil
+}
+
+// planChunks splits a large spec, returning nil when it does not break into several chunks
+func planChunks(manifestPath, compiledSpec string, chunkSize int) *ChunkPlan {
+	var attrs map[string]string
+	if structure, err := parser.BuildStructure(manifestPath); err == nil {
+		attrs = structure.GetAttributeMap()
+	}
+
+	plan := BuildChunkPlan(compiledSpec, attrs, chunkSize)
+	if len(plan.Chunks) < 2 {
+		return nil
+	}
+	return plan
+}
+
+// cancelled marks a result as interrupted before semantic validation finished
+func cancelled(result *ValidationResult, output io.Writer) *ValidationResult {
+	fmt.Fprintln(output, "\nValidation cancelled.")
+	result.SemanticRun = false
+	result.Cancelled = true
+	return result
+}
+
+// reportCachedFindings prints and returns recorded findings for sections that were not re-validated
+func reportCachedFindings(manifestPath string, revalidated []string, compiledSpec string, output io.Writer) ([]Finding, error) {
+	record, err := LoadFindings(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		fmt.Fprint(output, "No cached findings for unchanged sections - run a full 'cca validate' to record a baseline.\n\n")
+		return nil, nil
+	}
+
+	carried := CarryOverFindings(record.Findings, revalidated, sectionTitles(compiledSpec))
+	fmt.Fprintf(output, "=== Cached Findings (unchanged sections, recorded %s) ===\n\n", record.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Fprint(output, FormatFindings(carried))
+	fmt.Fprintln(output)
+
+	return carried, nil
+}
+
 // ValidateQuick runs only structural checks (no Claude)
-func ValidateQuick(manifestPath string) (*ValidationResult, error) {
+func ValidateQuick(manifestPath string, schema *SpecSchema) (*ValidationResult, error) {
 	result := &ValidationResult{}
 
-	checks, err := RunStructuralChecks(manifestPath)
+	checks, err := RunStructuralChecks(manifestPath, schema)
 	if err != nil {
 		return nil, err
 	}
@@ -119,28 +332,13 @@ func FormatSummary(result *ValidationResult) string {
 	return "✗ Structural checks failed"
 }
 
-// ListRules returns all validation rules (for documentation)
+// ListRules returns all validation rules as "id: summary" (for documentation)
 func ListRules() []string {
-	return []string{
-		"exact-versions: All dependencies have exact versions",
-		"no-or-choices: No unresolved alternatives",
-		"no-conditionals: No conditional logic (if needed, optional, TBD)",
-		"no-optional: No optional sections",
-		"file-tree: Complete file structure provided",
-		"types-complete: All types fully defined",
-		"example-data: Actual examples, not just schemas",
-		"db-schema: Database schema complete",
-		"api-routes: API routes fully specified",
-		"perf-quantified: Performance has numbers, not adjectives",
-		"numeric-derivation: Constants have rationale",
-		"data-formats: Format examples with real values",
-		"error-handling: Error responses specified",
-		"concurrency: Threading model specified if needed",
-		"persistence: Storage format concrete",
-		"deployment: Deployment config complete",
-		"secrets-separated: Config/secrets properly separated",
-		"no-weak-language: No should/could/might",
+	var rules []string
+	for _, rule := range builtinRules {
+		rules = append(rules, rule.ID+": "+rule.Summary)
 	}
+	return rules
 }
 
 // GetCompiledSpec compiles and returns the spec (for external use)
@@ -150,7 +348,7 @@ func GetCompiledSpec(manifestPath string) (string, error) {
 
 // ValidateWithOutput is a convenience function that writes to stdout with default options
 func ValidateWithOutput(manifestPath string) (*ValidationResult, error) {
-	return Validate(manifestPath, os.Stdout, ValidationOptions{})
+	return Validate(context.Background(), manifestPath, os.Stdout, ValidationOptions{})
 }
 
 // ValidateToFile writes validation output to a file with default options
@@ -161,7 +359,7 @@ func ValidateToFile(manifestPath, outputPath string) (*ValidationResult, error)
 	}
 	defer f.Close()
 
-	return Validate(manifestPath, f, ValidationOptions{})
+	return Validate(context.Background(), manifestPath, f, ValidationOptions{})
 }
 
 // BaseDir returns the base directory for relative path display
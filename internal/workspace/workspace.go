@@ -0,0 +1,188 @@
+// Package workspace runs a command over every spec in a directory tree
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+// Per-spec outcomes
+const (
+	StatusPassed    = "passed"
+	StatusFindings  = "findings"
+	StatusFailed    = "failed"
+	StatusRefused   = "refused" // The estimated run exceeds the configured budget
+	StatusCancelled = "cancelled"
+)
+
+// DefaultConcurrency is how many specs are processed at once
+const DefaultConcurrency = 4
+
+// exitCancelled matches the exit code cca validate uses for a cancelled run
+const exitCancelled = 3
+
+// Result is one spec's outcome
+type Result struct {
+	Spec     string `json:"spec"`              // Manifest path relative to the search root
+	Status   string `json:"status"`            // passed, findings, failed, refused or cancelled
+	ExitCode int    `json:"exit_code"`         // What the single-spec command would have exited with
+	Summary  string `json:"summary,omitempty"` // One line for the report
+	Error    string `json:"error,omitempty"`   // Set when Status is failed
+	Data     any    `json:"result,omitempty"`  // Command-specific result, for JSON output
+	Output   string `json:"-"`                 // Text the command wrote for this spec
+}
+
+// Task processes one spec, writing its text output to w
+type Task func(ctx context.Context, spec config.SpecLocation, w io.Writer) Result
+
+// Run applies task to every spec, at most concurrency at a time
+// Results are returned in spec order regardless of completion order; specs not
+// started before ctx is cancelled are marked cancelled
+func Run(ctx context.Context, root string, specs []config.SpecLocation, concurrency int, task Task) []Result {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	results := make([]Result, len(specs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, spec := range specs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			notStarted := Result{Spec: relSpec(root, spec.Manifest), Status: StatusCancelled, ExitCode: exitCancelled, Summary: "not started"}
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = notStarted
+				return
+			}
+			if ctx.Err() != nil {
+				results[i] = notStarted
+				return
+			}
+
+			var buf bytes.Buffer
+			results[i] = task(ctx, spec, &buf)
+			results[i].Spec = relSpec(root, spec.Manifest)
+			results[i].Output = buf.String()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Failed builds the result for a spec whose command returned an error
+func Failed(err error, exitCode int) Result {
+	return Result{Status: StatusFailed, ExitCode: exitCode, Summary: firstLine(err.Error()), Error: err.Error()}
+}
+
+// ExitCode combines per-spec exit codes: the highest wins, so a run refused over
+// budget (4) outranks a cancelled one (3), then a failed one (2), then findings (1)
+func ExitCode(results []Result) int {
+	code := 0
+	for _, r := range results {
+		code = max(code, r.ExitCode)
+	}
+	return code
+}
+
+// Count returns how many results have the given status
+func Count(results []Result, status string) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Header introduces a spec's output when several specs are printed together
+func Header(spec string) string {
+	return fmt.Sprintf("==> %s <==\n", spec)
+}
+
+// FormatOutputs prints each spec's output under its header, in spec order
+func FormatOutputs(results []Result) string {
+	var sb strings.Builder
+	for i, r := range results {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(Header(r.Spec))
+		sb.WriteString(r.Output)
+		if r.Output != "" && !strings.HasSuffix(r.Output, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// FormatReport renders the per-spec status table and a combined total
+func FormatReport(results []Result) string {
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.Spec))
+	}
+
+	var sb strings.Builder
+	for _, r := range results {
+		mark := "\033[32m✓\033[0m"
+		switch r.Status {
+		case StatusFindings, StatusFailed, StatusRefused:
+			mark = "\033[31m✗\033[0m"
+		case StatusCancelled:
+			mark = "\033[33m-\033[0m"
+		}
+		fmt.Fprintf(&sb, "  %s %-*s  %-9s  %s\n", mark, width, r.Spec, r.Status, r.Summary)
+	}
+
+	var parts []string
+	for _, status := range []string{StatusPassed, StatusFindings, StatusFailed, StatusRefused, StatusCancelled} {
+		if n := Count(results, status); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	fmt.Fprintf(&sb, "\n%d spec(s): %s\n", len(results), strings.Join(parts, ", "))
+	return sb.String()
+}
+
+// FormatReportJSON renders results as a JSON array
+func FormatReportJSON(results []Result) string {
+	if results == nil {
+		results = []Result{}
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+func relSpec(root, manifest string) string {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return manifest
+	}
+	rel, err := filepath.Rel(absRoot, manifest)
+	if err != nil {
+		return manifest
+	}
+	return filepath.ToSlash(rel)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,58 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emontenegr/ClaudeCodeArchitect/internal/config"
+)
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	var specs []config.SpecLocation
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		specs = append(specs, config.SpecLocation{Dir: filepath.Join(root, name), Manifest: filepath.Join(root, name, "MANIFEST.adoc")})
+	}
+
+	results := Run(context.Background(), root, specs, 2, func(_ context.Context, spec config.SpecLocation, w io.Writer) Result {
+		name := filepath.Base(spec.Dir)
+		fmt.Fprintf(w, "checked %s", name)
+		switch name {
+		case "b":
+			return Result{Status: StatusFindings, ExitCode: 1}
+		case "d":
+			return Failed(fmt.Errorf("provider unavailable\ndetails"), 2)
+		}
+		return Result{Status: StatusPassed}
+	})
+
+	if len(results) != 5 || results[1].Spec != "b/MANIFEST.adoc" || results[3].Summary != "provider unavailable" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if code := ExitCode(results); code != 2 {
+		t.Errorf("expected combined exit code 2, got %d", code)
+	}
+	if !strings.Contains(FormatOutputs(results), "==> c/MANIFEST.adoc <==\nchecked c\n") {
+		t.Errorf("unexpected outputs:\n%s", FormatOutputs(results))
+	}
+	if report := FormatReport(results); !strings.Contains(report, "5 spec(s): 3 passed, 1 findings, 1 failed") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	specs := []config.SpecLocation{{Manifest: "/specs/a/MANIFEST.adoc"}}
+	results := Run(ctx, "/specs", specs, 1, func(context.Context, config.SpecLocation, io.Writer) Result {
+		return Result{Status: StatusPassed}
+	})
+	if results[0].Status != StatusCancelled || results[0].ExitCode != exitCancelled || results[0].Spec != "a/MANIFEST.adoc" {
+		t.Errorf("unexpected result for cancelled run: %+v", results[0])
+	}
+}